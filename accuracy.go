@@ -0,0 +1,103 @@
+package addic7ed
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// AccuracyCase is a file with the version of its right subtitle, like "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv].mkv" and "BATV"
+type AccuracyCase struct {
+	File    string
+	Version string
+}
+
+// AccuracyResult is the result of SearchBest for a case of an accuracy evaluation
+type AccuracyResult struct {
+	AccuracyCase
+	// Name is the name of the episode found on Addic7ed
+	Name string
+	// Best is the subtitle chosen by SearchBest
+	Best Subtitle
+	// Correct is set when the version of the chosen subtitle is the expected one, compared like WithVersion
+	Correct bool
+	// Err is the error of the search, if any
+	Err error
+}
+
+// AccuracyReport is the result of an accuracy evaluation, see EvaluateAccuracy
+type AccuracyReport struct {
+	// Results are the results of the cases, in order
+	Results []AccuracyResult
+	// Correct and Wrong are the numbers of cases whose best subtitle has the expected version or not
+	Correct int
+	Wrong   int
+	// Failed is the number of cases whose search failed, not counted in the accuracy
+	Failed int
+}
+
+// Accuracy returns the share of the cases whose best subtitle has the expected version, between 0 and 1, ignoring failed searches
+func (r AccuracyReport) Accuracy() float64 {
+	if r.Correct+r.Wrong == 0 {
+		return 0
+	}
+	return float64(r.Correct) / float64(r.Correct+r.Wrong)
+}
+
+func (r AccuracyReport) String() string {
+	return fmt.Sprintf("accuracy %.1f%% (%v/%v), %v failed", 100*r.Accuracy(), r.Correct, r.Correct+r.Wrong, r.Failed)
+}
+
+// EvaluateAccuracy runs SearchBest on files whose right subtitle is known, and reports how often the expected version is chosen.
+// It is meant to evaluate scoring changes on a real library before adopting them, like a custom Scorer given with WithScorer:
+// run it with a client per scorer on the same cases, and compare the reports.
+func (c *Client) EvaluateAccuracy(cases []AccuracyCase, lang string, opts ...CallOption) (AccuracyReport, error) {
+	return c.EvaluateAccuracyContext(context.Background(), cases, lang, opts...)
+}
+
+// EvaluateAccuracyContext is like EvaluateAccuracy, with a context to cancel the searches.
+// It returns the results of the cases evaluated so far and the error of the context when it's done.
+func (c *Client) EvaluateAccuracyContext(ctx context.Context, cases []AccuracyCase, lang string, opts ...CallOption) (AccuracyReport, error) {
+	report := AccuracyReport{Results: make([]AccuracyResult, 0, len(cases))}
+	for _, accuracyCase := range cases {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		result := AccuracyResult{AccuracyCase: accuracyCase}
+		result.Name, result.Best, result.Err = c.SearchBestContext(ctx, accuracyCase.File, lang, opts...)
+		switch {
+		case result.Err != nil:
+			report.Failed++
+		case WithVersion(accuracyCase.Version)(result.Best):
+			result.Correct = true
+			report.Correct++
+		default:
+			report.Wrong++
+		}
+		report.Results = append(report.Results, result)
+	}
+	return report, nil
+}
+
+// ReadAccuracyCases reads the cases of an accuracy evaluation from CSV records of a file and its expected version, like
+//
+//	Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv].mkv,BATV
+//
+// Lines starting with "#" are comments.
+func ReadAccuracyCases(r io.Reader) ([]AccuracyCase, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	cases := make([]AccuracyCase, 0, len(records))
+	for _, record := range records {
+		cases = append(cases, AccuracyCase{File: strings.TrimSpace(record[0]), Version: strings.TrimSpace(record[1])})
+	}
+	return cases, nil
+}
@@ -0,0 +1,46 @@
+package addic7ed_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+func TestEvaluateAccuracy(t *testing.T) {
+	handler := episodeHandler(t, nil)
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Query().Get("search"), "Unknown") {
+			w.Write([]byte("<html><body>Nothing found</body></html>"))
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})}}))
+
+	cases, err := addic7ed.ReadAccuracyCases(strings.NewReader(`# file, expected version
+Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv].mkv, batv
+"Shameless.US.S08E11.WEB.x264-TBS.mkv",BATV
+Unknown.Show.S01E01.mkv,LOL
+`))
+	assert.NoError(t, err)
+	assert.Len(t, cases, 3)
+
+	report, err := c.EvaluateAccuracy(cases, "English")
+	assert.NoError(t, err)
+	assert.Equal(t, 1, report.Correct)
+	assert.Equal(t, 1, report.Wrong)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, 0.5, report.Accuracy())
+	assert.Equal(t, "accuracy 50.0% (1/2), 1 failed", report.String())
+	if assert.Len(t, report.Results, 3) {
+		assert.True(t, report.Results[0].Correct)
+		assert.Equal(t, "WEB.x264-TBS", report.Results[1].Best.Version)
+		assert.Error(t, report.Results[2].Err)
+	}
+
+	_, err = addic7ed.ReadAccuracyCases(strings.NewReader("only a file\n"))
+	assert.Error(t, err)
+}
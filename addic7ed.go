@@ -1,61 +1,173 @@
 package addic7ed
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"maps"
 	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync/atomic"
+	"time"
 	"unicode"
 
 	"github.com/PuerkitoBio/goquery"
-	textdistance "github.com/masatana/go-textdistance"
 )
 
 const userAgent = "Mozilla/5.0 (X11; Linux x86_64; rv:12.0) Gecko/20100101 Firefox/12.0"
 
 // Client is the addic7ed client
+// Searches and downloads are safe for concurrent use. Settings (Debug, SetLogLevel, ReadOnly...) should be set before using the client.
 type Client struct {
-	// doc is the indexed document, representing the page
-	doc   *goquery.Document
-	debug bool
+	level             LogLevel
+	logger            *slog.Logger
+	readOnly          bool
+	acceptedMIMETypes []string
+	acceptedFormats   []Format
+	httpClient        *http.Client
+	episodeMappings   map[string]EpisodeMapping
+	headers           map[string]string
+	cache             *showCache
+	cacheLimits       cacheLimits
+	retry             RetryPolicy
+	rand              *lockedRand
+	cookieFile        string
+	limiter           *rateLimiter
+	peakLimiters      []peakLimiter
+	showPeakLimiters  map[string][]peakLimiter
+	scoreMargins      *scoreMarginStats
+	baseURL           string
+	scorer            Scorer
+	toUTF8            bool
+	encodings         map[string]Charset
+	rtlMarks          bool
+	ladder            Ladder
+	showLadders       map[string]Ladder
+	resolver          ShowResolver
+	aliases           map[string]string
+	prefetching       chan struct{}
+	warnOnMismatch    bool
+
+	// searches and downloads count the requests sent to Addic7ed, see Usage
+	searches  int64
+	downloads int64
+}
+
+// Usage is the accounting of the requests sent to Addic7ed by a client
+type Usage struct {
+	// Searches is the number of pages fetched to search for shows and subtitles
+	Searches int
+	// Downloads is the number of subtitles and season packs served by Addic7ed, errors and pages of exceeded quota
+	// excluded. Addic7ed limits the number of downloads per day
+	Downloads int
 }
 
 // New creates an Addic7ed client, ready to interact with.
-func New() *Client {
-	return &Client{}
+// Options can be given to configure the client, for example New(WithHTTPClient(&http.Client{Timeout: 10 * time.Second}))
+func New(opts ...Option) *Client {
+	c := &Client{
+		httpClient:   &http.Client{},
+		baseURL:      DefaultBaseURL,
+		scorer:       JaroWinklerScorer{},
+		ladder:       DefaultLadder,
+		retry:        DefaultRetryPolicy,
+		limiter:      newRateLimiter(DefaultRateLimit),
+		scoreMargins: &scoreMarginStats{},
+		cacheLimits:  cacheLimits{maxEntries: DefaultCacheEntries, maxSize: DefaultCacheSize},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.cookieFile != "" {
+		c.useCookieFile()
+	}
+	return c
 }
 
 // NewVerbose creates a new client that will log verbosely to stdout
-func NewVerbose() *Client {
-	return &Client{
-		debug: true,
-	}
+func NewVerbose(opts ...Option) *Client {
+	c := New(opts...)
+	c.level = LevelTrace
+	return c
 }
 
 // Debug is used to set logging to verbose
+// Verbose logging includes the very detailed trace of the scoring, use SetLogLevel for less verbose logs
 func (c *Client) Debug(isVerbose bool) {
-	c.debug = isVerbose
+	if isVerbose {
+		c.level = LevelTrace
+	} else {
+		c.level = LevelOff
+	}
 }
 
-func (c *Client) logf(message string, params ...interface{}) {
-	if c.debug {
-		fmt.Printf(message+"\n", params...)
+// WithAcceptedMIMETypes restricts the MIME types of the files that can be downloaded at creation, see AcceptMIMETypes
+func WithAcceptedMIMETypes(types ...string) Option {
+	return func(c *Client) {
+		c.acceptedMIMETypes = types
 	}
 }
 
-func (c *Client) log(message string, params ...interface{}) {
-	if c.debug {
-		fmt.Println(message)
+// WithAcceptedFormats restricts the formats of the subtitles that can be downloaded at creation, see AcceptFormats
+func WithAcceptedFormats(formats ...Format) Option {
+	return func(c *Client) {
+		c.acceptedFormats = formats
+	}
+}
+
+// WithReadOnly sets the client in read-only mode at creation, see ReadOnly
+func WithReadOnly() Option {
+	return func(c *Client) {
+		c.readOnly = true
+	}
+}
+
+// AcceptMIMETypes is used to restrict the MIME types of the files that can be downloaded, like "text/plain" or "application/x-subrip"
+// Downloading a subtitle served with another Content-Type returns ErrUnacceptableContent. All types are accepted by default.
+// Call it before using the client, or use WithAcceptedMIMETypes.
+func (c *Client) AcceptMIMETypes(types ...string) {
+	c.acceptedMIMETypes = types
+}
+
+// AcceptFormats is used to restrict the formats of the subtitles that can be downloaded, like FormatSRT
+// Downloading a subtitle of another format, as detected from its content, returns ErrUnacceptableContent. All formats are accepted by default.
+// Call it before using the client, or use WithAcceptedFormats.
+func (c *Client) AcceptFormats(formats ...Format) {
+	c.acceptedFormats = formats
+}
+
+// ReadOnly is used to forbid downloads.
+// In read-only mode, searches still work but downloading a subtitle returns ErrReadOnly.
+// Call it before using the client, or use WithReadOnly.
+func (c *Client) ReadOnly(isReadOnly bool) {
+	c.readOnly = isReadOnly
+}
+
+// Usage returns the number of searches and downloads done by the client since its creation.
+// Only downloads of subtitles found by this client are accounted.
+// The counts are kept in memory and are not persisted: they start at zero with each client, and do not include the downloads
+// of other processes sharing the quota, nor of earlier runs of the same day. Plan with the *DownloadLimitError of
+// ErrDownloadLimitExceeded, telling when downloads are allowed again, rather than with a remaining quota.
+func (c *Client) Usage() Usage {
+	return Usage{
+		Searches:  int(atomic.LoadInt64(&c.searches)),
+		Downloads: int(atomic.LoadInt64(&c.downloads)),
 	}
 }
 
-func (c *Client) findShowName() (string, error) {
+func (c *call) findShowName(doc *goquery.Document) (string, error) {
 	var show string
-	c.log("Searching for show name in current page...")
-	c.doc.Find(".titulo").Contents().EachWithBreak(func(i int, s *goquery.Selection) bool {
+	c.tracef("Searching for show name in current page...")
+	doc.Find(".titulo").Contents().EachWithBreak(func(i int, s *goquery.Selection) bool {
 		if !s.Is("small") {
 			show = strings.TrimSpace(s.Text())
 			return false
@@ -63,286 +175,628 @@ func (c *Client) findShowName() (string, error) {
 		return true
 	})
 	if show == "" {
-		c.log("Show name is not found in current indexed page")
-		return "", errors.New("not found")
+		c.tracef("Show name is not found in current indexed page")
+		return "", newError(CodeParseFailure, nil, "show name not found in page")
 	}
-	c.logf("Show name is: %v", show)
+	c.tracef("Show name is: %v", show)
 	return show, nil
 }
 
-func (c *Client) findResults() []string {
-	results := []string{}
-	c.doc.Find(".tabel").Each(func(i int, s *goquery.Selection) {
+func (c *call) findResults(doc *goquery.Document) []searchResult {
+	results := []searchResult{}
+	doc.Find(".tabel").Each(func(i int, s *goquery.Selection) {
 		s.Find("a").Each(func(j int, ss *goquery.Selection) {
 			if url, ok := ss.Attr("href"); ok {
-				results = append(results, url)
+				results = append(results, searchResult{link: url, title: strings.TrimSpace(ss.Text())})
 			}
 		})
 	})
 	return results
 }
 
-func createDocFromURL(url string) (*goquery.Document, error) {
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", url, nil)
+func (c *call) createDocFromURL(url string) (*goquery.Document, error) {
+	// With a cache, pages are revalidated with Addic7ed rather than downloaded again, see cachedPage
+	header := http.Header{}
+	var cached *cachedPage
+	if c.cache != nil {
+		cached = c.cache.page(url)
+	}
+	if cached != nil {
+		if cached.etag != "" {
+			header.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+	resp, err := c.get(url, header)
 	if err != nil {
 		return nil, err
 	}
-	// Avoid getting cached pages
-	req.Header.Add("Cache-Control", "no-cache")
-	req.Header.Add("User-Agent", userAgent)
+	defer resp.Body.Close()
 
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("Unable to reach addic7ed server: %v", err)
+	var body io.Reader = resp.Body
+	cacheable := c.cache != nil && (resp.Header.Get("ETag") != "" || resp.Header.Get("Last-Modified") != "")
+	switch {
+	case resp.StatusCode == http.StatusNotModified:
+		c.tracef("Page %v not modified, served from cache", url)
+		body = bytes.NewReader(cached.body)
+	case cacheable || c.bestEffort:
+		data, err := io.ReadAll(resp.Body)
+		switch {
+		case err != nil && c.cutShort() && len(data) > 0:
+			// HTML is parsed even when cut, so the beginning of the page is still read
+			c.partialf("page %v was cut after %v bytes: %v", url, len(data), err)
+		case err != nil:
+			return nil, newError(CodeParseFailure, err, "Unable to construct document from server response")
+		case cacheable:
+			c.cache.storePage(url, &cachedPage{body: data, etag: resp.Header.Get("ETag"), lastModified: resp.Header.Get("Last-Modified")})
+		}
+		body = bytes.NewReader(data)
 	}
-	defer resp.Body.Close()
 
 	// We use goquery to fetch the page from Addic7ed in way that we can find data quickly like the JQuery way
-	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	doc, err := goquery.NewDocumentFromReader(body)
 	if err != nil {
-		return nil, fmt.Errorf("Unable to construct document from server response: %v", err)
+		return nil, newError(CodeParseFailure, err, "Unable to construct document from server response")
+	}
+	// The URL of the page is kept, after redirections, so that subtitles can be refreshed from their page, see Subtitle.Refresh
+	if resp.Request != nil {
+		doc.Url = resp.Request.URL
+	} else {
+		doc.Url = parseURL(url)
 	}
 
 	return doc, nil
 }
 
+// get gets a page of Addic7ed website, counted as a search, see Usage
+// The header is added to the request. When it makes the request conditional, a 304 Not Modified response is returned as is.
+// The body of the response must be closed by the caller
+func (c *call) get(url string, header http.Header) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(c.ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Avoid getting cached pages
+	req.Header.Add("Cache-Control", "no-cache")
+	req.Header.Add("User-Agent", userAgent)
+	c.setHeaders(req)
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := c.do(req, c.show, func(reason string, delay time.Duration) {
+		c.warnf("Request to %v failed with %v, retrying in %v", url, reason, delay)
+	})
+	if err != nil {
+		c.errorf("Unable to reach addic7ed server: %v", err)
+		return nil, newError(CodeServerUnreachable, err, "Unable to reach addic7ed server")
+	}
+	atomic.AddInt64(&c.searches, 1)
+	conditional := header.Get("If-None-Match") != "" || header.Get("If-Modified-Since") != ""
+	if resp.StatusCode == http.StatusNotModified && conditional {
+		return resp, nil
+	}
+	if err := checkStatus(resp.StatusCode); err != nil {
+		resp.Body.Close()
+		c.errorf("Addic7ed server answered to %v with status %v", url, resp.StatusCode)
+		return nil, err
+	}
+	return resp, nil
+}
+
 // fetchShowPage get the addic7ed show page from Addic7ed website
 // It uses search function of the website to get the page
 // Return an error if the page is not found
 // If more than one result is returned, we get the first one to match
-func (c *Client) fetchShowPage(fileName string) (string, error) {
+// It returns the name of the show and the page of the show
+func (c *call) fetchShowPage(fileName string) (string, *goquery.Document, error) {
+	show, doc, err := c.searchRelease(fileName)
+	if !errors.Is(err, ErrShowNotFound) {
+		return show, doc, err
+	}
+	alias, ok := c.aliasSearch(fileName)
+	if !ok {
+		return show, doc, err
+	}
+	c.infof("Show of %v not found, searching its alias %v", fileName, alias)
+	return c.searchShowPage(alias)
+}
+
+// searchRelease searches the show page of a release, by its show and episode first
+func (c *call) searchRelease(fileName string) (string, *goquery.Document, error) {
+	// Release names contain a lot of tags that confuse the search of Addic7ed, so the show and the episode are searched first
+	release := ParseRelease(fileName)
+	if !release.HasEpisode() {
+		return c.searchShowPage(fileName)
+	}
+	if resolved, ok := c.resolveRelease(release); ok {
+		c.infof("Searching episode %v of release %v", resolved.Query(), fileName)
+		show, doc, err := c.searchShowPage(resolved.Query())
+		if !errors.Is(err, ErrShowNotFound) {
+			return show, doc, err
+		}
+		c.infof("Episode %v not found, searching the release as is", resolved.Query())
+	}
+	if release.Query() != fileName {
+		c.infof("Searching episode %v of release %v", release.Query(), fileName)
+		show, doc, err := c.searchShowPage(release.Query())
+		if !errors.Is(err, ErrShowNotFound) {
+			return show, doc, err
+		}
+		c.infof("Episode %v not found, searching %v as is", release.Query(), fileName)
+	}
+	return c.searchShowPage(fileName)
+}
 
-	c.log("Searching show using addic7ed search page...")
-	doc, err := createDocFromURL(fmt.Sprintf("http://www.addic7ed.com/srch.php?search=%v&Submit=Search", url.QueryEscape(fileName)))
+// searchShowPage get the addic7ed show page from the search page of Addic7ed website
+func (c *call) searchShowPage(fileName string) (string, *goquery.Document, error) {
+	if mapped := c.mapEpisode(fileName); mapped != fileName {
+		c.infof("Episode is mapped, searching %v instead of %v", mapped, fileName)
+		fileName = mapped
+	}
+
+	c.tracef("Searching show using addic7ed search page...")
+	doc, err := c.createDocFromURL(c.url(fmt.Sprintf("srch.php?search=%v&Submit=Search", url.QueryEscape(fileName))))
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
-	c.doc = doc
-	c.log("Addic7ed is up and we found a page")
+	c.tracef("Addic7ed is up and we found a page")
 
-	show, err := c.findShowName()
+	show, err := c.findShowName(doc)
 	if err != nil {
-		c.log("Current page is not a show page, trying to find what is it...")
+		c.infof("Current page is not a show page, trying to find what is it...")
 		// Addic7ed did not find the page of the show from the search feature
-		results := c.findResults()
+		results := c.findResults(doc)
 		if len(results) == 0 {
-			c.log("Current page is not a result page either. We don't know what it is.")
-			return "", fmt.Errorf("show not found for filename %v", fileName)
+			c.warnf("Current page is not a result page either. We don't know what it is.")
+			return "", nil, newError(CodeShowNotFound, nil, "show not found for filename %v", fileName)
 		}
-		// If more result, we get the first result
-		c.logf("Current page is a results page containing %v resuts. It means the input filename matches with multiple shows.", len(results))
-		c.log("Getting show page from first result...")
-		doc, err := createDocFromURL("http://www.addic7ed.com/" + results[0])
+		// If more result, we get the one matching the hints of the filename, or the first result
+		c.infof("Current page is a results page containing %v resuts. It means the input filename matches with multiple shows.", len(results))
+		result := c.pickResult(results, fileName)
+		c.tracef("Getting show page from result %v...", result.title)
+		doc, err = c.createDocFromURL(c.url(result.link))
 		if err != nil {
-			return "", err
+			return "", nil, err
 		}
-		c.log("We found a show page from first result")
-		c.doc = doc
-		show, err = c.findShowName()
+		c.tracef("We found a show page from result")
+		show, err = c.findShowName(doc)
 		if err != nil {
-			return "", err
+			return "", nil, err
 		}
 	}
-	c.logf("Current page is a show page: %v", show)
-	return show, nil
+	c.infof("Current page is a show page: %v", show)
+	return show, doc, nil
 }
 
-// cleanTitle cleans the title of useless words.
+// CleanVersion cleans the title of a version of useless words.
 // Title are usually of the format "Version BATV, 0.00 MBs", and we want to keep only "BATV"
-func cleanTitle(title string) string {
-	parts := strings.Split(title, ",")
-	clean := title
-	if len(parts) >= 0 {
-		clean = parts[0]
-	}
-	parts = strings.Fields(clean)
+// It never panics, whatever the input, and returns an empty string if the title does not contain any word.
+func CleanVersion(title string) string {
+	parts := strings.Fields(strings.Split(title, ",")[0])
 	if len(parts) >= 2 {
-		clean = parts[1]
+		return parts[1]
 	} else if len(parts) == 1 {
-		clean = parts[0]
+		return parts[0]
 	}
-	return clean
+	return ""
 }
 
-// Parse the string to find words
+// Words parses the string to find words, as used to compare filenames and versions
 // The filename is split in words. A word is a a sequence of letters or numbers.
 // Every other character is a separator (space, dots, plus, minus...)
-func wordsFromString(s string) []string {
-	return strings.FieldsFunc(s, func(c rune) bool {
+// Words are also split where they switch between Chinese, Japanese or Korean and another script, like "Shameless第八季".
+// It never panics, whatever the input. Invalid UTF-8 sequences are separators.
+func Words(s string) []string {
+	words := []string{}
+	for _, word := range strings.FieldsFunc(s, func(c rune) bool {
 		return !unicode.IsLetter(c) && !unicode.IsNumber(c)
-	})
+	}) {
+		words = append(words, splitScripts(word)...)
+	}
+	return words
 }
 
-// scoreBestSubVersions give score to subtitles versions
-// It searches for similarities in both the filename and the version
-// filename and versions are indexed by word, and the more there are common words, the more the version gets a good score
-// Similarity is computed from a scoring between word exact matching and word distance (with Jaro/Winkler distance algorithm)
-func (c *Client) scoreBestSubVersions(fileName string, subtitlesByVersion map[string]Subtitles) map[string]float64 {
-	const weightWhenExactMatch = 10
-	wordsFromTitle := wordsFromString(fileName)
+// scoreBestSubVersions give score to subtitles versions, with the scorer of the client (see WithScorer)
+func (c *call) scoreBestSubVersions(fileName string, subtitlesByVersion map[string]Subtitles) map[string]float64 {
 	scores := map[string]float64{}
-	c.logf("Computing scores for file %v...", fileName)
-	for version := range subtitlesByVersion {
-		versionWords := wordsFromString(version)
-		exactMatchs := 0.0
-		var similarityScore float64
-		for _, subWordFromTitle := range wordsFromTitle {
-			for _, subWordFromVersion := range versionWords {
-				// Similarity is a float computed from Jaro/Winkler distance
-				// 0 = no similarity at all, 1 = exact same string
-				distanceScore := textdistance.JaroWinklerDistance(strings.ToLower(subWordFromVersion), strings.ToLower(subWordFromTitle))
-				if distanceScore > 0.9 {
-					exactMatchs += distanceScore
-				}
-				similarityScore += distanceScore
-
-				c.logf("--- Comparison: %v (version '%v' compared to '%v') - exact-matchs=%v => distance=%v",
-					version, subWordFromVersion, subWordFromTitle, exactMatchs, distanceScore)
-			}
+	c.tracef("Computing scores for file %v...", fileName)
+	// Versions are scored in order, so that traces are the same between two runs
+	for _, version := range slices.Sorted(maps.Keys(subtitlesByVersion)) {
+		scorer, ok := c.scorer.(WordScorer)
+		if jaroWinkler, isJaroWinkler := c.scorer.(JaroWinklerScorer); isJaroWinkler {
+			scorer, ok = jaroWinkler.wordScorer(), true
+		}
+		if ok {
+			// Word scorers, like the default scorer, trace their computations
+			scores[version] = scorer.score(fileName, version, c.tracef)
+			continue
 		}
-		searchCardinality := float64(len(versionWords) * len(wordsFromTitle)) // Number of comparisons
-		c.logf("== Search cardinality = (words in Version=%v)x(words in Filename=%v) = %v",
-			len(versionWords), len(wordsFromTitle), searchCardinality)
-		// Will lower the similarity score if there were a lot of word to compare
-		computedSimilarityScore := similarityScore / searchCardinality
-		c.logf("== Computed similarity = (similarity=%v)/(searchCardinality=%v) = %v",
-			similarityScore, searchCardinality, computedSimilarityScore,
-		)
-
-		// By multiplying by the number of matches, we ensure that a version with 3 exact matches is better than a version with 2 exact matches.
-		proportionExactMatchs := (exactMatchs) / float64(len(versionWords)) // Will tend to 1 (1 = all words in version are contained in filename)
-		exactMatchScore := float64(proportionExactMatchs * (exactMatchs * weightWhenExactMatch))
-		c.logf("== Exact match score =  (proportionOfExactMatchs=%v)x(exactMatch=%v)x(weigth=%v) = %v",
-			proportionExactMatchs, exactMatchs, weightWhenExactMatch, exactMatchScore,
-		)
-
-		scores[version] = computedSimilarityScore + exactMatchScore
-		c.log("=============================================================================")
-		c.logf("===> TOTAL SCORE FILE=%v VERSION=%v = (Computed similarity=%v)+(Exact match score=%v)=%v <===",
-			fileName, version, computedSimilarityScore, exactMatchScore, scores[version],
-		)
-		c.log("=============================================================================")
+		scores[version] = c.scorer.Score(fileName, version)
+		c.tracef("===> TOTAL SCORE FILE=%v VERSION=%v = %v <===", fileName, version, scores[version])
 	}
-
 	return scores
 }
 
 // findBestSubtitleFromScores returns the best suitable subtitle from the given scores
+// Versions with the same score are ordered by name, so that the same input data always gives the same subtitle
 func findBestSubtitleFromScores(scores map[string]float64, subtitlesByVersion map[string]Subtitles) (Subtitle, float64) {
-	// Get best version from score
 	var bestScore float64
 	var bestVersion string
-	for version, score := range scores {
-		if score > bestScore {
+	for _, version := range slices.Sorted(maps.Keys(subtitlesByVersion)) {
+		if score := scores[version]; bestVersion == "" || score > bestScore {
 			bestVersion = version
 			bestScore = score
 		}
 	}
 
-	// Unable to get the best version from scores, so we get the first to come ¯\_(ツ)_/¯
-	if bestVersion == "" {
-		// As Go randomizes maps, the "first to come" version may be different between two runs with same input data
-		for version := range subtitlesByVersion {
-			bestVersion = version
-			break
-		}
-	}
-
-	bestSubs := subtitlesByVersion[bestVersion]
+	return bestOfVersion(subtitlesByVersion[bestVersion]), bestScore
+}
 
-	// From a given version, keep the best subtitle
-	// Addic7ed authorizes multiple subtitle of the same version, so we get the most updated one
+// bestOfVersion returns the best subtitle of a version
+// Addic7ed authorizes multiple subtitle of the same version, so we get the most updated one
+func bestOfVersion(subtitles Subtitles) Subtitle {
 	var bestSub Subtitle
-	for _, sub := range bestSubs {
+	for _, sub := range subtitles {
 		if sub.IsUpdated() {
-			bestSub = sub
-			break
+			return sub
 		}
 		bestSub = sub
 	}
-	return bestSub, bestScore
+	return bestSub
 }
 
 // SearchBest searches in the Addic7ed website for the best suitable subtitle of given episode of a show
 // showStr is usually the name of the video file that need to be searched but it could be any search that can be handled by Addic7ed website
-// lang is the language of the subtitle
+// lang is the language of the subtitle, by name like "French" or by ISO 639-1 code like "fr", see WithLanguage
 // It returns the episode name and the found subtitle.
-func (c *Client) SearchBest(showStr, lang string) (string, Subtitle, error) {
-	show, err := c.SearchAll(showStr)
+func (c *Client) SearchBest(showStr, lang string, opts ...CallOption) (string, Subtitle, error) {
+	return c.SearchBestContext(context.Background(), showStr, lang, opts...)
+}
+
+// SearchBestContext is like SearchBest, with a context to cancel the search
+func (c *Client) SearchBestContext(ctx context.Context, showStr, lang string, opts ...CallOption) (string, Subtitle, error) {
+	return c.newCall(ctx, opts).searchBest(showStr, lang)
+}
+
+func (c *call) searchBest(showStr, lang string) (string, Subtitle, error) {
+	show, err := c.searchAll(showStr)
+	if err != nil {
+		return "", Subtitle{}, err
+	}
+	return c.bestOfShow(showStr, lang, show)
+}
+
+// SearchBestWithFallback is like SearchBest, trying languages in order of priority, like "fr" then "en".
+// It returns the best subtitle of the first language having any. The episode is fetched once for all languages.
+func (c *Client) SearchBestWithFallback(showStr string, langs []string, opts ...CallOption) (string, Subtitle, error) {
+	return c.SearchBestWithFallbackContext(context.Background(), showStr, langs, opts...)
+}
+
+// SearchBestWithFallbackContext is like SearchBestWithFallback, with a context to cancel the search
+func (c *Client) SearchBestWithFallbackContext(ctx context.Context, showStr string, langs []string, opts ...CallOption) (string, Subtitle, error) {
+	call := c.newCall(ctx, opts)
+	show, err := call.searchAll(showStr)
 	if err != nil {
 		return "", Subtitle{}, err
 	}
+	for _, lang := range langs {
+		name, subtitle, err := call.bestOfShow(showStr, lang, show)
+		if !errors.Is(err, ErrNoSubtitlesForLanguage) {
+			return name, subtitle, err
+		}
+		call.infof("No subtitles found for lang %v, trying the next language", lang)
+	}
+	return "", Subtitle{}, newError(CodeNoSubtitlesForLanguage, nil, "Unable to find any subtitles for show %q in %q. Check available languages on Addic7ed website and retry", show.Name, strings.Join(langs, ", "))
+}
+
+// SearchBestMulti is like SearchBest for several languages at once, like the original and the translated subtitles of an episode.
+// It returns the best subtitle of each language having any, keyed by language as given. The episode is fetched once for all languages.
+// Languages without subtitles are missing from the map, and the error is ErrNoSubtitlesForLanguage only when no language has any.
+func (c *Client) SearchBestMulti(showStr string, langs []string, opts ...CallOption) (string, map[string]Subtitle, error) {
+	return c.SearchBestMultiContext(context.Background(), showStr, langs, opts...)
+}
+
+// SearchBestMultiContext is like SearchBestMulti, with a context to cancel the search
+func (c *Client) SearchBestMultiContext(ctx context.Context, showStr string, langs []string, opts ...CallOption) (string, map[string]Subtitle, error) {
+	call := c.newCall(ctx, opts)
+	show, err := call.searchAll(showStr)
+	if err != nil {
+		return "", nil, err
+	}
+	best := map[string]Subtitle{}
+	for _, lang := range langs {
+		_, subtitle, err := call.bestOfShow(showStr, lang, show)
+		if errors.Is(err, ErrNoSubtitlesForLanguage) {
+			call.infof("No subtitles found for lang %v", lang)
+			continue
+		}
+		if err != nil {
+			return "", nil, err
+		}
+		best[lang] = subtitle
+	}
+	if len(best) == 0 {
+		return "", best, newError(CodeNoSubtitlesForLanguage, nil, "Unable to find any subtitles for show %q in %q. Check available languages on Addic7ed website and retry", show.Name, strings.Join(langs, ", "))
+	}
+	return show.Name, best, nil
+}
+
+// bestOfShow finds the best subtitle of an episode for a search, in a given language
+func (c *call) bestOfShow(showStr, lang string, show Show) (string, Subtitle, error) {
 	subsWithLang := show.Subtitles.Filter(WithLanguage(lang))
 	if len(subsWithLang) == 0 {
-		return "", Subtitle{}, fmt.Errorf("Unable to find any subtitles for show %q in %q. Check available languages on Addic7ed website and retry", show.Name, lang)
+		for _, translation := range show.Translations {
+			if WithLanguage(lang)(Subtitle{Language: translation.Language}) {
+				c.warn(WarningTranslationInProgress, "%v", translation)
+			}
+		}
+		return "", Subtitle{}, newError(CodeNoSubtitlesForLanguage, nil, "Unable to find any subtitles for show %q in %q. Check available languages on Addic7ed website and retry", show.Name, lang)
 	}
 
 	if len(subsWithLang) == 1 {
-		c.logf("Only one subtitle found for lang %v", subsWithLang[0])
+		c.infof("Only one subtitle found for lang %v", subsWithLang[0])
+		c.checkCompletion(subsWithLang[0])
 		return show.Name, subsWithLang[0], nil
 	}
 
 	subsByVersion := subsWithLang.GroupByVersion()
 
 	// Score the different version to find best suitable one
-	c.logf("Found %v different versions of subtitles, trying to find the best one...", len(subsByVersion))
+	c.infof("Found %v different versions of subtitles, trying to find the best one...", len(subsByVersion))
 	scores := c.scoreBestSubVersions(showStr, subsByVersion)
-	if c.debug {
-		c.log("Scores are:")
-		for k, v := range scores {
-			c.logf(" - Version: %v => Score: %v", k, v)
+	if c.level >= LevelInfo {
+		c.infof("Scores are:")
+		for _, version := range slices.Sorted(maps.Keys(scores)) {
+			c.infof(" - Version: %v => Score: %v", version, scores[version])
 		}
 	}
 
+	if margin, ok := scoreMargin(scores); ok {
+		c.scoreMargins.record(margin)
+	}
+
 	// From the scores, find the best subtitle possible
 	bestSub, bestScore := findBestSubtitleFromScores(scores, subsByVersion)
-	c.logf("=> Best sub: %v (%v) with score %v", bestSub.Version, bestSub.Link, bestScore)
+	c.infof("=> Best sub: %v (%v) with score %v", bestSub.Version, bestSub.Link, bestScore)
+	if bestScore == 0 {
+		c.warn(WarningNoMatchingVersion, "no version matches %v, picked version %v", showStr, bestSub.Version)
+	}
+	c.checkCompletion(bestSub)
 
 	return show.Name, bestSub, nil
 }
 
+// checkCompletion raises a WarningIncompleteSubtitle if the picked subtitle is not completely translated yet
+func (c *call) checkCompletion(subtitle Subtitle) {
+	if !subtitle.IsCompleted() {
+		c.warn(WarningIncompleteSubtitle, "picked subtitle %v of version %v is only %v%% translated", subtitle.Link, subtitle.Version, subtitle.Completion)
+	}
+}
+
 // SearchAll searches in the Addic7ed website for a given episode of a show
 // showStr is usually the name of the video file that need to be searched but it could be any search that can be handled by Addic7ed website
 // It returns the episode name and all found subtitles.
-func (c *Client) SearchAll(showStr string) (Show, error) {
-	showName, err := c.fetchShowPage(showStr)
-	if err != nil {
-		return Show{}, err
+// If the page of the episode exists but does not have any subtitle yet, the show is returned along with ErrNoSubtitlesYet
+// With WithBestEffort, the subtitles parsed before the deadline are returned, flagged Partial.
+func (c *Client) SearchAll(showStr string, opts ...CallOption) (Show, error) {
+	return c.SearchAllContext(context.Background(), showStr, opts...)
+}
+
+// SearchAllContext is like SearchAll, with a context to cancel the search
+func (c *Client) SearchAllContext(ctx context.Context, showStr string, opts ...CallOption) (Show, error) {
+	return c.newCall(ctx, opts).searchAll(showStr)
+}
+
+func (c *call) searchAll(showStr string) (Show, error) {
+	show, err := c.cachedShow(searchKey(showStr), func(c *call) (Show, error) {
+		if show, ok := c.prefetched(showStr); ok {
+			c.tracef("Episode %v served from prefetched episode", showStr)
+			return show, nil
+		}
+		if show, ok := c.warmedEpisode(showStr); ok {
+			c.tracef("Episode %v served from warmed season", showStr)
+			return show, nil
+		}
+		return fetchSearch(showStr)(c)
+	})
+	if err == nil {
+		c.prefetchNext(showStr)
 	}
+	return show, c.checkFoundEpisode(showStr, &show, err)
+}
+
+// searchKey is the key of the episode found by a search in the cache
+func searchKey(showStr string) string {
+	return "search:" + showStr
+}
+
+// fetchSearch returns the function searching an episode, with all its subtitles
+func fetchSearch(showStr string) func(c *call) (Show, error) {
+	return func(c *call) (Show, error) {
+		c.show = normalizeShowName(ParseRelease(showStr).Title)
+		showName, doc, err := c.fetchShowPage(showStr)
+		if err != nil {
+			return Show{}, err
+		}
+		return c.showFromPage(showName, doc)
+	}
+}
+
+// showFromPage builds the show from the page of an episode, finding all its subtitles
+func (c *call) showFromPage(showName string, doc *goquery.Document) (Show, error) {
 	subtitles := Subtitles{}
+	err := c.parseAllSubtitles(doc, func(subtitle Subtitle) bool {
+		subtitles = append(subtitles, subtitle)
+		return true
+	})
+	// The subtitles of the pages already parsed are kept when a page with new versions can't be fetched, like for SearchAllSeq
+	missingVersions := err != nil
+	if missingVersions {
+		if c.ctx.Err() != nil {
+			return Show{}, err
+		}
+		c.warn(WarningMissingVersions, "unable to fetch all versions of %v: %v", showName, err)
+	}
+
+	show := Show{
+		Name:         showName,
+		Subtitles:    subtitles,
+		Warnings:     c.warnings,
+		Translations: c.parseTranslations(doc),
+		Partial:      c.partial,
+
+		missingVersions: missingVersions,
+	}
+	show.Number, show.Title = parseEpisodeName(showName, documentURL(doc))
+	show.showID, show.showName, _ = findShowLink(doc)
+	if len(subtitles) == 0 && c.partial {
+		return Show{}, newError(CodeServerUnreachable, c.ctx.Err(), "No subtitle of %v was parsed in time", showName)
+	}
+	if len(subtitles) == 0 {
+		c.warnf("Show page %v does not have any subtitle yet", showName)
+		return show, ErrNoSubtitlesYet
+	}
+
+	return show, nil
+}
+
+// findNewVersionsPages finds the links to the pages listing more versions of the episode.
+// Addic7ed sometimes splits the versions of an episode, announcing "New versions available" with a link to the other versions
+func (c *call) findNewVersionsPages(doc *goquery.Document) []string {
+	pages := []string{}
+	doc.Find("a").Each(func(i int, s *goquery.Selection) {
+		if !strings.Contains(strings.ToLower(s.Text()), "new versions") {
+			return
+		}
+		if href, ok := s.Attr("href"); ok && href != "" && !strings.HasPrefix(href, "#") {
+			pages = append(pages, strings.TrimPrefix(href, "/"))
+		}
+	})
+	return pages
+}
+
+// parseAllSubtitles finds all subtitles of a show page, including the ones listed in "New versions available" pages
+// Subtitles are given one by one to yield, and parsing stops as soon as yield returns false.
+// It returns the first error that occurred while fetching the other pages.
+func (c *call) parseAllSubtitles(doc *goquery.Document, yield func(Subtitle) bool) error {
+	seen := map[string]bool{}
+	keepGoing := true
+	dedupYield := func(s Subtitle) bool {
+		if seen[s.ID()] {
+			return true
+		}
+		seen[s.ID()] = true
+		keepGoing = yield(s)
+		return keepGoing
+	}
+	c.parseSubtitles(doc, dedupYield)
+	for _, page := range c.findNewVersionsPages(doc) {
+		if !keepGoing {
+			return nil
+		}
+		c.infof("Found a page with new versions: %v", page)
+		versionsDoc, err := c.createDocFromURL(c.url(page))
+		if err != nil && c.cutShort() {
+			c.partialf("page %v with new versions was not fetched: %v", page, err)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		c.parseSubtitles(versionsDoc, dedupYield)
+	}
+	return nil
+}
 
+// parseSubtitles finds all subtitles of a show page and gives them one by one to yield
+// Parsing stops as soon as yield returns false
+func (c *call) parseSubtitles(doc *goquery.Document, yield func(Subtitle) bool) {
+	page := documentURL(doc)
 	// Search for all HTML table with Addic7ed class tabel95
-	c.doc.Find(".tabel95").Each(func(i int, s *goquery.Selection) {
+	doc.Find(".tabel95").EachWithBreak(func(i int, s *goquery.Selection) bool {
 		// Filter only table corresponding to a subtitle version
-		if v, ok := s.Attr("align"); ok && v == "center" {
-			// Fin the
-			title := strings.TrimSpace(s.Find(".NewsTitle").Text())
-			s.Find(".language").Each(func(j int, ss *goquery.Selection) {
-				language := ss.Text()
-				ss.Parent().Find(".buttonDownload").Each(func(k int, sss *goquery.Selection) {
-					if val, ok := sss.Attr("href"); ok {
-						link := "http://www.addic7ed.com" + val
-
-						version := cleanTitle(title)
-						subtitle := Subtitle{
-							Version:  version,
-							Language: strings.TrimSpace(language),
-							Link:     strings.TrimSpace(link),
-						}
-						subtitles = append(subtitles, subtitle)
-					}
-				})
-			})
+		if v, ok := s.Attr("align"); !ok || v != "center" {
+			return true
 		}
+		title := strings.TrimSpace(s.Find(".NewsTitle").Text())
+		version := CleanVersion(title)
+		keepGoing := true
+		// Subtitles are found by their download links rather than by their language, so that subtitles whose other
+		// fields can't be parsed anymore after a change of the website are still returned, with warnings
+		s.Find("tr").EachWithBreak(func(j int, row *goquery.Selection) bool {
+			// A language may have several variants of the subtitle: original, updated and most updated
+			var links []string
+			row.Find(".buttonDownload").Each(func(k int, sss *goquery.Selection) {
+				if val, ok := sss.Attr("href"); ok && strings.TrimSpace(val) != "" {
+					links = append(links, c.url(strings.TrimSpace(val)))
+				}
+			})
+			if len(links) == 0 {
+				return true
+			}
+			language := strings.TrimSpace(row.Find(".language").Text())
+			warnings := c.unparsedFieldWarnings(links[0], language, version)
+			info := parseSubtitleInfo(s, row, time.Now())
+			for k, link := range links {
+				subtitle := Subtitle{
+					Version:     version,
+					VersionInfo: ParseVersion(version),
+					Language:    language,
+					Link:        link,
+					Page:        page,
+					Warnings:    warnings,
+					client:      c.Client,
+					variants:    otherLinks(links, k),
+
+					Completion:      info.completion,
+					HearingImpaired: info.hearingImpaired,
+					Downloads:       info.downloads,
+					Uploader:        info.uploader,
+					UploadedAt:      info.uploadedAt,
+				}
+				if keepGoing = yield(subtitle); !keepGoing {
+					break
+				}
+			}
+			return keepGoing
+		})
+		return keepGoing
 	})
+}
 
-	show := Show{
-		Name:      showName,
-		Subtitles: subtitles,
+// parseURL parses the URL of a page, returning nil if it is invalid
+func parseURL(page string) *url.URL {
+	u, err := url.Parse(page)
+	if err != nil {
+		return nil
 	}
+	return u
+}
 
-	return show, nil
+// documentURL returns the URL of the page of a document, or "" if it was not downloaded
+func documentURL(doc *goquery.Document) string {
+	if doc.Url == nil {
+		return ""
+	}
+	return doc.Url.String()
+}
+
+// unparsedFieldWarnings returns the warnings of a subtitle whose language or version couldn't be parsed, raising them for the call
+func (c *call) unparsedFieldWarnings(link, language, version string) []Warning {
+	var warnings []Warning
+	if language == "" {
+		warnings = append(warnings, Warning{Code: WarningUnparsedField, Message: fmt.Sprintf("the language of subtitle %v could not be parsed", link)})
+	}
+	if version == "" {
+		warnings = append(warnings, Warning{Code: WarningUnparsedField, Message: fmt.Sprintf("the version of subtitle %v could not be parsed", link)})
+	}
+	for _, w := range warnings {
+		c.warn(w.Code, "%v", w.Message)
+	}
+	return warnings
 }
 
 // Subtitle is a TV-Show subtitle
@@ -351,8 +805,61 @@ type Subtitle struct {
 	Language string
 	// Version is the subtitle type/version, usually the name of the teams who ripped the tv show
 	Version string
+	// VersionInfo is the parsed Version
+	VersionInfo Version
 	// Link is the link to the subtitle from Addic7ed website
 	Link string
+	// Completion is the percentage of the subtitle already translated, 100 when completed
+	Completion float64
+	// HearingImpaired is true when the subtitle describes sounds for hearing impaired people
+	HearingImpaired bool
+	// Downloads is the number of downloads of the subtitle on Addic7ed
+	Downloads int
+	// Uploader is the name of the user who uploaded the version
+	Uploader string
+	// UploadedAt is the date the version was uploaded, or the zero time if unknown
+	UploadedAt time.Time
+
+	// Page is the URL of the page the subtitle was found on, like the page of its episode, used to refresh its link, see Refresh
+	Page string
+	// MatchedByHash is set when the subtitle was found by the hash of the video, so it is synchronized with it, see SearchBestFrom
+	MatchedByHash bool
+	// Warnings are the issues met while parsing the subtitle, like a language that could not be parsed after a change of
+	// the website. Such subtitles are still returned with the fields that could be parsed, their link being usable
+	Warnings []Warning
+
+	// client is the client that found the subtitle, if any
+	client *Client
+	// provider is the provider that found the subtitle through a MultiProvider, if any
+	provider Provider
+	// variants are the other links of the subtitle for the same version and language, tried when Link is not found
+	variants []string
+}
+
+// otherLinks returns the links of variants, except the one at index i
+func otherLinks(links []string, i int) []string {
+	others := make([]string, 0, len(links)-1)
+	others = append(others, links[:i]...)
+	return append(others, links[i+1:]...)
+}
+
+// parsedVersion returns the parsed version of the subtitle, even when VersionInfo was not filled
+func (s Subtitle) parsedVersion() Version {
+	if s.VersionInfo.Raw == "" {
+		return ParseVersion(s.Version)
+	}
+	return s.VersionInfo
+}
+
+// ID is a stable identifier of the subtitle, derived from the path of its link, version and language.
+// The host of the link is left out, so that the ID doesn't change with the scheme or the base URL of the client, see WithBaseURL
+func (s Subtitle) ID() string {
+	link := s.Link
+	if u, err := url.Parse(s.Link); err == nil && u.Path != "" {
+		link = u.Path
+	}
+	sum := sha1.Sum([]byte(link + "\n" + s.Version + "\n" + s.Language))
+	return hex.EncodeToString(sum[:])
 }
 
 func (s Subtitle) String() string {
@@ -366,42 +873,106 @@ func (s Subtitle) IsOriginal() bool {
 }
 
 // Download download the subtitle in-memory, in a closable reader
+// When Addic7ed serves the subtitle in a .zip or .rar archive, the subtitle file is extracted from the archive.
+// It returns ErrReadOnly if the subtitle was found by a client in read-only mode,
+// ErrIncompleteDownload if the server sent less data than announced,
+// ErrEmptySubtitle if the file is empty or only contains a placeholder text,
+// and ErrUnacceptableContent if the file is not of a type accepted by the client (see AcceptMIMETypes and AcceptFormats)
 func (s Subtitle) Download() (io.ReadCloser, error) {
-	client := &http.Client{}
-	req, err := http.NewRequest("GET", s.Link, nil)
+	return s.DownloadContext(context.Background())
+}
+
+// DownloadContext is like Download, with a context to cancel the download
+func (s Subtitle) DownloadContext(ctx context.Context) (io.ReadCloser, error) {
+	data, err := s.download(ctx)
 	if err != nil {
 		return nil, err
 	}
-	// Avoid getting cached pages
-	req.Header.Add("Cache-Control", "no-cache")
-	req.Header.Add("User-Agent", userAgent)
-	req.Header.Add("Referer", s.Link) // Without it, the Addic7ed server redirect to the web page instead of dl the srt file
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
 
-	resp, err := client.Do(req)
+// DownloadAs downloads the subtitle to a given path, replacing its extension by the one of the detected format of the subtitle.
+// For example, a WebVTT subtitle downloaded as "Shameless.srt" is written to "Shameless.vtt".
+// The extension is kept when the format is not detected. It returns the path of the written file.
+func (s Subtitle) DownloadAs(path string) (string, error) {
+	return s.DownloadAsContext(context.Background(), path)
+}
+
+// DownloadAsContext is like DownloadAs, with a context to cancel the download
+func (s Subtitle) DownloadAsContext(ctx context.Context, path string) (string, error) {
+	data, err := s.download(ctx)
+	if err != nil {
+		return "", err
+	}
+	if format := DetectFormat(ToUTF8(data)); format != FormatUnknown {
+		path = strings.TrimSuffix(path, filepath.Ext(path)) + format.Extension()
+	}
+	return path, os.WriteFile(path, data, 0644)
+}
+
+// DownloadAlongside downloads the subtitle next to a video file, with the name of the video and the extension of the
+// detected format of the subtitle. With langSuffix, the ISO 639-1 code of the language is added before the extension, so that
+// "Show.S01E01.GROUP.mkv" gets "Show.S01E01.GROUP.en.srt", the name expected by most players and media servers.
+// It returns the path of the written file.
+func (s Subtitle) DownloadAlongside(videoPath string, langSuffix bool) (string, error) {
+	return s.DownloadAlongsideContext(context.Background(), videoPath, langSuffix)
+}
+
+// DownloadAlongsideContext is like DownloadAlongside, with a context to cancel the download
+func (s Subtitle) DownloadAlongsideContext(ctx context.Context, videoPath string, langSuffix bool) (string, error) {
+	data, err := s.download(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("Unable to reach addic7ed server: %v", err)
+		return "", err
+	}
+	path := strings.TrimSuffix(videoPath, filepath.Ext(videoPath))
+	if code := languageCode(s.Language); langSuffix && code != "" {
+		path += "." + code
 	}
-	return resp.Body, nil
+	path += DetectFormat(ToUTF8(data)).Extension()
+	return path, os.WriteFile(path, data, 0644)
 }
 
 // DownloadTo downloads the subtitle to a given path
+// If the download fails, no file is left at the given path
 func (s Subtitle) DownloadTo(path string) error {
-	sub, err := s.Download()
-	if err != nil {
-		return err
+	return s.DownloadToContext(context.Background(), path)
+}
+
+// DownloadToContext is like DownloadTo, with a context to cancel the download
+func (s Subtitle) DownloadToContext(ctx context.Context, path string) error {
+	// The file is only created once the subtitle is downloaded, so that a failed download does not overwrite an existing file
+	w := &lazyFile{path: path}
+	err := s.DownloadToWriterContext(ctx, w)
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil && w.file != nil {
+		_ = os.Remove(path)
 	}
-	defer sub.Close()
+	return err
+}
 
-	w, err := os.Create(path)
+// DownloadToWriter downloads the subtitle to a writer, like an HTTP response, an archive or a buffer
+// The subtitle is downloaded and checked in-memory before being written, so nothing is written if the download fails (see Download).
+func (s Subtitle) DownloadToWriter(w io.Writer) error {
+	return s.DownloadToWriterContext(context.Background(), w)
+}
+
+// DownloadToWriterContext is like DownloadToWriter, with a context to cancel the download
+func (s Subtitle) DownloadToWriterContext(ctx context.Context, w io.Writer) error {
+	data, err := s.download(ctx)
 	if err != nil {
 		return err
 	}
-	defer w.Close()
-
-	_, err = io.Copy(w, sub)
+	_, err = w.Write(data)
 	return err
 }
 
+// IsCompleted checks whether the translation of the subtitle is completed
+func (s Subtitle) IsCompleted() bool {
+	return s.Completion >= 100
+}
+
 // IsUpdated checks whether the subtitle is updated.
 // It means that the subtitle comeswith different version and this subtitle is the updated one.
 func (s Subtitle) IsUpdated() bool {
@@ -432,17 +1003,60 @@ func (ss Subtitles) Filter(filter func(s Subtitle) bool) Subtitles {
 	return subtitles
 }
 
+// Download downloads the first subtitle that is not empty, trying subtitles in order
+// Subtitles that are empty or only contain a placeholder text (see ErrEmptySubtitle), or that are not found on Addic7ed, are skipped.
+// It returns the downloaded subtitle with its content.
+func (ss Subtitles) Download() (Subtitle, io.ReadCloser, error) {
+	return ss.DownloadContext(context.Background())
+}
+
+// DownloadContext is like Download, with a context to cancel the downloads
+func (ss Subtitles) DownloadContext(ctx context.Context) (Subtitle, io.ReadCloser, error) {
+	for _, s := range ss {
+		sub, err := s.DownloadContext(ctx)
+		if err == nil {
+			return s, sub, nil
+		}
+		if !errors.Is(err, ErrEmptySubtitle) && !isNotFound(err) {
+			return Subtitle{}, nil, err
+		}
+	}
+	return Subtitle{}, nil, newError(CodeEmptySubtitle, nil, "no subtitle to download, all subtitles are empty or not available")
+}
+
+// DownloadTo downloads the first subtitle that is not empty to a given path, trying subtitles in order
+// See Subtitles.Download
+func (ss Subtitles) DownloadTo(path string) (Subtitle, error) {
+	return ss.DownloadToContext(context.Background(), path)
+}
+
+// DownloadToContext is like DownloadTo, with a context to cancel the downloads
+func (ss Subtitles) DownloadToContext(ctx context.Context, path string) (Subtitle, error) {
+	for _, s := range ss {
+		err := s.DownloadToContext(ctx, path)
+		if err == nil {
+			return s, nil
+		}
+		if !errors.Is(err, ErrEmptySubtitle) && !isNotFound(err) {
+			return Subtitle{}, err
+		}
+	}
+	return Subtitle{}, newError(CodeEmptySubtitle, nil, "no subtitle to download, all subtitles are empty or not available")
+}
+
 // GroupBy groups subtitles by a given property from the subtitle
+// See addic7ed.GroupBy to group by a property that is not a string
 func (ss Subtitles) GroupBy(property func(s Subtitle) string) map[string]Subtitles {
-	groupBy := map[string]Subtitles{}
+	return GroupBy(ss, property)
+}
+
+// GroupBy groups subtitles by a given property from the subtitle. The property can be of any comparable type.
+// For example, addic7ed.GroupBy(subtitles, Subtitle.IsUpdated) groups subtitles in updated and original ones.
+func GroupBy[K comparable](ss Subtitles, property func(s Subtitle) K) map[K]Subtitles {
+	groupBy := map[K]Subtitles{}
 	for _, s := range ss {
 		propKey := property(s)
-		if val, ok := groupBy[propKey]; ok {
-			val = append(val, s)
-			groupBy[propKey] = val
-		} else {
-			groupBy[propKey] = Subtitles{s}
-		}
+		groupBy[propKey] = append(groupBy[propKey], s)
 	}
 	return groupBy
 }
@@ -463,6 +1077,23 @@ func (ss Subtitles) GroupByLanguage() map[string]Subtitles {
 
 // Show defines a TV show with a name and associated subtitle
 type Show struct {
-	Name      string
+	Name string
+	// Number and Title are the episode of the page, like S08E11 and "A Gallagher Pedicure", to check that the right episode
+	// was found before downloading. They are parsed from the name of the episode, or from the URL of its page, and left
+	// empty when neither gives them.
+	Number    EpisodeNumber
+	Title     string
 	Subtitles Subtitles
+	// Warnings are the non-fatal issues that happened while searching the show
+	Warnings []Warning
+	// Translations are the subtitles being translated, listed on the page of the episode, even when they can't be downloaded yet
+	Translations []Translation
+	// Partial is true when the subtitles were only partly parsed before the end of a best-effort call, see WithBestEffort
+	Partial bool
+
+	// showID and showName are the Addic7ed id and name of the show of the episode, if found on its page
+	showID   string
+	showName string
+	// missingVersions is set when some pages of versions of the episode could not be fetched, so that it is not cached
+	missingVersions bool
 }
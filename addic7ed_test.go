@@ -2,6 +2,7 @@ package addic7ed_test
 
 import (
 	"errors"
+	"net/http"
 	"regexp"
 	"testing"
 
@@ -158,3 +159,92 @@ func TestFilterRegexp(t *testing.T) {
 	subtitles := subs.Filter(addic7ed.WithVersionRegexp(regex))
 	assert.Len(t, subtitles, 1)
 }
+
+func TestFilterMetadata(t *testing.T) {
+	subs := addic7ed.Subtitles{
+		{Version: "A", Language: "English", Completion: 100, Downloads: 1500},
+		{Version: "A", Language: "English", Completion: 100, HearingImpaired: true, Downloads: 300},
+		{Version: "B", Language: "English", Completion: 42.5, Downloads: 12},
+		{Version: "A", Language: "French", Completion: 100, Downloads: 800},
+	}
+	assert.Len(t, subs.Filter(addic7ed.WithHearingImpaired()), 1)
+	assert.Len(t, subs.Filter(addic7ed.WithCompleted()), 3)
+	assert.Len(t, subs.Filter(addic7ed.WithMinDownloads(800)), 2)
+	assert.Len(t, subs.Filter(addic7ed.And(addic7ed.WithLanguage("English"), addic7ed.Not(addic7ed.WithHearingImpaired()))), 2)
+	assert.Len(t, subs.Filter(addic7ed.Or(addic7ed.WithLanguage("French"), addic7ed.WithHearingImpaired())), 2)
+	assert.Len(t, subs.Filter(addic7ed.And()), 4)
+	assert.Len(t, subs.Filter(addic7ed.Or()), 0)
+}
+
+func TestUsageOfNewClient(t *testing.T) {
+	c := addic7ed.New()
+	assert.Equal(t, addic7ed.Usage{}, c.Usage())
+}
+
+func TestOptionsOfSettings(t *testing.T) {
+	const srt = "1\n00:00:01,000 --> 00:00:02,000\nHello\n"
+	transport := handlerTransport{episodeHandler(t, map[string]string{"/original/131967/0": srt})}
+
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}), addic7ed.WithReadOnly())
+	show, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+	_, err = show.Subtitles[0].Download()
+	assert.True(t, errors.Is(err, addic7ed.ErrReadOnly), "unexpected error %v", err)
+
+	c = addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}), addic7ed.WithAcceptedFormats(addic7ed.FormatVTT))
+	show, err = c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+	_, err = show.Subtitles[0].Download()
+	assert.True(t, errors.Is(err, addic7ed.ErrUnacceptableContent), "unexpected error %v", err)
+
+	c = addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}), addic7ed.WithAcceptedMIMETypes("application/x-subrip"))
+	show, err = c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+	_, err = show.Subtitles[0].Download()
+	assert.True(t, errors.Is(err, addic7ed.ErrUnacceptableContent), "unexpected error %v", err)
+}
+
+func TestDefaultClientIsShared(t *testing.T) {
+	assert.NotNil(t, addic7ed.DefaultClient())
+	assert.Same(t, addic7ed.DefaultClient(), addic7ed.DefaultClient())
+}
+
+func TestSubtitlesAllStopsEarly(t *testing.T) {
+	subs := addic7ed.Subtitles{
+		{Version: "A", Language: "French", Link: "http://addic7ed.com/A-good-show"},
+		{Version: "B", Language: "French", Link: "http://addic7ed.com/A-good-show"},
+		{Version: "C", Language: "Italian", Link: "http://addic7ed.com/A-good-show"},
+	}
+	versions := []string{}
+	for s := range subs.All() {
+		versions = append(versions, s.Version)
+		if s.Version == "B" {
+			break
+		}
+	}
+	assert.Equal(t, []string{"A", "B"}, versions)
+}
+
+func TestGroupByComparableProperty(t *testing.T) {
+	subs := addic7ed.Subtitles{
+		{Version: "A", Language: "French", Link: "http://addic7ed.com/original/A-good-show"},
+		{Version: "A", Language: "French", Link: "http://addic7ed.com/updated/A-good-show"},
+		{Version: "B", Language: "French", Link: "http://addic7ed.com/original/A-good-show"},
+	}
+	groupByUpdated := addic7ed.GroupBy(subs, addic7ed.Subtitle.IsUpdated)
+	assert.Len(t, groupByUpdated, 2)
+	assert.Len(t, groupByUpdated[true], 1)
+	assert.Len(t, groupByUpdated[false], 2)
+}
+
+func TestSubtitleID(t *testing.T) {
+	sub := addic7ed.Subtitle{Version: "A", Language: "French", Link: "http://addic7ed.com/A-good-show"}
+	assert.Equal(t, sub.ID(), addic7ed.Subtitle{Version: "A", Language: "French", Link: "http://addic7ed.com/A-good-show"}.ID())
+	assert.NotEqual(t, sub.ID(), addic7ed.Subtitle{Version: "A", Language: "English", Link: "http://addic7ed.com/A-good-show"}.ID())
+	assert.Len(t, sub.ID(), 40)
+
+	// The ID doesn't depend on the base URL
+	assert.Equal(t, sub.ID(), addic7ed.Subtitle{Version: "A", Language: "French", Link: "https://www.addic7ed.com/A-good-show"}.ID())
+	assert.Equal(t, sub.ID(), addic7ed.Subtitle{Version: "A", Language: "French", Link: "http://127.0.0.1:8080/A-good-show"}.ID())
+	assert.NotEqual(t, sub.ID(), addic7ed.Subtitle{Version: "A", Language: "French", Link: "http://addic7ed.com/Another-show"}.ID())
+}
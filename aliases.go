@@ -0,0 +1,90 @@
+package addic7ed
+
+import (
+	"fmt"
+	"maps"
+)
+
+// showAliases are the names of the shows listed under another title on Addic7ed, by their normalized titles in release names
+var showAliases = normalizeAliases(map[string]string{
+	"Money Heist":                      "La Casa de Papel",
+	"Law and Order SVU":                "Law & Order: Special Victims Unit",
+	"Law & Order SVU":                  "Law & Order: Special Victims Unit",
+	"Agents of SHIELD":                 "Marvel's Agents of S.H.I.E.L.D.",
+	"The Office US":                    "The Office (US)",
+	"The Office UK":                    "The Office (UK)",
+	"House of Cards US":                "House of Cards (2013)",
+	"House of Cards 2013":              "House of Cards (2013)",
+	"Shameless US":                     "Shameless (US)",
+	"Shameless UK":                     "Shameless (UK)",
+	"Skins US":                         "Skins (US)",
+	"Being Human US":                   "Being Human (US)",
+	"Queer as Folk US":                 "Queer as Folk (US)",
+	"The Flash 2014":                   "The Flash (2014)",
+	"Castle 2009":                      "Castle (2009)",
+	"Greys Anatomy":                    "Grey's Anatomy",
+	"The Handmaids Tale":               "The Handmaid's Tale",
+	"Its Always Sunny in Philadelphia": "It's Always Sunny in Philadelphia",
+})
+
+// normalizeAliases normalizes the titles of aliases, see normalizeShowName
+func normalizeAliases(aliases map[string]string) map[string]string {
+	normalized := make(map[string]string, len(aliases))
+	for title, name := range aliases {
+		normalized[normalizeShowName(title)] = name
+	}
+	return normalized
+}
+
+// WithShowAliases sets the names of the shows listed under another title on Addic7ed, like renamed shows or US and UK
+// variants, as a map of the titles found in release names to the names of Addic7ed, like "Money Heist" to "La Casa de Papel".
+// The aliases are searched when the show of a release isn't found, and complete a built-in table of well-known aliases.
+// Titles match regardless of case and separators, and may end with a year to only match releases of that year.
+func WithShowAliases(aliases map[string]string) Option {
+	return func(c *Client) {
+		if c.aliases == nil {
+			c.aliases = map[string]string{}
+		}
+		maps.Copy(c.aliases, normalizeAliases(aliases))
+	}
+}
+
+// alias returns the name of a show on Addic7ed when listed under another title, with whether the year the title was
+// given with is part of the alias. Aliases of the client win over the built-in ones.
+func (c *Client) alias(title string, year int) (name string, withYear bool, ok bool) {
+	keys := []string{normalizeShowName(title)}
+	if year > 0 {
+		keys = append([]string{normalizeShowName(fmt.Sprintf("%v %v", title, year))}, keys...)
+	}
+	for _, aliases := range []map[string]string{c.aliases, showAliases} {
+		for i, key := range keys {
+			if name, ok := aliases[key]; ok {
+				return name, year > 0 && i == 0, true
+			}
+		}
+	}
+	return "", false, false
+}
+
+// aliasSearch returns the search of a release using the alias of its show, or false if its show has no alias
+func (c *call) aliasSearch(fileName string) (string, bool) {
+	release := ParseRelease(fileName)
+	if release.Title == "" {
+		return "", false
+	}
+	name, withYear, ok := c.alias(release.Title, release.Year)
+	if !ok {
+		return "", false
+	}
+	release.Title = name
+	if withYear {
+		release.Year = 0
+	}
+	if !release.HasEpisode() {
+		if release.Year > 0 {
+			return fmt.Sprintf("%v %v", release.Title, release.Year), true
+		}
+		return release.Title, true
+	}
+	return release.Query(), true
+}
@@ -0,0 +1,59 @@
+package addic7ed_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/matcornic/addic7ed"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShowAliases(t *testing.T) {
+	var searches []string
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{onlySearch(t, "La Casa de Papel S08E11", &searches)}}))
+
+	_, err := c.SearchAll("Money.Heist.S08E11.720p.WEB.x264-GROUP.mkv")
+	assert.NoError(t, err)
+	// Aliases are only searched when the release isn't found
+	assert.Equal(t, []string{"Money Heist S08E11", "Money.Heist.S08E11.720p.WEB.x264-GROUP.mkv", "La Casa de Papel S08E11"}, searches)
+}
+
+func TestWithShowAliases(t *testing.T) {
+	var searches []string
+	c := addic7ed.New(
+		addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{onlySearch(t, "Star Trek Picard S08E11", &searches)}}),
+		addic7ed.WithShowAliases(map[string]string{"Picard": "Star Trek Picard", "Money Heist": "Money Heist (Korea)"}),
+	)
+	_, err := c.SearchAll("Picard.S08E11.mkv")
+	assert.NoError(t, err)
+	assert.Equal(t, "Star Trek Picard S08E11", searches[len(searches)-1])
+
+	// Aliases of the client win over the built-in ones
+	searches = nil
+	_, err = c.SearchAll("Money Heist S08E11")
+	assert.Error(t, err)
+	assert.Equal(t, []string{"Money Heist S08E11", "Money Heist (Korea) S08E11"}, searches)
+
+	// Shows without episode are aliased too
+	searches = nil
+	_, err = c.SearchAll("picard")
+	assert.Error(t, err)
+	assert.Equal(t, []string{"picard", "Star Trek Picard"}, searches)
+}
+
+func TestWithShowAliasesOfYear(t *testing.T) {
+	var searches []string
+	c := addic7ed.New(
+		addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{onlySearch(t, "Magnum P.I. (2018) S08E11", &searches)}}),
+		addic7ed.WithShowAliases(map[string]string{"Magnum PI 2018": "Magnum P.I. (2018)"}),
+	)
+	_, err := c.SearchAll("Magnum.PI.2018.S08E11.720p.HDTV.x264-AVS.mkv")
+	assert.NoError(t, err)
+	assert.Equal(t, "Magnum P.I. (2018) S08E11", searches[len(searches)-1])
+
+	// Other years are not aliased
+	searches = nil
+	_, err = c.SearchAll("Magnum.PI.1980.S08E11.mkv")
+	assert.Error(t, err)
+	assert.Equal(t, []string{"Magnum PI 1980 S08E11", "Magnum.PI.1980.S08E11.mkv"}, searches)
+}
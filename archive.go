@@ -0,0 +1,135 @@
+package addic7ed
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"mime"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/nwaples/rardecode/v2"
+)
+
+var (
+	zipMagic = []byte("PK\x03\x04")
+	rarMagic = []byte("Rar!\x1a\x07")
+)
+
+// subtitleExtensions are the extensions of subtitle files looked for in archives, by order of preference
+var subtitleExtensions = []string{".srt", ".ass", ".ssa", ".vtt", ".sub"}
+
+// IsSubtitle checks whether a file is a subtitle, from its extension, like the subtitles found next to videos by Watcher
+func IsSubtitle(file string) bool {
+	return subtitlePreference(file) >= 0
+}
+
+// archiveContentTypes are the content types of .zip and .rar archives
+var archiveContentTypes = []string{"application/zip", "application/x-zip-compressed", "application/x-rar-compressed", "application/vnd.rar", "application/x-rar"}
+
+// isArchive checks whether data is a .zip or .rar archive, from its magic bytes
+func isArchive(data []byte) bool {
+	return bytes.HasPrefix(data, zipMagic) || bytes.HasPrefix(data, rarMagic)
+}
+
+// extractSubtitle extracts the subtitle file from data if it is a .zip or .rar archive, detected by its magic bytes, and
+// returns it with its name in the archive. Data that is not an archive is returned as is, with an empty name, unless its
+// content type is the one of an archive.
+func extractSubtitle(contentType string, data []byte) ([]byte, string, error) {
+	switch {
+	case bytes.HasPrefix(data, zipMagic):
+		return extractSubtitleFromZip(data)
+	case bytes.HasPrefix(data, rarMagic):
+		return extractSubtitleFromRar(data)
+	}
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil && slices.Contains(archiveContentTypes, mediaType) {
+		return nil, "", newError(CodeParseFailure, nil, "subtitle served as %v is not a valid archive", mediaType)
+	}
+	return data, "", nil
+}
+
+// subtitlePreference returns the preference of a file found in an archive: the lower the better, -1 if not a subtitle file
+func subtitlePreference(name string) int {
+	ext := strings.ToLower(path.Ext(name))
+	for i, subExt := range subtitleExtensions {
+		if ext == subExt {
+			return i
+		}
+	}
+	return -1
+}
+
+// maxArchivedFileSize is the maximum size of a file extracted from an archive, far above the size of any subtitle,
+// so that a corrupted or malicious archive can't exhaust the memory
+const maxArchivedFileSize = 8 << 20
+
+// readArchivedFile reads a file of an archive, failing with CodeParseFailure if it is bigger than maxArchivedFileSize
+func readArchivedFile(r io.Reader, name, archive string) ([]byte, error) {
+	content, err := io.ReadAll(io.LimitReader(r, maxArchivedFileSize+1))
+	if err != nil {
+		return nil, newError(CodeParseFailure, err, "Unable to read %v in %v", name, archive)
+	}
+	if len(content) > maxArchivedFileSize {
+		return nil, newError(CodeParseFailure, nil, "%v in %v is bigger than %v MB", name, archive, maxArchivedFileSize>>20)
+	}
+	return content, nil
+}
+
+func extractSubtitleFromZip(data []byte) ([]byte, string, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, "", newError(CodeParseFailure, err, "Unable to read zip archive")
+	}
+	var best *zip.File
+	bestPreference := len(subtitleExtensions)
+	for _, f := range r.File {
+		if preference := subtitlePreference(f.Name); !f.FileInfo().IsDir() && preference >= 0 && preference < bestPreference {
+			best, bestPreference = f, preference
+		}
+	}
+	if best == nil {
+		return nil, "", newError(CodeParseFailure, nil, "zip archive does not contain any subtitle")
+	}
+	rc, err := best.Open()
+	if err != nil {
+		return nil, "", newError(CodeParseFailure, err, "Unable to read %v in zip archive", best.Name)
+	}
+	defer rc.Close()
+	content, err := readArchivedFile(rc, best.Name, "zip archive")
+	if err != nil {
+		return nil, "", err
+	}
+	return content, best.Name, nil
+}
+
+func extractSubtitleFromRar(data []byte) ([]byte, string, error) {
+	r, err := rardecode.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", newError(CodeParseFailure, err, "Unable to read rar archive")
+	}
+	// Files of a rar archive can only be read sequentially, so the best subtitle is kept while reading
+	var best []byte
+	var bestName string
+	bestPreference := len(subtitleExtensions)
+	for {
+		header, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, "", newError(CodeParseFailure, err, "Unable to read rar archive")
+		}
+		if preference := subtitlePreference(header.Name); !header.IsDir && preference >= 0 && preference < bestPreference {
+			content, err := readArchivedFile(r, header.Name, "rar archive")
+			if err != nil {
+				return nil, "", err
+			}
+			best, bestName, bestPreference = content, header.Name, preference
+		}
+	}
+	if best == nil {
+		return nil, "", newError(CodeParseFailure, nil, "rar archive does not contain any subtitle")
+	}
+	return best, bestName, nil
+}
@@ -0,0 +1,161 @@
+package addic7ed
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// Availability is the availability of the subtitles of an episode in a language
+type Availability struct {
+	// Subtitles is the number of subtitles in the language, whatever their completion
+	Subtitles int `json:"subtitles"`
+	// Completion is the best completion of the subtitles in the language, in percent, or 0 without subtitle
+	Completion float64 `json:"completion"`
+}
+
+// IsAvailable checks whether a completed subtitle is available
+func (a Availability) IsAvailable() bool {
+	return a.Subtitles > 0 && a.Completion >= 100
+}
+
+// String returns "available", the completion of the best subtitle being translated like "45%", or "missing"
+func (a Availability) String() string {
+	switch {
+	case a.IsAvailable():
+		return "available"
+	case a.Subtitles > 0:
+		return fmt.Sprintf("%v%%", a.Completion)
+	}
+	return "missing"
+}
+
+// AvailabilityRow is the availability of the subtitles of an episode, in the languages of its matrix
+type AvailabilityRow struct {
+	Season  int    `json:"season"`
+	Episode int    `json:"episode"`
+	Title   string `json:"title"`
+	// Languages are the availabilities in the languages of the matrix, in the same order
+	Languages []Availability `json:"languages"`
+}
+
+// AvailabilityMatrix is the availability of the subtitles of the episodes of a season by language, to plan which
+// languages to wait for. It can be written as JSON, CSV or markdown, see WriteJSON, WriteCSV and WriteMarkdown.
+type AvailabilityMatrix struct {
+	Show   string `json:"show"`
+	Season int    `json:"season"`
+	// Languages are the languages of the columns of the matrix
+	Languages []string `json:"languages"`
+	// Episodes are the rows of the matrix, in order
+	Episodes []AvailabilityRow `json:"episodes"`
+}
+
+// NewAvailabilityMatrix builds the availability matrix of the episodes of a season in languages, by name or code, see GetEpisodes.
+// All languages of the subtitles of the episodes are used, in alphabetical order, when no language is given.
+func NewAvailabilityMatrix(show string, season int, episodes []Episode, langs ...string) AvailabilityMatrix {
+	matrix := AvailabilityMatrix{Show: show, Season: season, Languages: []string{}, Episodes: []AvailabilityRow{}}
+	if len(langs) == 0 {
+		for _, episode := range episodes {
+			for _, s := range episode.Subtitles {
+				if s.Language != "" && !slices.Contains(langs, s.Language) {
+					langs = append(langs, s.Language)
+				}
+			}
+		}
+		slices.Sort(langs)
+	}
+	for _, lang := range langs {
+		matrix.Languages = append(matrix.Languages, ParseLanguage(lang).Name)
+	}
+	for _, episode := range episodes {
+		row := AvailabilityRow{Season: episode.Number.Season, Episode: episode.Number.Episode, Title: episode.Title, Languages: make([]Availability, len(langs))}
+		for i, lang := range langs {
+			for _, s := range episode.Subtitles.Filter(WithLanguage(lang)) {
+				row.Languages[i].Subtitles++
+				row.Languages[i].Completion = max(row.Languages[i].Completion, s.Completion)
+			}
+		}
+		matrix.Episodes = append(matrix.Episodes, row)
+	}
+	return matrix
+}
+
+// GetAvailability gets the availability matrix of the subtitles of a season of a show in languages, by name or code.
+// All languages having subtitles in the season are used when no language is given. Episodes without any subtitle aren't
+// listed by Addic7ed, so they are missing from the matrix. It returns ErrNoSubtitlesYet if the season has no subtitle yet.
+func (c *Client) GetAvailability(show TVShow, season int, langs []string, opts ...CallOption) (AvailabilityMatrix, error) {
+	return c.GetAvailabilityContext(context.Background(), show, season, langs, opts...)
+}
+
+// GetAvailabilityContext is like GetAvailability, with a context to cancel the search
+func (c *Client) GetAvailabilityContext(ctx context.Context, show TVShow, season int, langs []string, opts ...CallOption) (AvailabilityMatrix, error) {
+	episodes, err := c.GetEpisodesContext(ctx, show, season, opts...)
+	if err != nil {
+		return AvailabilityMatrix{}, err
+	}
+	return NewAvailabilityMatrix(show.Name, season, episodes, langs...), nil
+}
+
+// WriteJSON writes the matrix as indented JSON
+func (m AvailabilityMatrix) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(m)
+}
+
+// WriteCSV writes the matrix as CSV, with a header of the episode, its title and the languages, and the availabilities
+// of the episodes of each language like "available", "45%" or "missing"
+func (m AvailabilityMatrix) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	for _, record := range m.records() {
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// markdownAvailability are the availabilities as written in markdown, the completions being kept as is
+var markdownAvailability = map[string]string{"available": "✓", "missing": ""}
+
+// WriteMarkdown writes the matrix as a markdown table, checking the available subtitles
+func (m AvailabilityMatrix) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %v, season %v\n\n", m.Show, m.Season)
+	for i, record := range m.records() {
+		for j, cell := range record {
+			if i > 0 && j > 1 {
+				if mark, ok := markdownAvailability[cell]; ok {
+					cell = mark
+				}
+			} else {
+				cell = strings.ReplaceAll(cell, "|", `\|`)
+			}
+			fmt.Fprintf(&b, "| %v ", cell)
+		}
+		b.WriteString("|\n")
+		if i == 0 {
+			b.WriteString(strings.Repeat("| --- ", len(record)) + "|\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// records returns the header and the rows of the matrix
+func (m AvailabilityMatrix) records() [][]string {
+	records := [][]string{append([]string{"Episode", "Title"}, m.Languages...)}
+	for _, row := range m.Episodes {
+		record := []string{EpisodeNumber{Season: row.Season, Episode: row.Episode}.String(), row.Title}
+		for _, availability := range row.Languages {
+			record = append(record, availability.String())
+		}
+		records = append(records, record)
+	}
+	return records
+}
@@ -0,0 +1,67 @@
+package addic7ed_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+func TestGetAvailability(t *testing.T) {
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{showHandler(t)}}))
+	show := addic7ed.TVShow{ID: "5427", Name: "Shameless (US)", Seasons: []int{6, 7, 8}}
+
+	matrix, err := c.GetAvailability(show, 8, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"English", "French"}, matrix.Languages)
+
+	var csv bytes.Buffer
+	assert.NoError(t, matrix.WriteCSV(&csv))
+	assert.Equal(t, "Episode,Title,English,French\nS08E11,A Gallagher Pedicure,available,missing\nS08E12,Church of Gay Jesus,available,available\n", csv.String())
+
+	// Languages are given by name or code
+	matrix, err = c.GetAvailability(show, 8, []string{"fr", "Klingon"})
+	assert.NoError(t, err)
+	var decoded addic7ed.AvailabilityMatrix
+	var encoded bytes.Buffer
+	assert.NoError(t, matrix.WriteJSON(&encoded))
+	assert.NoError(t, json.Unmarshal(encoded.Bytes(), &decoded))
+	assert.Equal(t, matrix, decoded)
+	assert.Equal(t, []string{"French", "Klingon"}, decoded.Languages)
+	assert.Equal(t, []addic7ed.Availability{{}, {}}, decoded.Episodes[0].Languages)
+	assert.Equal(t, []addic7ed.Availability{{Subtitles: 1, Completion: 100}, {}}, decoded.Episodes[1].Languages)
+
+	_, err = c.GetAvailability(show, 7, nil)
+	assert.True(t, errors.Is(err, addic7ed.ErrNoSubtitlesYet), "unexpected error %v", err)
+}
+
+func TestAvailabilityMatrixMarkdown(t *testing.T) {
+	episodes := []addic7ed.Episode{
+		{Number: addic7ed.EpisodeNumber{Season: 1, Episode: 1}, Title: "Pilot | Part 1", Subtitles: addic7ed.Subtitles{
+			{Language: "English", Completion: 100},
+			{Language: "French", Completion: 12},
+			{Language: "French", Completion: 45},
+		}},
+		{Number: addic7ed.EpisodeNumber{Season: 1, Episode: 2}, Title: "Second", Subtitles: addic7ed.Subtitles{
+			{Language: "English", Completion: 100},
+		}},
+	}
+	matrix := addic7ed.NewAvailabilityMatrix("Show", 1, episodes)
+	assert.Equal(t, addic7ed.Availability{Subtitles: 2, Completion: 45}, matrix.Episodes[0].Languages[1])
+	assert.False(t, matrix.Episodes[0].Languages[1].IsAvailable())
+
+	var markdown bytes.Buffer
+	assert.NoError(t, matrix.WriteMarkdown(&markdown))
+	assert.Equal(t, `## Show, season 1
+
+| Episode | Title | English | French |
+| --- | --- | --- | --- |
+| S01E01 | Pilot \| Part 1 | ✓ | 45% |
+| S01E02 | Second | ✓ |  |
+`, markdown.String())
+}
@@ -0,0 +1,143 @@
+package addic7ed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// BatchResult is the result of the search of the best subtitle of a file, see SearchBestBatch
+type BatchResult struct {
+	// Name is the name of the found episode
+	Name string
+	// Subtitle is the best subtitle found for the file
+	Subtitle Subtitle
+	// Warnings are the warnings raised during the search of the file
+	Warnings []Warning
+	// Err is the error of the search, nil if a subtitle was found
+	Err error
+}
+
+// SearchBestBatch searches the best subtitle of each file, like SearchBest, with at most concurrency searches at the same time.
+// Searches respect the rate limit of the client (see WithRateLimit), so a whole season can be searched in one call.
+// Episodes of the same season of a show are answered from the page of the season, fetched once, instead of one search per file.
+// It returns the results by file, and an error joining the errors of the failed searches, nil if all searches succeeded.
+// Concurrency of 0 or less searches one file at a time. Options apply to each search:
+// as warnings may be raised by concurrent searches, the handler given to WithWarnings must be safe for concurrent use.
+func (c *Client) SearchBestBatch(files []string, lang string, concurrency int, opts ...CallOption) (map[string]BatchResult, error) {
+	return c.SearchBestBatchContext(context.Background(), files, lang, concurrency, opts...)
+}
+
+// SearchBestBatchContext is like SearchBestBatch, with a context to cancel the searches.
+// Files not searched yet when the context is cancelled fail with the error of the context.
+func (c *Client) SearchBestBatchContext(ctx context.Context, files []string, lang string, concurrency int, opts ...CallOption) (map[string]BatchResult, error) {
+	groups := c.groupBySeason(files)
+	jobs := make(chan []string)
+	var mu sync.Mutex
+	results := make(map[string]BatchResult, len(files))
+
+	var wg sync.WaitGroup
+	for range min(max(concurrency, 1), len(groups)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for group := range jobs {
+				c.searchBestGroup(ctx, group, lang, opts, func(file string, result BatchResult) {
+					mu.Lock()
+					results[file] = result
+					mu.Unlock()
+				})
+			}
+		}()
+	}
+	for _, group := range groups {
+		jobs <- group
+	}
+	close(jobs)
+	wg.Wait()
+
+	var errs []error
+	for _, file := range files {
+		if err := results[file].Err; err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", file, err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// groupBySeason groups files by season of the same show, after the episode mapping of the show, keeping the order of files.
+// Files that are not episodes of a show are alone in their group.
+func (c *Client) groupBySeason(files []string) [][]string {
+	groups := [][]string{}
+	indexes := map[string]int{}
+	for _, file := range files {
+		release := ParseRelease(c.mapEpisode(file))
+		if !release.HasEpisode() {
+			groups = append(groups, []string{file})
+			continue
+		}
+		key := fmt.Sprintf("%v|%v|%v", normalizeShowName(release.Title), release.Year, release.Season)
+		if i, ok := indexes[key]; ok {
+			groups[i] = append(groups[i], file)
+			continue
+		}
+		indexes[key] = len(groups)
+		groups = append(groups, []string{file})
+	}
+	return groups
+}
+
+// searchBestGroup searches the best subtitle of files of the same season of a show.
+// The first file is searched on its own, then the pages of the seasons of the other files, after the episode mapping of the
+// show, are fetched once to answer them. Files not found on the page of their season are searched on their own.
+func (c *Client) searchBestGroup(ctx context.Context, files []string, lang string, opts []CallOption, report func(file string, result BatchResult)) {
+	first := c.newCall(ctx, opts)
+	if err := ctx.Err(); err != nil {
+		for _, file := range files {
+			report(file, BatchResult{Err: err})
+		}
+		return
+	}
+	show, err := first.searchAll(files[0])
+	var result BatchResult
+	if err == nil {
+		result.Name, result.Subtitle, err = first.bestOfShow(files[0], lang, show)
+	}
+	result.Warnings, result.Err = first.warnings, err
+	report(files[0], result)
+
+	seasons := map[int]map[EpisodeNumber]Show{}
+	for _, file := range files[1:] {
+		// Files are numbered like Addic7ed before looking for their episode, see WithEpisodeMapping
+		number := ParseRelease(c.mapEpisode(file)).EpisodeNumber()
+		if _, fetched := seasons[number.Season]; !fetched && show.showID != "" {
+			first.infof("Fetching season %v of show %v for %v files", number.Season, show.showName, len(files))
+			// Seasons are cached like by GetEpisodes, so that seasons warmed by WarmSeasons are not fetched again
+			episodes, _ := c.GetEpisodesContext(ctx, TVShow{ID: show.showID, Name: show.showName}, number.Season, opts...)
+			seasons[number.Season] = seasonShows(episodes, show.showName)
+		}
+		if show, ok := seasons[number.Season][number]; ok {
+			call := c.newCall(ctx, opts)
+			err := call.checkEpisode(file, &show)
+			var name string
+			var subtitle Subtitle
+			if err == nil {
+				name, subtitle, err = call.bestOfShow(file, lang, show)
+			}
+			report(file, BatchResult{Name: name, Subtitle: subtitle, Warnings: call.warnings, Err: err})
+			continue
+		}
+		report(file, c.searchBestResult(ctx, file, lang, opts))
+	}
+}
+
+// searchBestResult searches the best subtitle of a file, in its own call
+func (c *Client) searchBestResult(ctx context.Context, file, lang string, opts []CallOption) BatchResult {
+	if err := ctx.Err(); err != nil {
+		return BatchResult{Err: err}
+	}
+	call := c.newCall(ctx, opts)
+	name, subtitle, err := call.searchBest(file, lang)
+	return BatchResult{Name: name, Subtitle: subtitle, Warnings: call.warnings, Err: err}
+}
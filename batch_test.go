@@ -0,0 +1,108 @@
+package addic7ed_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+func TestSearchBestBatch(t *testing.T) {
+	handler := episodeHandler(t, nil)
+	transport := handlerTransport{http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Query().Get("search"), "Unknown") {
+			w.Write([]byte("<html><body>Nothing found</body></html>"))
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})}
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}))
+
+	files := []string{
+		"Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]",
+		"Unknown.Show.S01E01.720p.HDTV.x264-BATV",
+		"Shameless.US.S08E11.WEB.x264-TBS",
+	}
+	results, err := c.SearchBestBatch(files, "English", 2)
+	assert.True(t, errors.Is(err, addic7ed.ErrShowNotFound), "unexpected error %v", err)
+	assert.Contains(t, err.Error(), files[1])
+	assert.Len(t, results, 3)
+	assert.Equal(t, "BATV", results[files[0]].Subtitle.Version)
+	assert.NoError(t, results[files[0]].Err)
+	assert.Equal(t, "Shameless (US) - 08x11 - A Gallagher Pedicure", results[files[2]].Name)
+	assert.Equal(t, "WEB.x264-TBS", results[files[2]].Subtitle.Version)
+	assert.True(t, errors.Is(results[files[1]].Err, addic7ed.ErrShowNotFound))
+
+	results, err = c.SearchBestBatch(files[:1], "English", 0)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+}
+
+func TestSearchBestBatchFetchesSeasonsOnce(t *testing.T) {
+	season, err := os.ReadFile("testdata/season.html")
+	assert.NoError(t, err)
+	var searches, seasons int64
+	handler := countSearches(episodeHandler(t, nil), &searches)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/show/5427" && r.URL.Query().Get("season") == "8" {
+			atomic.AddInt64(&seasons, 1)
+			w.Write(season)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	// Episodes missing from the season are searched, finding the page of S08E11 of the fixture
+	c := addic7ed.New(addic7ed.WithBaseURL(server.URL), addic7ed.WithEpisodeMismatchWarnings())
+
+	files := []string{
+		"Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]",
+		"Shameless.US.S08E12.720p.HDTV.x264-AVS[ettv]",
+		"Shameless.US.S08E12.WEB.x264-TBS",
+		"Shameless.US.S08E13.720p.HDTV.x264-BATV", // Not on the page of the season
+	}
+	results, err := c.SearchBestBatch(files, "English", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), atomic.LoadInt64(&searches))
+	assert.Equal(t, int64(1), atomic.LoadInt64(&seasons))
+
+	assert.Equal(t, "BATV", results[files[0]].Subtitle.Version)
+	assert.Equal(t, "Shameless (US) - 08x12 - Church of Gay Jesus", results[files[1]].Name)
+	assert.Equal(t, "AVS", results[files[1]].Subtitle.Version)
+	assert.Equal(t, server.URL+"/original/132104/0", results[files[1]].Subtitle.Link)
+	assert.True(t, results[files[1]].Subtitle.HearingImpaired)
+	assert.Equal(t, "WEB.x264-TBS", results[files[2]].Subtitle.Version)
+	assert.Equal(t, server.URL+"/original/132104/2", results[files[2]].Subtitle.Link)
+	assert.NoError(t, results[files[3]].Err)
+}
+
+func TestSearchBestBatchMapsEpisodes(t *testing.T) {
+	season, err := os.ReadFile("testdata/season.html")
+	assert.NoError(t, err)
+	handler := episodeHandler(t, nil)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/show/5427" && r.URL.Query().Get("season") == "8" {
+			w.Write(season)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	c := addic7ed.New(
+		addic7ed.WithBaseURL(server.URL),
+		addic7ed.WithEpisodeMapping("Shameless US", addic7ed.EpisodeMapping{{Season: 0, Episode: 1}: {Season: 8, Episode: 12}}),
+	)
+
+	files := []string{"Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", "Shameless.US.S00E01.720p.HDTV.x264-AVS[ettv]"}
+	results, err := c.SearchBestBatch(files, "English", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "Shameless (US) - 08x12 - Church of Gay Jesus", results[files[1]].Name)
+	assert.Equal(t, "AVS", results[files[1]].Subtitle.Version)
+}
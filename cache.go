@@ -0,0 +1,369 @@
+package addic7ed
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// WithCache caches the episodes found by searches in memory, with their subtitles, to avoid fetching them again.
+// Cached episodes are served as is for maxAge. For staleWhileRevalidate after that, they are still served right away,
+// but refreshed in the background so that later searches get fresh data. Older episodes are fetched again.
+// Failed searches are not cached.
+// The season listings of GetEpisodes and GetSeason are cached too, and served as is for maxAge.
+// Pages sent with an ETag or a Last-Modified date are cached too, and fetched again with conditional requests: a 304 Not Modified answer is served from the cache.
+// The cache is bounded, the least recently used episodes are evicted first, see WithCacheLimits.
+func WithCache(maxAge, staleWhileRevalidate time.Duration) Option {
+	return func(c *Client) {
+		c.cache = &showCache{
+			maxAge:               maxAge,
+			staleWhileRevalidate: staleWhileRevalidate,
+			limits:               c.cacheLimits,
+			entries:              map[string]*cacheEntry{},
+			recent:               list.New(),
+		}
+	}
+}
+
+const (
+	// DefaultCacheEntries is the default maximum number of episodes in the cache
+	DefaultCacheEntries = 1000
+	// DefaultCacheSize is the default maximum approximate memory used by the episodes in the cache, in bytes
+	DefaultCacheSize = 32 << 20
+)
+
+// WithCacheLimits bounds the cache by number of episodes and by approximate memory used by the episodes, in bytes,
+// so that long-running processes don't grow unboundedly. The least recently used episodes are evicted first.
+// A limit of 0 or less removes the bound. Defaults are DefaultCacheEntries and DefaultCacheSize.
+// It has no effect without WithCache.
+func WithCacheLimits(maxEntries int, maxSize int64) Option {
+	return func(c *Client) {
+		c.cacheLimits = cacheLimits{maxEntries: maxEntries, maxSize: maxSize}
+		if c.cache != nil {
+			c.cache.limits = c.cacheLimits
+		}
+	}
+}
+
+type cacheLimits struct {
+	maxEntries int
+	maxSize    int64
+}
+
+// showCache is an in-memory LRU cache of episodes, keyed by search
+type showCache struct {
+	maxAge               time.Duration
+	staleWhileRevalidate time.Duration
+	limits               cacheLimits
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	// recent orders the keys of the entries, from the most recently used to the least recently used
+	recent *list.List
+	// size is the approximate memory used by the entries
+	size int64
+	// pages is the number of entries holding pages rather than episodes
+	pages int
+}
+
+type cacheEntry struct {
+	show Show
+	// page is the page cached with its validators, for entries of pages rather than episodes, see cachedPage
+	page *cachedPage
+	// episodes are the episodes of a season, for entries of season listings, and tvShow the show of a name warmed by
+	// WarmSeasons, for entries of shows
+	episodes  []Episode
+	tvShow    *TVShow
+	fetchedAt time.Time
+	// refreshing is true while the entry is refreshed in the background
+	refreshing bool
+	size       int64
+	element    *list.Element
+}
+
+// cachedShow returns the episode cached for key, using fetch to get it when it is not cached or too old.
+// Stale episodes are refreshed in the background with a new call, as the call itself may be cancelled as soon as it returns.
+func (c *call) cachedShow(key string, fetch func(c *call) (Show, error)) (Show, error) {
+	cache := c.cache
+	if cache == nil {
+		return fetch(c)
+	}
+
+	cache.mu.Lock()
+	entry, ok := cache.entries[key]
+	if ok {
+		age := time.Since(entry.fetchedAt)
+		if age <= cache.maxAge+cache.staleWhileRevalidate {
+			cache.recent.MoveToFront(entry.element)
+			show := entry.show
+			if age > cache.maxAge && !entry.refreshing {
+				entry.refreshing = true
+				go c.refreshShow(key, fetch)
+			}
+			cache.mu.Unlock()
+			c.tracef("Episode %v served from cache, fetched %v ago", key, age)
+			show.Subtitles = slices.Clone(show.Subtitles)
+			show.Translations = slices.Clone(show.Translations)
+			show.Warnings = slices.Clone(show.Warnings)
+			return show, nil
+		}
+	}
+	cache.mu.Unlock()
+
+	show, err := fetch(c)
+	if err == nil && !show.Partial {
+		cache.store(key, show)
+	}
+	return show, err
+}
+
+// refreshShow fetches an episode again and caches it, in the background
+func (c *call) refreshShow(key string, fetch func(c *call) (Show, error)) {
+	refresh := c.Client.newCall(context.Background(), nil)
+	refresh.level = c.level
+	refresh.tracef("Refreshing stale episode %v in the background", key)
+	show, err := fetch(refresh)
+	if err != nil {
+		refresh.errorf("Unable to refresh episode %v: %v", key, err)
+		c.cache.mu.Lock()
+		if entry, ok := c.cache.entries[key]; ok {
+			entry.refreshing = false
+		}
+		c.cache.mu.Unlock()
+		return
+	}
+	c.cache.store(key, show)
+}
+
+// store caches an episode, unless some of its versions could not be fetched
+func (sc *showCache) store(key string, show Show) {
+	if show.missingVersions {
+		return
+	}
+	sc.put(key, &cacheEntry{show: show, size: showSize(show)})
+}
+
+// cachedPage is a page of Addic7ed website, with the validators sent by Addic7ed to check whether it changed since
+type cachedPage struct {
+	body         []byte
+	etag         string
+	lastModified string
+}
+
+// pageKey is the key of the page of an URL in the cache, distinct from the keys of episodes
+func pageKey(url string) string {
+	return "page:" + url
+}
+
+// page returns the page cached for url, if any
+func (sc *showCache) page(url string) *cachedPage {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	entry, ok := sc.entries[pageKey(url)]
+	if !ok {
+		return nil
+	}
+	sc.recent.MoveToFront(entry.element)
+	return entry.page
+}
+
+func (sc *showCache) storePage(url string, page *cachedPage) {
+	size := int64(len(url) + len(page.body) + len(page.etag) + len(page.lastModified))
+	sc.put(pageKey(url), &cacheEntry{page: page, size: size})
+}
+
+func (sc *showCache) put(key string, entry *cacheEntry) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if old, ok := sc.entries[key]; ok {
+		sc.remove(key, old)
+	}
+	if sc.limits.maxSize > 0 && entry.size > sc.limits.maxSize {
+		return
+	}
+	entry.fetchedAt = time.Now()
+	entry.element = sc.recent.PushFront(key)
+	sc.entries[key] = entry
+	sc.size += entry.size
+	if entry.page != nil {
+		sc.pages++
+	}
+	for sc.overflows() {
+		oldest := sc.recent.Back().Value.(string)
+		sc.remove(oldest, sc.entries[oldest])
+	}
+}
+
+// overflows checks whether the cache is over its limits.
+// Pages are bounded like episodes, but separately, as there is about one page per episode
+func (sc *showCache) overflows() bool {
+	return (sc.limits.maxEntries > 0 && (len(sc.entries)-sc.pages > sc.limits.maxEntries || sc.pages > sc.limits.maxEntries)) ||
+		(sc.limits.maxSize > 0 && sc.size > sc.limits.maxSize)
+}
+
+func (sc *showCache) remove(key string, entry *cacheEntry) {
+	sc.recent.Remove(entry.element)
+	delete(sc.entries, key)
+	sc.size -= entry.size
+	if entry.page != nil {
+		sc.pages--
+	}
+}
+
+// subtitleOverhead is the approximate memory used by a subtitle, besides its strings
+const subtitleOverhead = 256
+
+// showSize approximates the memory used by an episode, from the length of its strings
+func showSize(show Show) int64 {
+	size := int64(len(show.Name) + len(show.Title) + len(show.showID) + len(show.showName))
+	for _, w := range show.Warnings {
+		size += int64(len(w.Code) + len(w.Message))
+	}
+	for _, t := range show.Translations {
+		size += subtitleOverhead + int64(len(t.Language)+len(t.Version)+len(t.Team)+len(t.Link))
+	}
+	for _, s := range show.Subtitles {
+		size += subtitleOverhead + int64(len(s.Language)+len(s.Version)+len(s.Link)+len(s.Uploader))
+		size += int64(len(s.VersionInfo.Raw) + len(s.VersionInfo.Group) + len(s.VersionInfo.Source) + len(s.VersionInfo.Resolution))
+		for _, flag := range s.VersionInfo.Flags {
+			size += int64(len(flag))
+		}
+		for _, variant := range s.variants {
+			size += int64(len(variant))
+		}
+	}
+	return size
+}
+
+// seasonKey is the key of the episodes of a season of a show in the cache
+func seasonKey(showID string, season int) string {
+	return fmt.Sprintf("season:%v:%v", showID, season)
+}
+
+// showKey is the key of a show in the cache, by its name whatever the case and the separators
+func showKey(name string) string {
+	return "show:" + normalizeShowName(name)
+}
+
+// episodes returns the episodes of a season cached for key, if any and fresh. A nil cache has no episodes
+func (sc *showCache) episodes(key string) ([]Episode, bool) {
+	if sc == nil {
+		return nil, false
+	}
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	entry, ok := sc.entries[key]
+	if !ok || entry.episodes == nil || time.Since(entry.fetchedAt) > sc.maxAge {
+		return nil, false
+	}
+	sc.recent.MoveToFront(entry.element)
+	episodes := slices.Clone(entry.episodes)
+	for i := range episodes {
+		episodes[i].Subtitles = slices.Clone(episodes[i].Subtitles)
+	}
+	return episodes, true
+}
+
+// storeEpisodes caches the episodes of a season of a show. A nil cache caches nothing
+func (sc *showCache) storeEpisodes(key string, episodes []Episode, showName string) {
+	if sc == nil {
+		return
+	}
+	size := int64(len(key))
+	for _, episode := range episodes {
+		size += showSize(episode.show(showName))
+	}
+	sc.put(key, &cacheEntry{episodes: episodes, size: size})
+}
+
+// warmedEpisode returns the episode of a search from the season listing cached by WarmSeasons, if any and fresh
+func (c *call) warmedEpisode(showStr string) (Show, bool) {
+	release := ParseRelease(c.mapEpisode(showStr))
+	if c.cache == nil || !release.HasEpisode() {
+		return Show{}, false
+	}
+	c.cache.mu.Lock()
+	entry, ok := c.cache.entries[showKey(release.Title)]
+	c.cache.mu.Unlock()
+	if !ok || entry.tvShow == nil {
+		return Show{}, false
+	}
+	tvShow := *entry.tvShow
+	episodes, ok := c.cache.episodes(seasonKey(tvShow.ID, release.Season))
+	if !ok {
+		return Show{}, false
+	}
+	for _, episode := range episodes {
+		if episode.Number == release.EpisodeNumber() {
+			show := episode.show(tvShow.Name)
+			show.showID, show.showName = tvShow.ID, tvShow.Name
+			return show, true
+		}
+	}
+	return Show{}, false
+}
+
+// WarmSeasons fetches the season listing of the latest season of the given shows and caches it, even if it is already cached,
+// so that later searches of its episodes and GetSeason hit the cache. Shows are given by Addic7ed name or id, like for GetShow.
+// The cache is in memory, so warm the cache of a long-running client, like the one of a Watcher or of a SonarrWebhook,
+// during off-peak hours: a process exiting right after warming the cache throws it away.
+// Warmed listings are served for the maxAge of the cache, see WithCache, so the subtitles uploaded since are missed until then.
+// All shows are tried, and the returned error joins the errors of the failed ones.
+// It does nothing when the client has no cache.
+func (c *Client) WarmSeasons(ctx context.Context, shows ...string) error {
+	if c.cache == nil {
+		return nil
+	}
+	var errs []error
+	for _, name := range shows {
+		if err := ctx.Err(); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		tvShow, err := c.GetShowContext(ctx, name)
+		if err == nil && len(tvShow.Seasons) == 0 {
+			err = newError(CodeNoSubtitlesYet, nil, "show %v does not have any season yet", tvShow.Name)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", name, err))
+			continue
+		}
+		season := tvShow.Seasons[len(tvShow.Seasons)-1]
+		episodes, err := c.newCall(ctx, nil).fetchEpisodes(tvShow, season)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", name, err))
+			continue
+		}
+		c.cache.storeEpisodes(seasonKey(tvShow.ID, season), episodes, tvShow.Name)
+		for _, key := range []string{showKey(name), showKey(tvShow.Name)} {
+			c.cache.put(key, &cacheEntry{tvShow: &tvShow, size: int64(len(key) + len(tvShow.ID) + len(tvShow.Name))})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WarmCache fetches the episodes of the given searches and caches them, like SearchAll, even if they are already cached.
+// The cache is in memory, so warm the cache of a long-running client during off-peak hours, see WarmSeasons.
+// All searches are tried, and the returned error joins the errors of the failed ones.
+// It does nothing when the client has no cache, see WithCache.
+func (c *Client) WarmCache(ctx context.Context, searches ...string) error {
+	if c.cache == nil {
+		return nil
+	}
+	var errs []error
+	for _, search := range searches {
+		if err := ctx.Err(); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		show, err := fetchSearch(search)(c.newCall(ctx, nil))
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%v: %w", search, err))
+			continue
+		}
+		c.cache.store(searchKey(search), show)
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,158 @@
+package addic7ed_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+// countSearches counts the searches served by a handler
+func countSearches(handler http.Handler, searches *int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/srch.php" {
+			atomic.AddInt64(searches, 1)
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func TestWithCache(t *testing.T) {
+	var searches int64
+	transport := handlerTransport{countSearches(episodeHandler(t, nil), &searches)}
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}), addic7ed.WithCache(time.Hour, 0))
+
+	for i := 0; i < 3; i++ {
+		show, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+		assert.NoError(t, err)
+		assert.Len(t, show.Subtitles, 4)
+	}
+	assert.Equal(t, int64(1), atomic.LoadInt64(&searches))
+}
+
+func TestWithCacheRevalidatesStaleEpisodes(t *testing.T) {
+	var searches int64
+	transport := handlerTransport{countSearches(episodeHandler(t, nil), &searches)}
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}), addic7ed.WithCache(0, time.Hour))
+
+	_, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+	// The stale episode is served right away, and refreshed in the background
+	show, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+	assert.Len(t, show.Subtitles, 4)
+	for deadline := time.Now().Add(time.Second); atomic.LoadInt64(&searches) < 2 && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, int64(2), atomic.LoadInt64(&searches))
+}
+
+func TestWarmCache(t *testing.T) {
+	var searches int64
+	transport := handlerTransport{countSearches(episodeHandler(t, nil), &searches)}
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}), addic7ed.WithCache(time.Hour, 0))
+
+	assert.NoError(t, c.WarmCache(context.Background(), "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]"))
+	assert.Equal(t, int64(1), atomic.LoadInt64(&searches))
+	_, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&searches))
+
+	failing := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: failingTransport{}}), addic7ed.WithCache(time.Hour, 0), addic7ed.WithoutRetry())
+	err = failing.WarmCache(context.Background(), "Shameless.US.S08E11", "Shameless.US.S08E12")
+	assert.True(t, errors.Is(err, addic7ed.ErrServerUnreachable), "unexpected error %v", err)
+	assert.Contains(t, err.Error(), "Shameless.US.S08E12")
+}
+
+func TestWarmSeasons(t *testing.T) {
+	var searches, seasons int64
+	handler := showHandler(t)
+	transport := handlerTransport{countSearches(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("season") != "" {
+			atomic.AddInt64(&seasons, 1)
+		}
+		handler.ServeHTTP(w, r)
+	}), &searches)}
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}), addic7ed.WithCache(time.Hour, 0))
+
+	// The latest season of the show is fetched once, and its episodes are served from the cache
+	assert.NoError(t, c.WarmSeasons(context.Background(), "5427"))
+	assert.Equal(t, int64(1), atomic.LoadInt64(&seasons))
+	_, subtitle, err := c.SearchBest("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", "English")
+	assert.NoError(t, err)
+	assert.Equal(t, "BATV", subtitle.Version)
+	_, err = c.GetSeason(addic7ed.TVShow{ID: "5427", Name: "Shameless (US)"}, 8)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), atomic.LoadInt64(&searches))
+	assert.Equal(t, int64(1), atomic.LoadInt64(&seasons))
+
+	// Episodes missing from the listing are searched (the fixture only serves S08E11)
+	_, err = c.SearchAll("Shameless.US.S08E01.720p.HDTV.x264-BATV[ettv]")
+	assert.Error(t, err)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&searches))
+
+	// Without cache, nothing is warmed
+	assert.NoError(t, addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport})).WarmSeasons(context.Background(), "5427"))
+	assert.Equal(t, int64(1), atomic.LoadInt64(&seasons))
+}
+
+func TestWithCacheLimits(t *testing.T) {
+	var searches int64
+	transport := handlerTransport{countSearches(episodeHandler(t, nil), &searches)}
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}), addic7ed.WithCacheLimits(1, 0), addic7ed.WithCache(time.Hour, 0))
+
+	for _, search := range []string{"Shameless.US.S08E11.720p", "Shameless.US.S08E11.1080p", "Shameless.US.S08E11.1080p", "Shameless.US.S08E11.720p"} {
+		_, err := c.SearchAll(search)
+		assert.NoError(t, err)
+	}
+	// Only the last episode is kept, so the first one is fetched again
+	assert.Equal(t, int64(3), atomic.LoadInt64(&searches))
+
+	searches = 0
+	small := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}), addic7ed.WithCache(time.Hour, 0), addic7ed.WithCacheLimits(0, 100))
+	for i := 0; i < 2; i++ {
+		_, err := small.SearchAll("Shameless.US.S08E11.720p")
+		assert.NoError(t, err)
+	}
+	// The episode is bigger than the cache, so it is never cached
+	assert.Equal(t, int64(2), atomic.LoadInt64(&searches))
+}
+
+func TestWithCacheRevalidatesPages(t *testing.T) {
+	var notModified int64
+	handler := episodeHandler(t, nil)
+	transport := handlerTransport{http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt64(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		handler.ServeHTTP(w, r)
+	})}
+	// Episodes are never fresh, so every search gets the page again
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}), addic7ed.WithCache(0, 0))
+
+	for i := 0; i < 3; i++ {
+		show, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+		assert.NoError(t, err)
+		assert.Len(t, show.Subtitles, 4)
+	}
+	assert.Equal(t, int64(2), atomic.LoadInt64(&notModified))
+	assert.Equal(t, 3, c.Usage().Searches)
+
+	// Without cache, requests are never conditional
+	notModified = 0
+	uncached := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}))
+	for i := 0; i < 2; i++ {
+		_, err := uncached.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int64(0), atomic.LoadInt64(&notModified))
+}
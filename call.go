@@ -0,0 +1,68 @@
+package addic7ed
+
+import "context"
+
+// CallOption changes the behavior of a single call of the client, for example c.SearchBest(showStr, lang, addic7ed.WithTrace())
+type CallOption func(*call)
+
+// WithTrace enables the most verbose logs for a single call, whatever the log level of the client
+func WithTrace() CallOption {
+	return func(c *call) {
+		c.level = LevelTrace
+	}
+}
+
+// WithBestEffort returns the subtitles parsed so far when the context of the call is done, typically past its deadline,
+// instead of the error of the context, for interactive applications with strict latency budgets:
+//
+//	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+//	defer cancel()
+//	show, err := c.SearchAllContext(ctx, showStr, addic7ed.WithBestEffort())
+//
+// Pages cut by the deadline are parsed as is, and pages not fetched yet are skipped. The results are then flagged Partial,
+// with a WarningPartialResult, and never cached. The call still fails when nothing was parsed before the deadline.
+func WithBestEffort() CallOption {
+	return func(c *call) {
+		c.bestEffort = true
+	}
+}
+
+// call holds the settings of a single call of the client
+// Settings of the client can be overridden for the call without impacting other concurrent calls
+type call struct {
+	*Client
+	ctx   context.Context
+	level LogLevel
+	// bestEffort and partial tell whether the call returns partial results, and whether it did, see WithBestEffort
+	bestEffort bool
+	partial    bool
+	// show is the normalized name of the show the call is about, for its rate limits, see WithShowPeakRateLimit
+	show string
+
+	// warnings are the warnings raised during the call
+	warnings       []Warning
+	warningHandler func(w Warning)
+}
+
+func (c *Client) newCall(ctx context.Context, opts []CallOption) *call {
+	call := &call{
+		Client: c,
+		ctx:    ctx,
+		level:  c.level,
+	}
+	for _, opt := range opts {
+		opt(call)
+	}
+	return call
+}
+
+// cutShort checks whether the call can return partial results, as its context is done, see WithBestEffort
+func (c *call) cutShort() bool {
+	return c.bestEffort && c.ctx.Err() != nil
+}
+
+// partialf flags the results of the call as partial, raising a WarningPartialResult
+func (c *call) partialf(message string, params ...interface{}) {
+	c.partial = true
+	c.warn(WarningPartialResult, message, params...)
+}
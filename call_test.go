@@ -0,0 +1,87 @@
+package addic7ed_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+// cutHandler serves the beginning of a page, up to a marker, then hangs until the request is cancelled
+func cutHandler(t *testing.T, path, marker string) http.Handler {
+	page, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := bytes.Index(page, []byte(marker))
+	if at < 0 {
+		t.Fatalf("marker %q not found in %v", marker, path)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(page[:at])
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	})
+}
+
+func TestWithBestEffort(t *testing.T) {
+	server := httptest.NewServer(cutHandler(t, "testdata/episode.html", `href="/original/131967/2"`))
+	defer server.Close()
+	c := addic7ed.New(addic7ed.WithBaseURL(server.URL), addic7ed.WithCache(time.Hour, 0))
+
+	var warnings []addic7ed.Warning
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	show, err := c.SearchAllContext(ctx, "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", addic7ed.WithBestEffort(), addic7ed.WithWarnings(func(w addic7ed.Warning) {
+		warnings = append(warnings, w)
+	}))
+	assert.NoError(t, err)
+	assert.True(t, show.Partial)
+	assert.NotEmpty(t, show.Subtitles)
+	assert.True(t, len(show.Subtitles) < 4, "unexpected subtitles %v", show.Subtitles)
+	assert.Len(t, warnings, 1)
+	assert.Equal(t, addic7ed.WarningPartialResult, warnings[0].Code)
+
+	// Partial results are not cached, and calls fail without best effort
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = c.SearchAllContext(ctx, "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.True(t, errors.Is(err, addic7ed.ErrParseFailure), "unexpected error %v", err)
+}
+
+func TestWithBestEffortWithoutAnyResult(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	c := addic7ed.New(addic7ed.WithBaseURL(server.URL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.SearchAllContext(ctx, "Shameless.US.S08E11", addic7ed.WithBestEffort())
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "unexpected error %v", err)
+}
+
+func TestGetSeasonWithBestEffort(t *testing.T) {
+	// Cut the page after its first episode
+	server := httptest.NewServer(cutHandler(t, "testdata/season.html", "<td>8</td><td>12</td>"))
+	defer server.Close()
+	c := addic7ed.New(addic7ed.WithBaseURL(server.URL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	episodes, err := c.GetSeasonContext(ctx, addic7ed.TVShow{ID: "5427", Name: "Shameless (US)"}, 8, addic7ed.WithBestEffort())
+	assert.NoError(t, err)
+	assert.Len(t, episodes, 1)
+	for _, episode := range episodes {
+		assert.True(t, episode.Partial)
+	}
+}
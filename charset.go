@@ -0,0 +1,299 @@
+package addic7ed
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Charset is the character encoding of a subtitle
+type Charset string
+
+const (
+	// CharsetUTF8 is the UTF-8 encoding, with or without BOM
+	CharsetUTF8 Charset = "utf-8"
+	// CharsetUTF16LE is the little-endian UTF-16 encoding, usually written by Windows tools
+	CharsetUTF16LE Charset = "utf-16le"
+	// CharsetUTF16BE is the big-endian UTF-16 encoding
+	CharsetUTF16BE Charset = "utf-16be"
+	// CharsetWindows1252 is the Windows-1252 encoding, a superset of ISO-8859-1, used by most subtitles that are not UTF-8
+	CharsetWindows1252 Charset = "windows-1252"
+	// CharsetWindows1250 is the Windows-1250 encoding, for Central and Eastern European languages
+	CharsetWindows1250 Charset = "windows-1250"
+	// CharsetWindows1251 is the Windows-1251 encoding, for Cyrillic scripts
+	CharsetWindows1251 Charset = "windows-1251"
+	// CharsetWindows1253 is the Windows-1253 encoding, for Greek
+	CharsetWindows1253 Charset = "windows-1253"
+	// CharsetWindows1254 is the Windows-1254 encoding, for Turkish
+	CharsetWindows1254 Charset = "windows-1254"
+	// CharsetWindows1255 is the Windows-1255 encoding, for Hebrew
+	CharsetWindows1255 Charset = "windows-1255"
+	// CharsetWindows1256 is the Windows-1256 encoding, for Arabic
+	CharsetWindows1256 Charset = "windows-1256"
+)
+
+var (
+	utf16LEBOM = []byte("\xff\xfe")
+	utf16BEBOM = []byte("\xfe\xff")
+)
+
+// windows1252 maps the bytes from 0x80 to 0x9f of Windows-1252 to their runes. Other bytes are the same as ISO-8859-1
+var windows1252 = [32]rune{
+	'€', '\u0081', '‚', 'ƒ', '„', '…', '†', '‡', 'ˆ', '‰', 'Š', '‹', 'Œ', '\u008d', 'Ž', '\u008f',
+	'\u0090', '‘', '’', '“', '”', '•', '–', '—', '˜', '™', 'š', '›', 'œ', '\u009d', 'ž', 'Ÿ',
+}
+
+// DetectCharset sniffs the character encoding of a subtitle, from its BOM or its content.
+// Files that are neither UTF-8 nor UTF-16 are considered as Windows-1252, the most common encoding on Addic7ed after UTF-8.
+func DetectCharset(data []byte) Charset {
+	switch {
+	case bytes.HasPrefix(data, utf8BOM):
+		return CharsetUTF8
+	case bytes.HasPrefix(data, utf16LEBOM):
+		return CharsetUTF16LE
+	case bytes.HasPrefix(data, utf16BEBOM):
+		return CharsetUTF16BE
+	// Without BOM, UTF-16 text in latin scripts has a zero byte in each character
+	case len(data) >= 4 && data[0] != 0 && data[1] == 0 && data[2] != 0 && data[3] == 0:
+		return CharsetUTF16LE
+	case len(data) >= 4 && data[0] == 0 && data[1] != 0 && data[2] == 0 && data[3] != 0:
+		return CharsetUTF16BE
+	case utf8.Valid(data):
+		return CharsetUTF8
+	}
+	return CharsetWindows1252
+}
+
+// ToUTF8 converts a subtitle to UTF-8 without BOM, whatever its encoding (see DetectCharset)
+func ToUTF8(data []byte) []byte {
+	switch DetectCharset(data) {
+	case CharsetUTF16LE:
+		return decodeUTF16(bytes.TrimPrefix(data, utf16LEBOM), binary.LittleEndian)
+	case CharsetUTF16BE:
+		return decodeUTF16(bytes.TrimPrefix(data, utf16BEBOM), binary.BigEndian)
+	case CharsetWindows1252:
+		return decodeWindows1252(data)
+	}
+	return bytes.TrimPrefix(data, utf8BOM)
+}
+
+// languageCharsets are the legacy encodings of the subtitles of languages by ISO 639-1 code, when they are not Unicode.
+// Subtitles of the other languages are usually in Windows-1252.
+var languageCharsets = map[string]Charset{
+	"cs": CharsetWindows1250, "hr": CharsetWindows1250, "hu": CharsetWindows1250, "pl": CharsetWindows1250,
+	"ro": CharsetWindows1250, "sk": CharsetWindows1250, "sl": CharsetWindows1250,
+	"bg": CharsetWindows1251, "mk": CharsetWindows1251, "ru": CharsetWindows1251, "uk": CharsetWindows1251,
+	"el": CharsetWindows1253,
+	"tr": CharsetWindows1254,
+	"he": CharsetWindows1255,
+	"ar": CharsetWindows1256, "fa": CharsetWindows1256,
+}
+
+// legacyCharset returns the encoding of the subtitles of a language that are neither UTF-8 nor UTF-16: the code page set
+// for the language by WithEncodings, or else the usual code page of the language, Windows-1252 by default
+func (c *Client) legacyCharset(lang string) Charset {
+	if charset, ok := c.encodingFor(lang); ok && codePages[charset] != nil {
+		return charset
+	}
+	if charset, ok := languageCharsets[languageCode(lang)]; ok {
+		return charset
+	}
+	return CharsetWindows1252
+}
+
+// convertsLegacy checks whether the subtitles of a language in a legacy encoding are converted when downloaded,
+// with WithUTF8, WithRTLMarks or WithEncodings
+func (c *Client) convertsLegacy(lang string) bool {
+	charset, ok := c.encodingFor(lang)
+	return c.toUTF8 || c.rtlMarks || (ok && charset != c.legacyCharset(lang))
+}
+
+// decode converts a subtitle of a language to UTF-8 like ToUTF8, decoding subtitles that are neither UTF-8 nor UTF-16
+// with the legacy encoding of the language, so that a Greek subtitle in Windows-1253 is not read as Windows-1252
+func (c *Client) decode(data []byte, lang string) []byte {
+	if DetectCharset(data) != CharsetWindows1252 {
+		return ToUTF8(data)
+	}
+	return decodeCodePage(data, codePages[c.legacyCharset(lang)])
+}
+
+// encode converts a subtitle of a language to an encoding, see FromUTF8.
+// Subtitles already in the encoding are returned as is.
+func (c *Client) encode(data []byte, lang string, charset Charset) ([]byte, error) {
+	if DetectCharset(data) == CharsetWindows1252 && c.legacyCharset(lang) == charset {
+		return data, nil
+	}
+	return FromUTF8(c.decode(data, lang), charset)
+}
+
+func decodeUTF16(data []byte, order binary.ByteOrder) []byte {
+	units := make([]uint16, len(data)/2)
+	for i := range units {
+		units[i] = order.Uint16(data[2*i:])
+	}
+	runes := utf16.Decode(units)
+	if len(runes) > 0 && runes[0] == '\ufeff' {
+		runes = runes[1:]
+	}
+	return []byte(string(runes))
+}
+
+// decodeCodePage decodes a subtitle in a single-byte encoding, undefined bytes being decoded as '\ufffd'
+func decodeCodePage(data []byte, page *[128]rune) []byte {
+	decoded := make([]byte, 0, len(data)+len(data)/2)
+	for _, b := range data {
+		r := rune(b)
+		if b >= 0x80 {
+			r = page[b-0x80]
+		}
+		decoded = utf8.AppendRune(decoded, r)
+	}
+	return decoded
+}
+
+func decodeWindows1252(data []byte) []byte {
+	decoded := make([]byte, 0, len(data)+len(data)/8)
+	for _, b := range data {
+		r := rune(b)
+		if b >= 0x80 && b <= 0x9f {
+			r = windows1252[b-0x80]
+		}
+		decoded = utf8.AppendRune(decoded, r)
+	}
+	return decoded
+}
+
+// WithUTF8 converts downloaded subtitles to UTF-8 without BOM, so that players don't show garbled characters. See ToUTF8
+func WithUTF8() Option {
+	return func(c *Client) {
+		c.toUTF8 = true
+	}
+}
+
+// FromUTF8 converts a UTF-8 subtitle to another encoding, for players that only read legacy encodings.
+// Supported encodings are UTF-8 (written without BOM), UTF-16 (written with BOM) and the Windows code pages of this package.
+// Characters missing from the encoding are replaced by "?".
+// The encoding declared by the styles of ASS and SSA subtitles is updated to match, so that players pick the right code page.
+func FromUTF8(data []byte, charset Charset) ([]byte, error) {
+	data = bytes.TrimPrefix(data, utf8BOM)
+	if format := DetectFormat(data); format == FormatASS || format == FormatSSA {
+		data = setASSEncoding(data, charset)
+	}
+	switch charset {
+	case CharsetUTF8:
+		return data, nil
+	case CharsetUTF16LE:
+		return encodeUTF16(data, binary.LittleEndian, utf16LEBOM), nil
+	case CharsetUTF16BE:
+		return encodeUTF16(data, binary.BigEndian, utf16BEBOM), nil
+	}
+	page, ok := codePages[charset]
+	if !ok {
+		return nil, fmt.Errorf("encoding %q is not supported", charset)
+	}
+	bytesByRune := make(map[rune]byte, len(page))
+	for i, r := range page {
+		if r != utf8.RuneError {
+			bytesByRune[r] = byte(0x80 + i)
+		}
+	}
+	encoded := make([]byte, 0, len(data))
+	for _, r := range string(data) {
+		switch b, ok := bytesByRune[r]; {
+		case r < 0x80:
+			encoded = append(encoded, byte(r))
+		case ok:
+			encoded = append(encoded, b)
+		default:
+			encoded = append(encoded, '?')
+		}
+	}
+	return encoded, nil
+}
+
+func encodeUTF16(data []byte, order binary.AppendByteOrder, bom []byte) []byte {
+	units := utf16.Encode([]rune(string(data)))
+	encoded := make([]byte, len(bom), len(bom)+2*len(units))
+	copy(encoded, bom)
+	for _, unit := range units {
+		encoded = order.AppendUint16(encoded, unit)
+	}
+	return encoded
+}
+
+// assEncodings are the values of the Encoding field of ASS and SSA styles for each encoding, like the charsets of Windows fonts
+var assEncodings = map[Charset]int{
+	CharsetUTF8:        1,
+	CharsetUTF16LE:     1,
+	CharsetUTF16BE:     1,
+	CharsetWindows1252: 0,
+	CharsetWindows1250: 238,
+	CharsetWindows1251: 204,
+	CharsetWindows1253: 161,
+	CharsetWindows1254: 162,
+	CharsetWindows1255: 177,
+	CharsetWindows1256: 178,
+}
+
+// setASSEncoding sets the Encoding field of the styles of an ASS or SSA subtitle
+func setASSEncoding(data []byte, charset Charset) []byte {
+	encoding, ok := assEncodings[charset]
+	if !ok {
+		return data
+	}
+	lines := strings.SplitAfter(string(data), "\n")
+	field := -1
+	var fields int
+	for i, line := range lines {
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		switch strings.TrimSpace(name) {
+		case "Format":
+			names := strings.Split(value, ",")
+			fields, field = len(names), -1
+			for j, name := range names {
+				if strings.EqualFold(strings.TrimSpace(name), "Encoding") {
+					field = j
+				}
+			}
+		case "Style":
+			values := strings.SplitN(value, ",", fields)
+			if field < 0 || field >= len(values) {
+				continue
+			}
+			old := values[field]
+			// Keep the end of line of the last field
+			values[field] = strconv.Itoa(encoding) + old[len(strings.TrimRight(old, "\r\n")):]
+			lines[i] = name + ":" + strings.Join(values, ",")
+		}
+	}
+	return []byte(strings.Join(lines, ""))
+}
+
+// WithEncodings converts downloaded subtitles to an encoding depending on their language, for players that show garbled characters otherwise,
+// usually older hardware expecting legacy encodings like Windows-1253 for Greek or Windows-1255 for Hebrew.
+// rules maps languages, by name like "Greek" or by ISO 639-1 code like "el", to encodings. See FromUTF8.
+// Subtitles in other languages are left as is.
+func WithEncodings(rules map[string]Charset) Option {
+	return func(c *Client) {
+		c.encodings = map[string]Charset{}
+		for lang, charset := range rules {
+			c.encodings[strings.ToLower(lang)] = charset
+		}
+	}
+}
+
+// encodingFor returns the encoding of the subtitles of a language, see WithEncodings
+func (c *Client) encodingFor(lang string) (Charset, bool) {
+	if charset, ok := c.encodings[strings.ToLower(lang)]; ok {
+		return charset, true
+	}
+	charset, ok := c.encodings[strings.ToLower(languageCode(lang))]
+	return charset, ok
+}
@@ -0,0 +1,181 @@
+package addic7ed_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+func TestToUTF8(t *testing.T) {
+	var charsettests = []struct {
+		in       string
+		charset  addic7ed.Charset
+		expected string
+	}{
+		{"\xef\xbb\xbfD\xc3\xa9j\xc3\xa0 vu", addic7ed.CharsetUTF8, "Déjà vu"},
+		{"D\xe9j\xe0 vu \x93oui\x94 \x80", addic7ed.CharsetWindows1252, "Déjà vu “oui” €"},
+		{"\xff\xfeD\x00\xe9\x00j\x00\xe0\x00", addic7ed.CharsetUTF16LE, "Déjà"},
+		{"\x00D\x00\xe9\x00j\x00\xe0", addic7ed.CharsetUTF16BE, "Déjà"},
+		{"", addic7ed.CharsetUTF8, ""},
+	}
+	for _, test := range charsettests {
+		assert.Equal(t, test.charset, addic7ed.DetectCharset([]byte(test.in)), test.in)
+		assert.Equal(t, test.expected, string(addic7ed.ToUTF8([]byte(test.in))), test.in)
+	}
+}
+
+func TestDownloadWithUTF8(t *testing.T) {
+	const srt = "1\n00:00:01,000 --> 00:00:02,000\nD\xe9j\xe0 vu\n"
+	server := httptest.NewServer(episodeHandler(t, map[string]string{"/original/131967/0": srt}))
+	defer server.Close()
+
+	c := addic7ed.New(addic7ed.WithBaseURL(server.URL), addic7ed.WithUTF8())
+	show, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+	content, err := show.Subtitles[0].Download()
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(content)
+	assert.Equal(t, "1\n00:00:01,000 --> 00:00:02,000\nDéjà vu\n", string(data))
+}
+
+func TestDownloadUTF16Subtitles(t *testing.T) {
+	utf16, err := addic7ed.FromUTF8([]byte("1\n00:00:01,000 --> 00:00:02,000\nDéjà vu\n"), addic7ed.CharsetUTF16LE)
+	assert.NoError(t, err)
+	server := httptest.NewServer(episodeHandler(t, map[string]string{"/original/131967/0": string(utf16)}))
+	defer server.Close()
+
+	// Without WithUTF8, UTF-16 subtitles are recognized and kept as is
+	c := addic7ed.New(addic7ed.WithBaseURL(server.URL))
+	show, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+	result, err := show.Subtitles[0].Fetch()
+	assert.NoError(t, err)
+	assert.Equal(t, addic7ed.FormatSRT, result.Format)
+	assert.Equal(t, utf16, result.Data)
+
+	path, err := show.Subtitles[0].DownloadAlongside(t.TempDir()+"/Shameless.US.S08E11.mkv", false)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasSuffix(path, ".srt"), path)
+}
+
+func TestFromUTF8(t *testing.T) {
+	var charsettests = []struct {
+		in       string
+		charset  addic7ed.Charset
+		expected string
+	}{
+		{"\xef\xbb\xbfDéjà vu", addic7ed.CharsetUTF8, "Déjà vu"},
+		{"Déjà vu “oui” €", addic7ed.CharsetWindows1252, "D\xe9j\xe0 vu \x93oui\x94 \x80"},
+		{"Γειά σου", addic7ed.CharsetWindows1253, "\xc3\xe5\xe9\xdc \xf3\xef\xf5"},
+		{"שלום", addic7ed.CharsetWindows1255, "\xf9\xec\xe5\xed"},
+		{"Привет, école", addic7ed.CharsetWindows1251, "\xcf\xf0\xe8\xe2\xe5\xf2, ?cole"},
+		{"Déjà", addic7ed.CharsetUTF16LE, "\xff\xfeD\x00\xe9\x00j\x00\xe0\x00"},
+	}
+	for _, test := range charsettests {
+		encoded, err := addic7ed.FromUTF8([]byte(test.in), test.charset)
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, string(encoded), test.in)
+	}
+
+	_, err := addic7ed.FromUTF8([]byte("Déjà"), addic7ed.Charset("koi8-r"))
+	assert.Error(t, err)
+}
+
+func TestFromUTF8UpdatesASSEncoding(t *testing.T) {
+	const ass = "[Script Info]\r\nScriptType: v4.00+\r\n\r\n[V4+ Styles]\r\n" +
+		"Format: Name, Fontname, Fontsize, Bold, Encoding\r\nStyle: Default,Arial,16,0,1\r\nStyle: Top,Arial,16,0,0\r\n\r\n" +
+		"[Events]\r\nFormat: Layer, Start, End, Style, Text\r\nDialogue: 0,0:00:01.00,0:00:02.00,Default,Γειά, σου\r\n"
+	encoded, err := addic7ed.FromUTF8([]byte(ass), addic7ed.CharsetWindows1253)
+	assert.NoError(t, err)
+	expected := "[Script Info]\r\nScriptType: v4.00+\r\n\r\n[V4+ Styles]\r\n" +
+		"Format: Name, Fontname, Fontsize, Bold, Encoding\r\nStyle: Default,Arial,16,0,161\r\nStyle: Top,Arial,16,0,161\r\n\r\n" +
+		"[Events]\r\nFormat: Layer, Start, End, Style, Text\r\nDialogue: 0,0:00:01.00,0:00:02.00,Default,\xc3\xe5\xe9\xdc, \xf3\xef\xf5\r\n"
+	assert.Equal(t, expected, string(encoded))
+}
+
+func TestDownloadWithEncodings(t *testing.T) {
+	const srt = "1\n00:00:01,000 --> 00:00:02,000\nDéjà vu\n"
+	server := httptest.NewServer(episodeHandler(t, map[string]string{"/original/131967/0": srt, "/original/131967/1": srt}))
+	defer server.Close()
+
+	c := addic7ed.New(addic7ed.WithBaseURL(server.URL), addic7ed.WithEncodings(map[string]addic7ed.Charset{"fr": addic7ed.CharsetWindows1252}))
+	show, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+	for lang, expected := range map[string]string{"English": srt, "French": "1\n00:00:01,000 --> 00:00:02,000\nD\xe9j\xe0 vu\n"} {
+		subtitles := show.Subtitles.Filter(addic7ed.WithLanguage(lang))
+		content, err := subtitles[0].Download()
+		assert.NoError(t, err)
+		data, _ := io.ReadAll(content)
+		assert.Equal(t, expected, string(data), lang)
+	}
+}
+
+// languageHandler serves the episode fixture with its English subtitles renamed to another language, and their content
+func languageHandler(t *testing.T, lang, content string) http.Handler {
+	page, err := os.ReadFile("testdata/episode.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := episodeHandler(t, map[string]string{"/original/131967/0": content})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/srch.php" {
+			w.Write([]byte(strings.ReplaceAll(string(page), "English", lang)))
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func TestDownloadLegacySubtitles(t *testing.T) {
+	// "Γειά σου" in Windows-1253, served as the English subtitles of the fixture renamed as Greek
+	const greek = "1\n00:00:01,000 --> 00:00:02,000\n\xc3\xe5\xe9\xdc \xf3\xef\xf5\n"
+	server := httptest.NewServer(languageHandler(t, "Greek", greek))
+	defer server.Close()
+
+	const decoded = "1\n00:00:01,000 --> 00:00:02,000\nΓειά σου\n"
+	for name, test := range map[string]struct {
+		option   addic7ed.Option
+		expected string
+		guessed  bool
+	}{
+		"same encoding": {addic7ed.WithEncodings(map[string]addic7ed.Charset{"Greek": addic7ed.CharsetWindows1253}), greek, false},
+		"to utf-8":      {addic7ed.WithEncodings(map[string]addic7ed.Charset{"el": addic7ed.CharsetUTF8}), decoded, true},
+		"with utf-8":    {addic7ed.WithUTF8(), decoded, true},
+	} {
+		c := addic7ed.New(addic7ed.WithBaseURL(server.URL), test.option)
+		show, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+		assert.NoError(t, err)
+		subtitles := show.Subtitles.Filter(addic7ed.WithLanguage("el"))
+		if !assert.NotEmpty(t, subtitles, name) {
+			continue
+		}
+		result, err := subtitles[0].Fetch()
+		assert.NoError(t, err)
+		assert.Equal(t, test.expected, string(result.Data), name)
+		if test.guessed && assert.Len(t, result.Warnings, 1, name) {
+			assert.Equal(t, addic7ed.WarningEncodingGuessed, result.Warnings[0].Code)
+			assert.Contains(t, result.Warnings[0].Message, string(addic7ed.CharsetWindows1253))
+		} else if !test.guessed {
+			assert.Empty(t, result.Warnings, name)
+		}
+	}
+}
+
+func FuzzToUTF8(f *testing.F) {
+	f.Add([]byte("D\xe9j\xe0 vu"))
+	f.Add([]byte("\xff\xfeD\x00\xe9"))
+	f.Add([]byte("\xfe\xff\xd8\x00"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if converted := addic7ed.ToUTF8(data); !utf8.Valid(converted) {
+			t.Fatalf("converted %q to invalid UTF-8 %q", data, converted)
+		}
+	})
+}
@@ -0,0 +1,46 @@
+package addic7ed
+
+import "unicode"
+
+// DefaultCJKNGram is the size of the n-grams that the words in Chinese, Japanese or Korean are split into by JaroWinklerScorer
+const DefaultCJKNGram = 2
+
+// isCJK checks whether a rune is written in a script without spaces between words, like Chinese or Japanese
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul, unicode.Bopomofo)
+}
+
+// splitScripts splits a word where it switches between a CJK script and another script, like "Shameless第八季" in "Shameless" and "第八季"
+func splitScripts(word string) []string {
+	var words []string
+	start := 0
+	var previous rune
+	for i, r := range word {
+		if i > start && isCJK(r) != isCJK(previous) {
+			words = append(words, word[start:i])
+			start = i
+		}
+		previous = r
+	}
+	return append(words, word[start:])
+}
+
+// cjkNGrams replaces the CJK words longer than n by their n-grams, as these words usually are whole sentences.
+// "無恥之徒" is replaced by "無恥", "恥之" and "之徒" for n = 2. Other words are kept as is. A n of 0 or less keeps all words.
+func cjkNGrams(words []string, n int) []string {
+	if n <= 0 {
+		return words
+	}
+	split := make([]string, 0, len(words))
+	for _, word := range words {
+		runes := []rune(word)
+		if len(runes) <= n || !isCJK(runes[0]) {
+			split = append(split, word)
+			continue
+		}
+		for i := 0; i+n <= len(runes); i++ {
+			split = append(split, string(runes[i:i+n]))
+		}
+	}
+	return split
+}
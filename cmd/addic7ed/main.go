@@ -0,0 +1,623 @@
+// Command addic7ed searches and downloads subtitles from Addic7ed.
+//
+// Usage:
+//
+//	addic7ed search [flags] <file or search>
+//	addic7ed download [flags] <file or search>...
+//	find /media -name '*.mkv' | addic7ed download [flags] -
+//	addic7ed batch [flags] <directory>
+//	addic7ed watch [flags] <directory>[=language]...
+//	addic7ed clean [flags] <directory>
+//	addic7ed serve [flags] [<directory>...]
+//	addic7ed accuracy [flags] <cases.csv>
+//	addic7ed availability [flags] <show> <season> [<language>...]
+//
+// Run a command with -h to get its flags.
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/matcornic/addic7ed"
+)
+
+const usage = `addic7ed searches and downloads subtitles from Addic7ed.
+
+Usage:
+
+	addic7ed search [flags] <file or search>       list the subtitles of an episode
+	addic7ed download [flags] <file or search>...  download the best subtitle of each file, or of each line of stdin for "-"
+	addic7ed batch [flags] <directory>             download the best subtitle of the videos missing one
+	addic7ed watch [flags] <directory>[=lang]...   download the best subtitle of new videos, until interrupted
+	addic7ed clean [flags] <directory>             remove the subtitles whose video no longer exists
+	addic7ed serve [flags] [<directory>...]        download the subtitles of the episodes imported by Sonarr
+	addic7ed accuracy [flags] <cases.csv>          report how often the expected versions of files are chosen
+	addic7ed availability [flags] <show> <season> [<lang>...]
+	                                               list the available subtitles of a season by episode and language
+
+Run a command with -h to get its flags.
+`
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs a command with its arguments, returning the exit status: 0 on success, 1 on failures, 2 on usage errors.
+// The options configure the client, after the options of the flags.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer, opts ...addic7ed.Option) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	commands := map[string]func(ctx context.Context, cmd *command, args []string) error{
+		"search":       search,
+		"download":     download,
+		"batch":        batch,
+		"watch":        watch,
+		"clean":        clean,
+		"serve":        serve,
+		"accuracy":     accuracy,
+		"availability": availability,
+	}
+	name := args[0]
+	if name == "-h" || name == "-help" || name == "help" {
+		fmt.Fprint(stdout, usage)
+		return 0
+	}
+	runCommand, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n%v", name, usage)
+		return 2
+	}
+
+	cmd := &command{flags: flag.NewFlagSet(name, flag.ContinueOnError), stdin: stdin, stdout: stdout, stderr: stderr}
+	cmd.flags.SetOutput(stderr)
+	if name != "availability" {
+		cmd.flags.StringVar(&cmd.lang, "lang", "English", "language of the subtitles, by name or ISO 639-1 code")
+	}
+	cmd.flags.BoolVar(&cmd.json, "json", false, "write results as JSON, one object per line")
+	jellyfin := false
+	if name != "search" && name != "clean" && name != "accuracy" && name != "availability" {
+		cmd.flags.StringVar(&cmd.output, "o", defaultTemplate, "template of the paths of the downloaded subtitles, with "+strings.Join(placeholders, ", "))
+		cmd.flags.BoolVar(&jellyfin, "jellyfin", false, "name the subtitles with the language and the flags read by Jellyfin, like -o "+jellyfinTemplate)
+		cmd.flags.BoolVar(&cmd.flagged.isDefault, "default", false, "flag the subtitles as default tracks in {flags}")
+		cmd.flags.BoolVar(&cmd.flagged.forced, "forced", false, "flag the subtitles as forced tracks in {flags}")
+	}
+	workers := 1
+	if name == "batch" {
+		cmd.flags.IntVar(&workers, "workers", 2, "number of concurrent searches")
+	}
+	if name == "watch" {
+		cmd.flags.DurationVar(&cmd.settle, "settle", addic7ed.DefaultWatchSettle, "how long new videos must stay unchanged before searching their subtitle")
+	}
+	warm := ""
+	if name == "watch" || name == "serve" {
+		cmd.flags.StringVar(&warm, "warm", "", "comma-separated Addic7ed names or ids of shows whose latest season is cached, to search their new episodes from the cache")
+		cmd.flags.DurationVar(&cmd.warmEvery, "warm-every", 24*time.Hour, "how often the seasons of -warm are fetched again, and how long searches are cached")
+	}
+	if name == "serve" {
+		cmd.flags.StringVar(&cmd.addr, "addr", "127.0.0.1:8080", "address to listen to, the webhook being served at /sonarr")
+		cmd.flags.StringVar(&cmd.user, "user", "", "username of the basic authentication of the webhook, its password being read from "+passwordEnv)
+		cmd.flags.StringVar(&cmd.sonarr, "sonarr", "", "URL of Sonarr, to download at start the subtitles of the episode files of its monitored series missing one, its API key being read from "+sonarrAPIKeyEnv)
+	}
+	if name == "availability" {
+		cmd.flags.StringVar(&cmd.format, "format", "markdown", "format of the matrix: markdown, csv or json, like -json")
+	}
+	if name == "clean" {
+		cmd.flags.StringVar(&cmd.archive, "archive", "", "directory to move the orphaned subtitles to, instead of removing them")
+		cmd.flags.BoolVar(&cmd.dryRun, "n", false, "only list the orphaned subtitles")
+	}
+	if err := cmd.flags.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if jellyfin {
+		if cmd.output != defaultTemplate {
+			fmt.Fprintln(stderr, "-jellyfin and -o can't be used together")
+			return 2
+		}
+		cmd.output = jellyfinTemplate
+	}
+	if err := checkTemplate(cmd.output); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+	cmd.workers = workers
+	for _, show := range strings.Split(warm, ",") {
+		if show = strings.TrimSpace(show); show != "" {
+			cmd.warm = append(cmd.warm, show)
+		}
+	}
+	clientOpts := []addic7ed.Option{addic7ed.WithLogLevel(addic7ed.LevelError)}
+	if len(cmd.warm) > 0 {
+		clientOpts = append(clientOpts, addic7ed.WithCache(cmd.warmEvery, 0))
+	}
+	cmd.client = addic7ed.New(append(clientOpts, opts...)...)
+
+	err := runCommand(ctx, cmd, cmd.flags.Args())
+	var usageErr usageError
+	switch {
+	case errors.As(err, &usageErr):
+		fmt.Fprintf(stderr, "%v\n\nUsage of %v:\n", err, name)
+		cmd.flags.PrintDefaults()
+		return 2
+	case err != nil:
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// command is a command being run, with its flags
+type command struct {
+	flags  *flag.FlagSet
+	client *addic7ed.Client
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	// mu serializes the reports of the commands reporting from several goroutines
+	mu sync.Mutex
+
+	lang    string
+	json    bool
+	output  string
+	flagged subtitleFlags
+	workers int
+	settle  time.Duration
+	archive string
+	dryRun  bool
+	format  string
+	addr    string
+	user    string
+	sonarr  string
+
+	warm      []string
+	warmEvery time.Duration
+}
+
+// usageError is an error in the arguments of a command
+type usageError string
+
+func (e usageError) Error() string {
+	return string(e)
+}
+
+// errFailures is returned when some files of a command failed, after their errors were reported
+var errFailures = errors.New("some subtitles could not be downloaded")
+
+// subtitle is a subtitle written as JSON
+type subtitle struct {
+	ID              string  `json:"id"`
+	Language        string  `json:"language"`
+	LanguageCode    string  `json:"languageCode"`
+	Version         string  `json:"version"`
+	Link            string  `json:"link"`
+	HearingImpaired bool    `json:"hearingImpaired"`
+	Completion      float64 `json:"completion"`
+	Downloads       int     `json:"downloads"`
+}
+
+func newSubtitle(s addic7ed.Subtitle) subtitle {
+	return subtitle{
+		ID:              s.ID(),
+		Language:        s.Language,
+		LanguageCode:    s.LanguageCode(),
+		Version:         s.Version,
+		Link:            s.Link,
+		HearingImpaired: s.HearingImpaired,
+		Completion:      s.Completion,
+		Downloads:       s.Downloads,
+	}
+}
+
+// search lists the subtitles of an episode in the language of the command
+func search(ctx context.Context, cmd *command, args []string) error {
+	if len(args) != 1 {
+		return usageError("search takes exactly one file or search")
+	}
+	show, err := cmd.client.SearchAllContext(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	subtitles := show.Subtitles.Filter(addic7ed.WithLanguage(cmd.lang))
+	if cmd.json {
+		result := struct {
+			Episode   string     `json:"episode"`
+			Subtitles []subtitle `json:"subtitles"`
+		}{Episode: show.Name, Subtitles: []subtitle{}}
+		for _, s := range subtitles {
+			result.Subtitles = append(result.Subtitles, newSubtitle(s))
+		}
+		return json.NewEncoder(cmd.stdout).Encode(result)
+	}
+	fmt.Fprintln(cmd.stdout, show.Name)
+	for _, s := range subtitles {
+		var flags []string
+		if s.HearingImpaired {
+			flags = append(flags, "hearing impaired")
+		}
+		if s.Completion < 100 {
+			flags = append(flags, fmt.Sprintf("%v%% completed", s.Completion))
+		}
+		fmt.Fprintf(cmd.stdout, "%v\t%v\t%v\t%v\n", s.Language, s.Version, strings.Join(flags, ", "), s.Link)
+	}
+	return nil
+}
+
+// download downloads the best subtitle of each file or search.
+// "-" reads the files from stdin, one per line, and streams the results as JSON, one object per line, for shell pipelines
+func download(ctx context.Context, cmd *command, args []string) error {
+	if len(args) == 0 {
+		return usageError("download takes at least one file or search")
+	}
+	failed := false
+	downloadFile := func(file string) {
+		name, best, err := cmd.client.SearchBestContext(ctx, file, cmd.lang)
+		if !cmd.save(ctx, file, name, best, err) {
+			failed = true
+		}
+	}
+	for _, arg := range args {
+		if arg != "-" {
+			downloadFile(arg)
+			continue
+		}
+		cmd.json = true
+		scanner := bufio.NewScanner(cmd.stdin)
+		for scanner.Scan() && ctx.Err() == nil {
+			if file := strings.TrimSpace(scanner.Text()); file != "" {
+				downloadFile(file)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return err
+		}
+	}
+	if failed {
+		return errFailures
+	}
+	return nil
+}
+
+// batch downloads the best subtitle of the videos of a directory missing one
+func batch(ctx context.Context, cmd *command, args []string) error {
+	if len(args) != 1 {
+		return usageError("batch takes exactly one directory")
+	}
+	videos, err := videosWithoutSubtitles(args[0])
+	if err != nil {
+		return err
+	}
+	if len(videos) == 0 {
+		return nil
+	}
+	results, _ := cmd.client.SearchBestBatchContext(ctx, videos, cmd.lang, cmd.workers)
+	failed := false
+	for _, video := range videos {
+		result := results[video]
+		if !cmd.save(ctx, video, result.Name, result.Subtitle, result.Err) {
+			failed = true
+		}
+	}
+	if failed {
+		return errFailures
+	}
+	return nil
+}
+
+// watch downloads the best subtitle of the new videos of directories, until the command is interrupted.
+// Each directory can have its own language, like "~/Shows/French=fr", the language of the command being the default one.
+// The shows of -warm are kept warm in the cache of the client while watching, see keepWarm
+func watch(ctx context.Context, cmd *command, args []string) error {
+	if len(args) == 0 {
+		return usageError("watch takes at least one directory")
+	}
+	w := cmd.client.NewWatcher()
+	w.Settle = cmd.settle
+	w.Save = cmd.write
+	w.OnEvent = func(event addic7ed.WatchEvent) {
+		cmd.report(event.Video, event.Name, event.Subtitle, event.Path, event.Err)
+	}
+	for _, arg := range args {
+		dir, lang := arg, cmd.lang
+		if i := strings.LastIndex(arg, "="); i >= 0 {
+			dir, lang = arg[:i], arg[i+1:]
+		}
+		if err := w.Add(dir, lang); err != nil {
+			return err
+		}
+	}
+	go cmd.keepWarm(ctx)
+	return w.Run(ctx)
+}
+
+// passwordEnv is the environment variable of the password of the webhook of the serve command, kept out of the flags to stay
+// out of the list of processes
+const passwordEnv = "ADDIC7ED_WEBHOOK_PASSWORD"
+
+// sonarrAPIKeyEnv is the environment variable of the API key of Sonarr for the serve command, kept out of the flags like passwordEnv
+const sonarrAPIKeyEnv = "ADDIC7ED_SONARR_API_KEY"
+
+// errOpenWebhook is returned when the webhook would let anyone write subtitles anywhere
+var errOpenWebhook = errors.New("the webhook needs a password in " + passwordEnv + " or at least one directory")
+
+// serve serves a webhook for Sonarr, downloading the best subtitle of the imported episodes until the command is interrupted.
+// When directories are given, only the episodes inside them are handled. The webhook refuses to start without a password
+// nor directory, as any client reaching it could then write files next to any path.
+// The shows of -warm are kept warm in the cache of the client while serving, see keepWarm.
+// With -sonarr, the episode files of the series monitored by Sonarr missing a subtitle are imported while the webhook starts.
+func serve(ctx context.Context, cmd *command, args []string) error {
+	webhook := cmd.client.NewSonarrWebhook(cmd.lang)
+	webhook.Username, webhook.Password = cmd.user, os.Getenv(passwordEnv)
+	if webhook.Password == "" && len(args) == 0 {
+		return errOpenWebhook
+	}
+	webhook.Dirs = args
+	webhook.Save = cmd.write
+	webhook.OnEvent = func(event addic7ed.WatchEvent) {
+		cmd.report(event.Video, event.Name, event.Subtitle, event.Path, event.Err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/sonarr", webhook)
+	server := &http.Server{Addr: cmd.addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		server.Shutdown(context.Background())
+	}()
+	fmt.Fprintf(cmd.stderr, "Serving the Sonarr webhook at http://%v/sonarr\n", cmd.addr)
+	go cmd.keepWarm(ctx)
+	if cmd.sonarr != "" {
+		go func() {
+			if _, err := webhook.Import(ctx, addic7ed.Sonarr{URL: cmd.sonarr, APIKey: os.Getenv(sonarrAPIKeyEnv)}); err != nil && ctx.Err() == nil {
+				cmd.mu.Lock()
+				fmt.Fprintf(cmd.stderr, "importing the episodes of Sonarr: %v\n", err)
+				cmd.mu.Unlock()
+			}
+		}()
+	}
+	if err := server.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// accuracy runs the search of the best subtitle on files whose expected version is known, read from a CSV file of "file,version"
+// records, and reports the accuracy. The wrong and failed cases are listed before the accuracy
+func accuracy(ctx context.Context, cmd *command, args []string) error {
+	if len(args) != 1 {
+		return usageError("accuracy takes exactly one CSV file of cases")
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	cases, err := addic7ed.ReadAccuracyCases(f)
+	if err != nil {
+		return fmt.Errorf("invalid cases %v: %w", args[0], err)
+	}
+	report, err := cmd.client.EvaluateAccuracyContext(ctx, cases, cmd.lang)
+	if err != nil {
+		return err
+	}
+	if cmd.json {
+		type result struct {
+			File     string    `json:"file"`
+			Expected string    `json:"expected"`
+			Subtitle *subtitle `json:"subtitle,omitempty"`
+			Correct  bool      `json:"correct"`
+			Error    string    `json:"error,omitempty"`
+		}
+		results := []result{}
+		for _, r := range report.Results {
+			result := result{File: r.File, Expected: r.Version, Correct: r.Correct}
+			if r.Err != nil {
+				result.Error = r.Err.Error()
+			} else {
+				s := newSubtitle(r.Best)
+				result.Subtitle = &s
+			}
+			results = append(results, result)
+		}
+		return json.NewEncoder(cmd.stdout).Encode(struct {
+			Accuracy float64  `json:"accuracy"`
+			Correct  int      `json:"correct"`
+			Wrong    int      `json:"wrong"`
+			Failed   int      `json:"failed"`
+			Results  []result `json:"results"`
+		}{report.Accuracy(), report.Correct, report.Wrong, report.Failed, results})
+	}
+	for _, r := range report.Results {
+		switch {
+		case r.Err != nil:
+			fmt.Fprintf(cmd.stdout, "failed\t%v\t%v\n", r.File, r.Err)
+		case !r.Correct:
+			fmt.Fprintf(cmd.stdout, "wrong\t%v\texpected %v, got %v\n", r.File, r.Version, r.Best.Version)
+		}
+	}
+	fmt.Fprintln(cmd.stdout, report)
+	return nil
+}
+
+// availability writes the availability matrix of the subtitles of a season in the format of the command
+func availability(ctx context.Context, cmd *command, args []string) error {
+	if len(args) < 2 {
+		return usageError("availability takes a show, a season and optionally languages")
+	}
+	season, err := strconv.Atoi(args[1])
+	if err != nil || season < 0 {
+		return usageError(fmt.Sprintf("invalid season %q", args[1]))
+	}
+	format := cmd.format
+	if cmd.json {
+		format = "json"
+	}
+	if format != "markdown" && format != "csv" && format != "json" {
+		return usageError(fmt.Sprintf("unknown format %q", format))
+	}
+	show, err := cmd.client.GetShowContext(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	matrix, err := cmd.client.GetAvailabilityContext(ctx, show, season, args[2:])
+	if err != nil {
+		return err
+	}
+	switch format {
+	case "csv":
+		return matrix.WriteCSV(cmd.stdout)
+	case "json":
+		return matrix.WriteJSON(cmd.stdout)
+	}
+	return matrix.WriteMarkdown(cmd.stdout)
+}
+
+// clean removes or archives the subtitles of a directory whose video no longer exists, and lists them
+func clean(ctx context.Context, cmd *command, args []string) error {
+	if len(args) != 1 {
+		return usageError("clean takes exactly one directory")
+	}
+	var orphans []string
+	var err error
+	if cmd.dryRun {
+		orphans, err = addic7ed.OrphanSubtitles(args[0])
+	} else {
+		orphans, err = addic7ed.CleanOrphanSubtitles(args[0], cmd.archive)
+	}
+	for _, orphan := range orphans {
+		if cmd.json {
+			json.NewEncoder(cmd.stdout).Encode(struct {
+				Path string `json:"path"`
+			}{orphan})
+		} else {
+			fmt.Fprintln(cmd.stdout, orphan)
+		}
+	}
+	return err
+}
+
+// save downloads the best subtitle found for a file, and reports the result. It returns false when the file failed
+func (cmd *command) save(ctx context.Context, file, episode string, best addic7ed.Subtitle, err error) bool {
+	var path string
+	if err == nil {
+		path, err = cmd.write(ctx, file, episode, best)
+	}
+	return cmd.report(file, episode, best, path, err)
+}
+
+// report reports the result of the download of the subtitle of a file. It returns false when the file failed
+func (cmd *command) report(file, episode string, best addic7ed.Subtitle, path string, err error) bool {
+	cmd.mu.Lock()
+	defer cmd.mu.Unlock()
+	if cmd.json {
+		result := struct {
+			File     string    `json:"file"`
+			Episode  string    `json:"episode,omitempty"`
+			Subtitle *subtitle `json:"subtitle,omitempty"`
+			Path     string    `json:"path,omitempty"`
+			Error    string    `json:"error,omitempty"`
+		}{File: file, Episode: episode, Path: path}
+		if err != nil {
+			result.Error = err.Error()
+		} else {
+			s := newSubtitle(best)
+			result.Subtitle = &s
+		}
+		json.NewEncoder(cmd.stdout).Encode(result)
+		return err == nil
+	}
+	if err != nil {
+		fmt.Fprintf(cmd.stderr, "%v: %v\n", file, err)
+		return false
+	}
+	fmt.Fprintln(cmd.stdout, path)
+	return true
+}
+
+// keepWarm caches the latest season of the shows of -warm right away, then every -warm-every, until the context is done.
+// Failures are reported and tried again at the next warming
+func (cmd *command) keepWarm(ctx context.Context) {
+	if len(cmd.warm) == 0 {
+		return
+	}
+	for {
+		if err := cmd.client.WarmSeasons(ctx, cmd.warm...); err != nil && ctx.Err() == nil {
+			cmd.mu.Lock()
+			fmt.Fprintf(cmd.stderr, "warming the cache: %v\n", err)
+			cmd.mu.Unlock()
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(cmd.warmEvery):
+		}
+	}
+}
+
+// write downloads a subtitle to the path given by the output template for a file
+func (cmd *command) write(ctx context.Context, file, episode string, best addic7ed.Subtitle) (string, error) {
+	result, err := best.FetchContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	path := renderTemplate(cmd.output, file, episode, best, result.Format, cmd.flagged)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	return path, os.WriteFile(path, result.Data, 0644)
+}
+
+// videosWithoutSubtitles finds the videos of a directory and its subdirectories without any subtitle next to them.
+// A video "Show.S01E01.mkv" has a subtitle when a file like "Show.S01E01.srt" or "Show.S01E01.en.srt" exists
+func videosWithoutSubtitles(dir string) ([]string, error) {
+	filesByDir := map[string][]string{}
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() {
+			filesByDir[filepath.Dir(path)] = append(filesByDir[filepath.Dir(path)], entry.Name())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	var videos []string
+	for dir, files := range filesByDir {
+		for _, file := range files {
+			if !addic7ed.IsVideo(file) {
+				continue
+			}
+			base := strings.TrimSuffix(file, filepath.Ext(file))
+			hasSubtitle := slices.ContainsFunc(files, func(other string) bool {
+				return strings.HasPrefix(other, base+".") && addic7ed.IsSubtitle(other)
+			})
+			if !hasSubtitle {
+				videos = append(videos, filepath.Join(dir, file))
+			}
+		}
+	}
+	slices.Sort(videos)
+	return videos, nil
+}
@@ -0,0 +1,403 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+const srt = "1\n00:00:01,000 --> 00:00:02,000\nHello\n"
+
+// newServer serves the page of an episode for all searches, and its subtitles
+func newServer(t *testing.T) *httptest.Server {
+	page, err := os.ReadFile("../../testdata/episode.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	show, err := os.ReadFile("../../testdata/show.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	season, err := os.ReadFile("../../testdata/season.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/show/5427" && r.URL.Query().Get("season") == "8":
+			w.Write(season)
+		case r.URL.Path == "/show/5427" && r.URL.Query().Get("season") == "":
+			w.Write(show)
+		case r.URL.Path == "/srch.php" && strings.Contains(r.URL.Query().Get("search"), "Unknown"):
+			w.Write([]byte("<html><body>Nothing found</body></html>"))
+		case r.URL.Path == "/srch.php":
+			w.Write(page)
+		case strings.HasPrefix(r.URL.Path, "/original/"), strings.HasPrefix(r.URL.Path, "/updated/"):
+			w.Write([]byte(srt))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func runWith(t *testing.T, server *httptest.Server, args ...string) (int, string, string) {
+	return runWithStdin(t, server, "", args...)
+}
+
+// runWithStdin runs a command reading stdin from a string
+func runWithStdin(t *testing.T, server *httptest.Server, stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	status := run(context.Background(), args, strings.NewReader(stdin), &stdout, &stderr, addic7ed.WithBaseURL(server.URL), addic7ed.WithoutRetry())
+	return status, stdout.String(), stderr.String()
+}
+
+func TestSearch(t *testing.T) {
+	server := newServer(t)
+	status, stdout, _ := runWith(t, server, "search", "-lang", "fr", "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.Equal(t, 0, status)
+	assert.True(t, strings.HasPrefix(stdout, "Shameless (US) - 08x11 - A Gallagher Pedicure\nFrench\t"), stdout)
+
+	status, stdout, _ = runWith(t, server, "search", "-json", "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.Equal(t, 0, status)
+	var result struct {
+		Episode   string
+		Subtitles []subtitle
+	}
+	assert.NoError(t, json.Unmarshal([]byte(stdout), &result))
+	assert.Equal(t, "Shameless (US) - 08x11 - A Gallagher Pedicure", result.Episode)
+	assert.NotEmpty(t, result.Subtitles)
+	assert.Equal(t, "en", result.Subtitles[0].LanguageCode)
+}
+
+func TestDownload(t *testing.T) {
+	server := newServer(t)
+	dir := t.TempDir()
+	video := filepath.Join(dir, "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv].mkv")
+	status, stdout, _ := runWith(t, server, "download", video)
+	assert.Equal(t, 0, status)
+	expected := filepath.Join(dir, "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv].en.srt")
+	assert.Equal(t, expected+"\n", stdout)
+	content, err := os.ReadFile(expected)
+	assert.NoError(t, err)
+	assert.Equal(t, srt, string(content))
+
+	// Files failing are reported, the others are still downloaded
+	status, stdout, _ = runWith(t, server, "download", "-json", "-lang", "French", "-o", dir+"/{episode}/{version}{ext}", "Unknown.Show.S01E01", video)
+	assert.Equal(t, 1, status)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	assert.Len(t, lines, 2)
+	assert.Contains(t, lines[0], `"error":`)
+	assert.Contains(t, lines[1], `"languageCode":"fr"`)
+	_, err = os.Stat(filepath.Join(dir, "Shameless (US) - 08x11 - A Gallagher Pedicure", "BATV.srt"))
+	assert.NoError(t, err)
+}
+
+func TestDownloadForJellyfin(t *testing.T) {
+	server := newServer(t)
+	dir := t.TempDir()
+	video := filepath.Join(dir, "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv].mkv")
+	status, stdout, _ := runWith(t, server, "download", "-jellyfin", "-default", "-forced", video)
+	assert.Equal(t, 0, status)
+	assert.Equal(t, filepath.Join(dir, "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv].en.default.forced.srt")+"\n", stdout)
+
+	status, _, _ = runWith(t, server, "download", "-jellyfin", "-o", "{name}{ext}", video)
+	assert.Equal(t, 2, status)
+
+	// Hearing impaired subtitles are flagged as SDH
+	hearingImpaired := addic7ed.Subtitle{Language: "English", HearingImpaired: true}
+	assert.Equal(t, filepath.Join(dir, "Show.S01E01.en.sdh.srt"),
+		renderTemplate(jellyfinTemplate, filepath.Join(dir, "Show.S01E01.mkv"), "", hearingImpaired, addic7ed.FormatSRT, subtitleFlags{}))
+}
+
+func TestDownloadFromStdin(t *testing.T) {
+	server := newServer(t)
+	dir := t.TempDir()
+	video := filepath.Join(dir, "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv].mkv")
+	status, stdout, _ := runWithStdin(t, server, video+"\n\nUnknown.Show.S01E01\n", "download", "-lang", "French", "-")
+	assert.Equal(t, 1, status)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	if assert.Len(t, lines, 2) {
+		var result struct {
+			File  string `json:"file"`
+			Path  string `json:"path"`
+			Error string `json:"error"`
+		}
+		assert.NoError(t, json.Unmarshal([]byte(lines[0]), &result))
+		assert.Equal(t, video, result.File)
+		assert.Equal(t, filepath.Join(dir, "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv].fr.srt"), result.Path)
+		assert.Contains(t, lines[1], `"error":`)
+	}
+}
+
+func TestBatch(t *testing.T) {
+	server := newServer(t)
+	dir := t.TempDir()
+	season := filepath.Join(dir, "Season 8")
+	assert.NoError(t, os.MkdirAll(season, 0755))
+	for _, file := range []string{
+		"Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv].mkv",
+		"Shameless.US.S08E12.720p.HDTV.x264-AVS[ettv].mkv",
+		"Shameless.US.S08E12.720p.HDTV.x264-AVS[ettv].fr.srt",
+		"notes.txt",
+	} {
+		assert.NoError(t, os.WriteFile(filepath.Join(season, file), nil, 0644))
+	}
+
+	status, stdout, stderr := runWith(t, server, "batch", dir)
+	assert.Equal(t, 0, status, stderr)
+	assert.Equal(t, filepath.Join(season, "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv].en.srt")+"\n", stdout)
+
+	// Once downloaded, videos are skipped
+	status, stdout, _ = runWith(t, server, "batch", dir)
+	assert.Equal(t, 0, status)
+	assert.Empty(t, stdout)
+}
+
+func TestWatch(t *testing.T) {
+	server := newServer(t)
+	dir := t.TempDir()
+	french := filepath.Join(dir, "French")
+	assert.NoError(t, os.Mkdir(french, 0755))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan int)
+	go func() {
+		done <- run(ctx, []string{"watch", "-settle", "50ms", dir, french + "=fr"}, nil, io.Discard, io.Discard, addic7ed.WithBaseURL(server.URL), addic7ed.WithoutRetry())
+	}()
+	// Give the watcher the time to watch the directories
+	time.Sleep(50 * time.Millisecond)
+	assert.NoError(t, os.WriteFile(filepath.Join(french, "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv].mkv"), nil, 0644))
+
+	expected := filepath.Join(french, "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv].fr.srt")
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(10 * time.Millisecond) {
+		if _, err := os.Stat(expected); err == nil {
+			break
+		}
+	}
+	cancel()
+	assert.Equal(t, 0, <-done)
+	content, err := os.ReadFile(expected)
+	assert.NoError(t, err)
+	assert.Equal(t, srt, string(content))
+}
+
+// countingTransport counts the searches and the season listings requested
+type countingTransport struct {
+	searches, seasons *int64
+}
+
+func (t countingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	switch {
+	case r.URL.Path == "/srch.php":
+		atomic.AddInt64(t.searches, 1)
+	case r.URL.Query().Get("season") != "":
+		atomic.AddInt64(t.seasons, 1)
+	}
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+func TestWatchWarmsSeasons(t *testing.T) {
+	server := newServer(t)
+	dir := t.TempDir()
+	var searches, seasons int64
+	client := &http.Client{Transport: countingTransport{searches: &searches, seasons: &seasons}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan int)
+	go func() {
+		done <- run(ctx, []string{"watch", "-settle", "50ms", "-warm", "5427, ", dir}, nil, io.Discard, io.Discard,
+			addic7ed.WithBaseURL(server.URL), addic7ed.WithoutRetry(), addic7ed.WithHTTPClient(client))
+	}()
+	for start := time.Now(); time.Since(start) < 5*time.Second && atomic.LoadInt64(&seasons) == 0; time.Sleep(10 * time.Millisecond) {
+	}
+	// Give the warming the time to cache the season
+	time.Sleep(50 * time.Millisecond)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv].mkv"), nil, 0644))
+
+	expected := filepath.Join(dir, "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv].en.srt")
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(10 * time.Millisecond) {
+		if _, err := os.Stat(expected); err == nil {
+			break
+		}
+	}
+	cancel()
+	assert.Equal(t, 0, <-done)
+	assert.FileExists(t, expected)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&seasons))
+	assert.Equal(t, int64(0), atomic.LoadInt64(&searches))
+}
+
+func TestServe(t *testing.T) {
+	server := newServer(t)
+	dir := t.TempDir()
+	video := filepath.Join(dir, "Shameless (US) - S08E11 - A Gallagher Pedicure.mkv")
+	assert.NoError(t, os.WriteFile(video, nil, 0644))
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan int)
+	go func() {
+		done <- run(ctx, []string{"serve", "-addr", addr, "-lang", "fr", dir}, nil, io.Discard, io.Discard, addic7ed.WithBaseURL(server.URL), addic7ed.WithoutRetry())
+	}()
+	payload := fmt.Sprintf(`{"eventType": "Download", "episodeFile": {"path": %q, "sceneName": "Shameless.US.S08E11.720p.HDTV.x264-BATV"}}`, video)
+	var response *http.Response
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(10 * time.Millisecond) {
+		if response, err = http.Post("http://"+addr+"/sonarr", "application/json", strings.NewReader(payload)); err == nil {
+			break
+		}
+	}
+	if assert.NoError(t, err) {
+		response.Body.Close()
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+	}
+	cancel()
+	assert.Equal(t, 0, <-done)
+	content, err := os.ReadFile(filepath.Join(dir, "Shameless (US) - S08E11 - A Gallagher Pedicure.fr.srt"))
+	assert.NoError(t, err)
+	assert.Equal(t, srt, string(content))
+}
+
+func TestServeImportsSonarr(t *testing.T) {
+	server := newServer(t)
+	dir := t.TempDir()
+	video := filepath.Join(dir, "Shameless (US) - S08E11 - A Gallagher Pedicure.mkv")
+	assert.NoError(t, os.WriteFile(video, nil, 0644))
+	sonarr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Header.Get("X-Api-Key") != "key":
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		case r.URL.Path == "/api/v3/series":
+			w.Write([]byte(`[{"id": 1, "title": "Shameless (US)", "monitored": true}]`))
+		case r.URL.Path == "/api/v3/episodefile":
+			fmt.Fprintf(w, `[{"path": %q, "sceneName": "Shameless.US.S08E11.720p.HDTV.x264-BATV"}]`, video)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer sonarr.Close()
+	t.Setenv(sonarrAPIKeyEnv, "key")
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	addr := listener.Addr().String()
+	listener.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan int)
+	go func() {
+		done <- run(ctx, []string{"serve", "-addr", addr, "-sonarr", sonarr.URL, dir}, nil, io.Discard, io.Discard, addic7ed.WithBaseURL(server.URL), addic7ed.WithoutRetry())
+	}()
+	expected := filepath.Join(dir, "Shameless (US) - S08E11 - A Gallagher Pedicure.en.srt")
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(10 * time.Millisecond) {
+		if _, err := os.Stat(expected); err == nil {
+			break
+		}
+	}
+	cancel()
+	assert.Equal(t, 0, <-done)
+	content, err := os.ReadFile(expected)
+	assert.NoError(t, err)
+	assert.Equal(t, srt, string(content))
+}
+
+func TestAccuracy(t *testing.T) {
+	server := newServer(t)
+	cases := filepath.Join(t.TempDir(), "cases.csv")
+	assert.NoError(t, os.WriteFile(cases, []byte("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv].mkv,BATV\nShameless.US.S08E11.WEB.x264-TBS.mkv,BATV\n"), 0644))
+
+	status, stdout, _ := runWith(t, server, "accuracy", cases)
+	assert.Equal(t, 0, status)
+	assert.Equal(t, "wrong\tShameless.US.S08E11.WEB.x264-TBS.mkv\texpected BATV, got WEB.x264-TBS\naccuracy 50.0% (1/2), 0 failed\n", stdout)
+
+	status, stdout, _ = runWith(t, server, "accuracy", "-json", cases)
+	assert.Equal(t, 0, status)
+	assert.Contains(t, stdout, `"accuracy":0.5`)
+}
+
+func TestAvailability(t *testing.T) {
+	server := newServer(t)
+	status, stdout, _ := runWith(t, server, "availability", "-format", "csv", "Shameless (US)", "8", "en", "fr")
+	assert.Equal(t, 0, status)
+	assert.Equal(t, "Episode,Title,English,French\nS08E11,A Gallagher Pedicure,available,missing\nS08E12,Church of Gay Jesus,available,available\n", stdout)
+
+	status, stdout, _ = runWith(t, server, "availability", "Shameless (US)", "8")
+	assert.Equal(t, 0, status)
+	assert.Contains(t, stdout, "| S08E12 | Church of Gay Jesus | ✓ | ✓ |\n")
+
+	status, stdout, _ = runWith(t, server, "availability", "-json", "Shameless (US)", "8")
+	assert.Equal(t, 0, status)
+	assert.Contains(t, stdout, `"show": "Shameless (US)"`)
+
+	status, _, _ = runWith(t, server, "availability", "-format", "xml", "Shameless (US)", "8")
+	assert.Equal(t, 2, status)
+	status, _, _ = runWith(t, server, "availability", "Shameless (US)")
+	assert.Equal(t, 2, status)
+}
+
+func TestClean(t *testing.T) {
+	server := newServer(t)
+	dir := t.TempDir()
+	for _, file := range []string{"Show.S01E01.mkv", "Show.S01E01.en.srt", "Show.S01E02.en.srt"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, file), nil, 0644))
+	}
+	orphan := filepath.Join(dir, "Show.S01E02.en.srt")
+
+	status, stdout, _ := runWith(t, server, "clean", "-n", dir)
+	assert.Equal(t, 0, status)
+	assert.Equal(t, orphan+"\n", stdout)
+	_, err := os.Stat(orphan)
+	assert.NoError(t, err)
+
+	status, stdout, _ = runWith(t, server, "clean", dir)
+	assert.Equal(t, 0, status)
+	assert.Equal(t, orphan+"\n", stdout)
+	_, err = os.Stat(orphan)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestUsage(t *testing.T) {
+	server := newServer(t)
+	for _, args := range [][]string{
+		{},
+		{"unknown"},
+		{"search"},
+		{"download", "-o", "{dir}/{show}.srt", "file.mkv"},
+		{"batch", "-unknown", "dir"},
+		{"watch"},
+		{"clean"},
+		{"accuracy"},
+	} {
+		status, _, stderr := runWith(t, server, args...)
+		assert.Equal(t, 2, status, "%v", args)
+		assert.NotEmpty(t, stderr, "%v", args)
+	}
+	status, stdout, _ := runWith(t, server, "help")
+	assert.Equal(t, 0, status)
+	assert.Contains(t, stdout, "addic7ed batch")
+}
+
+func TestServeNeedsPasswordOrDirectory(t *testing.T) {
+	t.Setenv(passwordEnv, "")
+	status, _, stderr := runWith(t, newServer(t), "serve")
+	assert.Equal(t, 1, status)
+	assert.Contains(t, stderr, passwordEnv)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/matcornic/addic7ed"
+)
+
+// defaultTemplate saves subtitles next to their video, with the name expected by most players and media servers
+const defaultTemplate = "{dir}/{name}.{lang}{ext}"
+
+// jellyfinTemplate saves subtitles next to their video with the language and the flags read by Jellyfin, like
+// "Show.S01E01.en.default.sdh.srt", see -jellyfin
+const jellyfinTemplate = "{dir}/{name}.{lang}{flags}{ext}"
+
+// placeholders are the placeholders of the output templates
+var placeholders = []string{"{dir}", "{name}", "{lang}", "{language}", "{version}", "{episode}", "{flags}", "{ext}"}
+
+var placeholderRegexp = regexp.MustCompile(`\{[^{}]*\}`)
+
+// checkTemplate checks that an output template only has known placeholders
+func checkTemplate(template string) error {
+	for _, placeholder := range placeholderRegexp.FindAllString(template, -1) {
+		if !slices.Contains(placeholders, placeholder) {
+			return fmt.Errorf("unknown placeholder %v in output template %q, available placeholders are %v", placeholder, template, strings.Join(placeholders, ", "))
+		}
+	}
+	return nil
+}
+
+// renderTemplate renders the path of the subtitle of a file from an output template:
+//   - {dir} is the directory of the file, "." for searches
+//   - {name} is the name of the file, without its video extension
+//   - {lang} and {language} are the ISO 639-1 code and the Addic7ed name of the language of the subtitle
+//   - {version} is the version of the subtitle
+//   - {episode} is the name of the episode on Addic7ed
+//   - {flags} are the flags of the subtitle in the names of Jellyfin, each after a dot: "default" and "forced" when asked
+//     with -default and -forced, and "sdh" for hearing impaired subtitles, like ".default.sdh"
+//   - {ext} is the extension of the detected format of the subtitle, like ".srt"
+func renderTemplate(template, file, episode string, s addic7ed.Subtitle, format addic7ed.Format, flags subtitleFlags) string {
+	name := filepath.Base(file)
+	if addic7ed.IsVideo(name) {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	ext := format.Extension()
+	if ext == "" {
+		ext = ".srt"
+	}
+	replacer := strings.NewReplacer(
+		"{dir}", filepath.Dir(file),
+		"{name}", name,
+		"{lang}", s.LanguageCode(),
+		"{language}", s.Language,
+		"{version}", sanitize(s.Version),
+		"{episode}", sanitize(episode),
+		"{flags}", flags.suffix(s),
+		"{ext}", ext,
+	)
+	return filepath.Clean(replacer.Replace(template))
+}
+
+// subtitleFlags are the flags of the tracks of the downloaded subtitles for media servers, see {flags}
+type subtitleFlags struct {
+	isDefault bool
+	forced    bool
+}
+
+// suffix returns the flags of a subtitle in the order of the names of Jellyfin, each after a dot
+func (f subtitleFlags) suffix(s addic7ed.Subtitle) string {
+	var suffix string
+	if f.isDefault {
+		suffix += ".default"
+	}
+	if f.forced {
+		suffix += ".forced"
+	}
+	if s.HearingImpaired {
+		suffix += ".sdh"
+	}
+	return suffix
+}
+
+// sanitize replaces the characters that are not allowed in file names by most file systems
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, s)
+}
@@ -0,0 +1,158 @@
+// Command refresh-fixtures re-downloads the pages of the test fixtures from the live Addic7ed website, so that the
+// parsing of the package can be checked against the current markup of the website.
+//
+// Usage, from the root of the repository:
+//
+//	go run ./cmd/refresh-fixtures [flags] [fixture...]
+//
+// Pages are sanitized before being written: scripts, frames, comments and ads are removed, and absolute links to the
+// website are made relative, like the links of the fixtures. Review the changes with "git diff testdata" and run the
+// tests: tests relying on the content of the fixtures, like the number of subtitles, may need to be updated along.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/matcornic/addic7ed"
+)
+
+// fixture is a page of the website saved in the test fixtures
+type fixture struct {
+	// name is the name of the file of the fixture in the fixtures directory
+	name string
+	// path is the path of the page on the website, as requested by the client
+	path string
+}
+
+// fixtures are the fixtures of the tests, all about the 11th episode of the 8th season of Shameless (US),
+// and the results of the search of Shameless, matching Shameless (US) and Shameless (UK)
+var fixtures = []fixture{
+	{name: "episode.html", path: "serie/Shameless_(US)/8/11/0"},
+	{name: "show.html", path: "show/5427"},
+	{name: "season.html", path: "show/5427?season=8"},
+	{name: "rss.xml", path: "rss.php?mode=versions"},
+	{name: "search.html", path: "srch.php?search=Shameless&Submit=Search"},
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run refreshes the fixtures, returning the exit status: 0 on success, 1 on failures, 2 on usage errors
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("refresh-fixtures", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	dir := flags.String("dir", "testdata", "directory of the fixtures")
+	baseURL := flags.String("base", addic7ed.DefaultBaseURL, "base URL of the website")
+	delay := flags.Duration("delay", 2*time.Second, "delay between two downloads, to be gentle with the website")
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: refresh-fixtures [flags] [fixture...]\n\nFixtures are all refreshed by default, among:\n")
+		for _, f := range fixtures {
+			fmt.Fprintf(stderr, "  %v\t%v\n", f.name, f.path)
+		}
+		fmt.Fprintf(stderr, "\nFlags:\n")
+		flags.PrintDefaults()
+	}
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	selected := fixtures
+	if flags.NArg() > 0 {
+		selected = nil
+		for _, name := range flags.Args() {
+			i := slices.IndexFunc(fixtures, func(f fixture) bool { return f.name == name })
+			if i < 0 {
+				fmt.Fprintf(stderr, "unknown fixture %q\n", name)
+				flags.Usage()
+				return 2
+			}
+			selected = append(selected, fixtures[i])
+		}
+	}
+
+	failed := false
+	for i, f := range selected {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				fmt.Fprintln(stderr, ctx.Err())
+				return 1
+			case <-time.After(*delay):
+			}
+		}
+		page, err := download(ctx, strings.TrimSuffix(*baseURL, "/")+"/"+f.path)
+		if err == nil {
+			err = os.WriteFile(filepath.Join(*dir, f.name), sanitize(page), 0644)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "%v: %v\n", f.name, err)
+			failed = true
+			continue
+		}
+		fmt.Fprintln(stdout, filepath.Join(*dir, f.name))
+	}
+	if failed {
+		return 1
+	}
+	return 0
+}
+
+// download downloads a page of the website
+func download(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	// The User-Agent of the client, so that the website serves the same markup
+	req.Header.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64; rv:12.0) Gecko/20100101 Firefox/12.0")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %v for %v", resp.Status, url)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+var (
+	// removedRegexps match the parts of pages useless to the tests, or changing at each download
+	removedRegexps = []*regexp.Regexp{
+		regexp.MustCompile(`(?is)<script\b.*?</script>`),
+		regexp.MustCompile(`(?is)<noscript\b.*?</noscript>`),
+		regexp.MustCompile(`(?is)<iframe\b.*?</iframe>`),
+		regexp.MustCompile(`(?is)<ins\b.*?</ins>`),
+		regexp.MustCompile(`(?s)<!--.*?-->`),
+	}
+	// absoluteLinkRegexp matches the absolute links to the website
+	absoluteLinkRegexp = regexp.MustCompile(`(?i)(href|src|action)="(?:https?:)?//(?:www\.)?addic7ed\.com/`)
+	// blankLinesRegexp matches the blank lines left by removed parts
+	blankLinesRegexp = regexp.MustCompile(`\n(?:[ \t]*\n)+`)
+)
+
+// sanitize removes the scripts, frames, comments and ads of a page, and makes its links to the website relative
+func sanitize(page []byte) []byte {
+	for _, re := range removedRegexps {
+		page = re.ReplaceAll(page, nil)
+	}
+	page = absoluteLinkRegexp.ReplaceAll(page, []byte(`$1="/`))
+	return blankLinesRegexp.ReplaceAll(page, []byte("\n"))
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitize(t *testing.T) {
+	page := `<html>
+<head><script type="text/javascript">var ads = 1;</script></head>
+<body>
+  <!-- ad -->
+  <iframe src="https://ads.example.com"></iframe>
+
+  <a href="https://www.addic7ed.com/show/5427">Shameless (US)</a>
+  <a href="//addic7ed.com/original/131967/0">Download</a>
+  <a href="https://example.com/">Other</a>
+</body>
+</html>`
+	assert.Equal(t, `<html>
+<head></head>
+<body>
+  <a href="/show/5427">Shameless (US)</a>
+  <a href="/original/131967/0">Download</a>
+  <a href="https://example.com/">Other</a>
+</body>
+</html>`, string(sanitize([]byte(page))))
+}
+
+func TestRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/show/5427" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("<html><script>x</script><body>season " + r.URL.Query().Get("season") + "</body></html>"))
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+
+	var stdout, stderr bytes.Buffer
+	status := run(context.Background(), []string{"-dir", dir, "-base", server.URL, "-delay", "0", "show.html", "season.html"}, &stdout, &stderr)
+	assert.Equal(t, 0, status, stderr.String())
+	season, err := os.ReadFile(filepath.Join(dir, "season.html"))
+	assert.NoError(t, err)
+	assert.Equal(t, "<html><body>season 8</body></html>", string(season))
+	_, err = os.Stat(filepath.Join(dir, "episode.html"))
+	assert.True(t, os.IsNotExist(err))
+
+	status = run(context.Background(), []string{"-dir", dir, "-base", server.URL, "-delay", "0", "episode.html"}, &stdout, &stderr)
+	assert.Equal(t, 1, status)
+	assert.Equal(t, 2, run(context.Background(), []string{"unknown.html"}, &stdout, &stderr))
+}
@@ -0,0 +1,180 @@
+package addic7ed
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// srtTagRegexp matches the HTML-like tags of SRT files, like <i> or <font color="#ffff00">
+	srtTagRegexp = regexp.MustCompile(`</?([a-zA-Z]+)[^>]*>`)
+	// srtOverrideRegexp matches the ASS override tags sometimes found in SRT files, like {\an8}
+	srtOverrideRegexp = regexp.MustCompile(`\{\\[^}]*\}`)
+)
+
+// assHeader is the header of the ASS files converted from SRT, with a default style close to the rendering of SRT by most players
+const assHeader = `[Script Info]
+ScriptType: v4.00+
+PlayResX: 384
+PlayResY: 288
+WrapStyle: 0
+ScaledBorderAndShadow: yes
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
+Style: Default,Arial,16,&H00FFFFFF,&H000000FF,&H00000000,&H80000000,0,0,0,0,100,100,0,0,1,1,0,2,10,10,10,1
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+`
+
+// ConvertSRT converts a SRT subtitle read from r to the given format, written to w.
+// Supported formats are FormatSRT, FormatVTT and FormatASS. Italic, bold and underline tags are kept, other tags are removed.
+func ConvertSRT(w io.Writer, r io.Reader, format Format) error {
+	cues, err := parseSRT(r)
+	if err != nil {
+		return newError(CodeParseFailure, err, "Unable to read the subtitle")
+	}
+	switch format {
+	case FormatSRT:
+		return writeSRT(w, cues)
+	case FormatVTT:
+		return writeVTT(w, cues)
+	case FormatASS:
+		return writeASS(w, cues)
+	default:
+		return fmt.Errorf("conversion of SRT subtitles to %q is not supported", format)
+	}
+}
+
+// writeVTT writes cues as a WebVTT file
+func writeVTT(w io.Writer, cues []cue) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprint(bw, "WEBVTT\n\n")
+	for _, c := range cues {
+		fmt.Fprintf(bw, "%s --> %s\n", formatVTTTimestamp(c.start), formatVTTTimestamp(c.end))
+		for _, line := range c.lines {
+			fmt.Fprintln(bw, vttText(line))
+		}
+		fmt.Fprintln(bw)
+	}
+	return bw.Flush()
+}
+
+// formatVTTTimestamp formats a duration as a WebVTT timestamp, like "01:02:03.456"
+func formatVTTTimestamp(d time.Duration) string {
+	return strings.Replace(formatSRTTimestamp(d), ",", ".", 1)
+}
+
+// vttText converts the text of a SRT cue to WebVTT, keeping the tags supported by WebVTT
+func vttText(line string) string {
+	line = srtOverrideRegexp.ReplaceAllString(line, "")
+	return srtTagRegexp.ReplaceAllStringFunc(line, func(tag string) string {
+		switch strings.ToLower(srtTagRegexp.FindStringSubmatch(tag)[1]) {
+		case "i", "b", "u":
+			return strings.ToLower(tag)
+		default:
+			return ""
+		}
+	})
+}
+
+// writeASS writes cues as an ASS file, with the default style
+func writeASS(w io.Writer, cues []cue) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprint(bw, assHeader)
+	for _, c := range cues {
+		text := make([]string, 0, len(c.lines))
+		for _, line := range c.lines {
+			text = append(text, assText(line))
+		}
+		fmt.Fprintf(bw, "Dialogue: 0,%s,%s,Default,,0,0,0,,%s\n", formatASSTimestamp(c.start), formatASSTimestamp(c.end), strings.Join(text, `\N`))
+	}
+	return bw.Flush()
+}
+
+// formatASSTimestamp formats a duration as an ASS timestamp, like "1:02:03.45"
+func formatASSTimestamp(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	h := d / time.Hour
+	m := (d % time.Hour) / time.Minute
+	s := (d % time.Minute) / time.Second
+	cs := (d % time.Second) / (10 * time.Millisecond)
+	return fmt.Sprintf("%d:%02d:%02d.%02d", h, m, s, cs)
+}
+
+// assText converts the text of a SRT cue to ASS, converting italic, bold and underline tags to override tags
+func assText(line string) string {
+	return srtTagRegexp.ReplaceAllStringFunc(line, func(tag string) string {
+		name := strings.ToLower(srtTagRegexp.FindStringSubmatch(tag)[1])
+		switch name {
+		case "i", "b", "u":
+			if strings.HasPrefix(tag, "</") {
+				return `{\` + name + `0}`
+			}
+			return `{\` + name + `1}`
+		default:
+			return ""
+		}
+	})
+}
+
+// DownloadAsVTT downloads the subtitle converted to WebVTT to a given path, see DownloadConverted
+func (s Subtitle) DownloadAsVTT(path string) error {
+	return s.DownloadAsVTTContext(context.Background(), path)
+}
+
+// DownloadAsVTTContext is like DownloadAsVTT, with a context to cancel the download
+func (s Subtitle) DownloadAsVTTContext(ctx context.Context, path string) error {
+	return s.downloadConvertedTo(ctx, path, FormatVTT)
+}
+
+// DownloadAsASS downloads the subtitle converted to ASS to a given path, see DownloadConverted
+func (s Subtitle) DownloadAsASS(path string) error {
+	return s.DownloadAsASSContext(context.Background(), path)
+}
+
+// DownloadAsASSContext is like DownloadAsASS, with a context to cancel the download
+func (s Subtitle) DownloadAsASSContext(ctx context.Context, path string) error {
+	return s.downloadConvertedTo(ctx, path, FormatASS)
+}
+
+func (s Subtitle) downloadConvertedTo(ctx context.Context, path string, format Format) error {
+	var converted bytes.Buffer
+	if err := s.DownloadConvertedContext(ctx, &converted, format); err != nil {
+		return err
+	}
+	return os.WriteFile(path, converted.Bytes(), 0644)
+}
+
+// DownloadConverted downloads the subtitle converted to a format to a writer, like the response of a web player.
+// Subtitles already in the format are written as is. Other subtitles must be SRT subtitles, see ConvertSRT.
+func (s Subtitle) DownloadConverted(w io.Writer, format Format) error {
+	return s.DownloadConvertedContext(context.Background(), w, format)
+}
+
+// DownloadConvertedContext is like DownloadConverted, with a context to cancel the download
+func (s Subtitle) DownloadConvertedContext(ctx context.Context, w io.Writer, format Format) error {
+	data, err := s.download(ctx)
+	if err != nil {
+		return err
+	}
+	switch detected := DetectFormat(ToUTF8(data)); detected {
+	case format:
+		_, err = w.Write(data)
+		return err
+	case FormatSRT:
+		return ConvertSRT(w, bytes.NewReader(ToUTF8(data)), format)
+	default:
+		return newError(CodeUnacceptableContent, nil, "subtitle of format %q can't be converted to %q", detected, format)
+	}
+}
@@ -0,0 +1,92 @@
+package addic7ed_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+const convertedSRT = "1\r\n00:00:01,000 --> 00:00:02,500\r\n{\\an8}<i>Hello</i>\r\n<font color=\"#ffff00\">World</font>\r\n\r\n2\r\n01:00:03,000 --> 01:00:04,000\r\n<b>Bye</b>\r\n"
+
+func TestConvertSRTToVTT(t *testing.T) {
+	var out bytes.Buffer
+	assert.NoError(t, addic7ed.ConvertSRT(&out, strings.NewReader(convertedSRT), addic7ed.FormatVTT))
+	expected := "WEBVTT\n\n" +
+		"00:00:01.000 --> 00:00:02.500\n<i>Hello</i>\nWorld\n\n" +
+		"01:00:03.000 --> 01:00:04.000\n<b>Bye</b>\n\n"
+	assert.Equal(t, expected, out.String())
+	assert.Equal(t, addic7ed.FormatVTT, addic7ed.DetectFormat(out.Bytes()))
+}
+
+func TestConvertSRTToASS(t *testing.T) {
+	var out bytes.Buffer
+	assert.NoError(t, addic7ed.ConvertSRT(&out, strings.NewReader(convertedSRT), addic7ed.FormatASS))
+	assert.Equal(t, addic7ed.FormatASS, addic7ed.DetectFormat(out.Bytes()))
+	assert.Contains(t, out.String(), "Dialogue: 0,0:00:01.00,0:00:02.50,Default,,0,0,0,,{\\an8}{\\i1}Hello{\\i0}\\NWorld\n")
+	assert.Contains(t, out.String(), "Dialogue: 0,1:00:03.00,1:00:04.00,Default,,0,0,0,,{\\b1}Bye{\\b0}\n")
+}
+
+func TestConvertSRTToUnsupportedFormat(t *testing.T) {
+	assert.Error(t, addic7ed.ConvertSRT(io.Discard, strings.NewReader(convertedSRT), addic7ed.FormatSUB))
+}
+
+func TestDownloadAsVTT(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(convertedSRT))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "sub.vtt")
+	assert.NoError(t, addic7ed.Subtitle{Link: server.URL}.DownloadAsVTT(path))
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(content), "WEBVTT\n\n00:00:01.000 --> 00:00:02.500\n"))
+}
+
+func TestDownloadAsASSContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(convertedSRT))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "sub.ass")
+	assert.NoError(t, addic7ed.Subtitle{Link: server.URL}.DownloadAsASSContext(context.Background(), path))
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, addic7ed.FormatASS, addic7ed.DetectFormat(content))
+
+	// Cancelled downloads write nothing
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	path = filepath.Join(t.TempDir(), "sub.vtt")
+	err = addic7ed.Subtitle{Link: server.URL}.DownloadAsVTTContext(ctx, path)
+	assert.True(t, errors.Is(err, context.Canceled), "unexpected error %v", err)
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err), "unexpected error %v", err)
+}
+
+func TestDownloadConvertedKeepsSubtitlesInTheFormat(t *testing.T) {
+	const vtt = "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nHello\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(vtt))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	assert.NoError(t, addic7ed.Subtitle{Link: server.URL}.DownloadConverted(&out, addic7ed.FormatVTT))
+	assert.Equal(t, vtt, out.String())
+
+	err := addic7ed.Subtitle{Link: server.URL}.DownloadConverted(io.Discard, addic7ed.FormatASS)
+	assert.True(t, errors.Is(err, addic7ed.ErrUnacceptableContent), "unexpected error %v", err)
+}
@@ -0,0 +1,202 @@
+package addic7ed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+// WithCookieFile persists the cookies of the client, like the session of Login or language preferences, to a JSON file.
+// The cookies of the file are loaded when the client is created, and the file is updated whenever Addic7ed sets cookies,
+// so that long-running processes and successive runs of a command keep their Addic7ed session.
+// The file is created if it does not exist. It holds session secrets, so it is only readable by its owner.
+func WithCookieFile(path string) Option {
+	return func(c *Client) {
+		c.cookieFile = path
+	}
+}
+
+// cookieFileVersion is the version of the format of cookie files. Files of the first releases of WithCookieFile are bare
+// JSON arrays of cookies, without version, and are still loaded.
+const cookieFileVersion = 1
+
+// cookieFileContent is the content of a cookie file
+type cookieFileContent struct {
+	Version int           `json:"version"`
+	Cookies []savedCookie `json:"cookies"`
+}
+
+// errCookieFileVersion is returned when a cookie file was written by a newer version of the package
+var errCookieFileVersion = errors.New("unknown version of cookie file")
+
+// savedCookie is a cookie as saved in a cookie file, with the URL that set it
+type savedCookie struct {
+	URL      string    `json:"url"`
+	Name     string    `json:"name"`
+	Value    string    `json:"value"`
+	Domain   string    `json:"domain,omitempty"`
+	Path     string    `json:"path,omitempty"`
+	Expires  time.Time `json:"expires,omitempty"`
+	Secure   bool      `json:"secure,omitempty"`
+	HttpOnly bool      `json:"httpOnly,omitempty"`
+}
+
+// fileJar is a cookie jar saving its cookies to a file
+// The cookies are kept by the standard cookie jar, which can't list them, so saved cookies are tracked aside.
+type fileJar struct {
+	path    string
+	jar     *cookiejar.Jar
+	onError func(err error)
+	// frozen is set when the file can't be read by this version of the package, so that it is not overwritten
+	frozen bool
+
+	mu      sync.Mutex
+	cookies map[string]savedCookie
+}
+
+// useCookieFile sets a cookie jar saving its cookies to the cookie file of the client, loading the cookies already saved.
+// The HTTP client of the client is copied, so that the jar is not shared with other users of the HTTP client
+func (c *Client) useCookieFile() {
+	logs := c.newCall(context.Background(), nil)
+	jar, _ := cookiejar.New(nil)
+	fj := &fileJar{
+		path:    c.cookieFile,
+		jar:     jar,
+		cookies: map[string]savedCookie{},
+		onError: func(err error) {
+			logs.errorf("Unable to save cookies to %v: %v", c.cookieFile, err)
+		},
+	}
+	if err := fj.load(); errors.Is(err, errCookieFileVersion) {
+		fj.frozen = true
+		logs.errorf("Unable to load cookies from %v, starting without cookies and leaving the file untouched: %v", c.cookieFile, err)
+	} else if err != nil {
+		logs.errorf("Unable to load cookies from %v, starting without cookies: %v", c.cookieFile, err)
+	}
+	httpClient := *c.httpClient
+	httpClient.Jar = fj
+	c.httpClient = &httpClient
+}
+
+func (fj *fileJar) load() error {
+	data, err := os.ReadFile(fj.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var content cookieFileContent
+	if trimmed := bytes.TrimSpace(data); bytes.HasPrefix(trimmed, []byte("[")) {
+		// Cookie files without version
+		err = json.Unmarshal(trimmed, &content.Cookies)
+	} else if err = json.Unmarshal(data, &content); err == nil && content.Version != cookieFileVersion {
+		err = fmt.Errorf("%w %v, expected %v", errCookieFileVersion, content.Version, cookieFileVersion)
+	}
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, cookie := range content.Cookies {
+		u, err := url.Parse(cookie.URL)
+		if err != nil || (!cookie.Expires.IsZero() && cookie.Expires.Before(now)) {
+			continue
+		}
+		fj.jar.SetCookies(u, []*http.Cookie{{
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Domain:   cookie.Domain,
+			Path:     cookie.Path,
+			Expires:  cookie.Expires,
+			Secure:   cookie.Secure,
+			HttpOnly: cookie.HttpOnly,
+		}})
+		fj.cookies[cookieKey(u, cookie.Domain, cookie.Path, cookie.Name)] = cookie
+	}
+	return nil
+}
+
+// SetCookies sets cookies in the jar and saves them to the file
+func (fj *fileJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	fj.jar.SetCookies(u, cookies)
+
+	fj.mu.Lock()
+	defer fj.mu.Unlock()
+	now := time.Now()
+	for _, cookie := range cookies {
+		key := cookieKey(u, cookie.Domain, cookie.Path, cookie.Name)
+		expires := cookie.Expires
+		if cookie.MaxAge > 0 {
+			expires = now.Add(time.Duration(cookie.MaxAge) * time.Second)
+		}
+		if cookie.MaxAge < 0 || (!expires.IsZero() && expires.Before(now)) {
+			delete(fj.cookies, key)
+			continue
+		}
+		fj.cookies[key] = savedCookie{
+			URL:      (&url.URL{Scheme: u.Scheme, Host: u.Host, Path: "/"}).String(),
+			Name:     cookie.Name,
+			Value:    cookie.Value,
+			Domain:   cookie.Domain,
+			Path:     cookie.Path,
+			Expires:  expires,
+			Secure:   cookie.Secure,
+			HttpOnly: cookie.HttpOnly,
+		}
+	}
+	if fj.frozen {
+		return
+	}
+	if err := fj.save(); err != nil {
+		fj.onError(err)
+	}
+}
+
+// Cookies returns the cookies to send to a URL
+func (fj *fileJar) Cookies(u *url.URL) []*http.Cookie {
+	return fj.jar.Cookies(u)
+}
+
+// save writes the cookies to the file, through a temporary file so that the file is never left half written
+func (fj *fileJar) save() error {
+	saved := make([]savedCookie, 0, len(fj.cookies))
+	for _, key := range slices.Sorted(maps.Keys(fj.cookies)) {
+		saved = append(saved, fj.cookies[key])
+	}
+	data, err := json.MarshalIndent(cookieFileContent{Version: cookieFileVersion, Cookies: saved}, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(fj.path), filepath.Base(fj.path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), fj.path)
+}
+
+// cookieKey identifies a cookie by the host that set it, its domain, its path and its name
+func cookieKey(u *url.URL, domain, path, name string) string {
+	if domain == "" {
+		domain = u.Hostname()
+	}
+	return domain + "|" + path + "|" + name
+}
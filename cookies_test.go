@@ -0,0 +1,98 @@
+package addic7ed_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+func TestWithCookieFile(t *testing.T) {
+	var received []string
+	handler := episodeHandler(t, nil)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("PHPSESSID"); err == nil {
+			received = append(received, cookie.Value)
+		} else {
+			http.SetCookie(w, &http.Cookie{Name: "PHPSESSID", Value: "session", Path: "/"})
+			http.SetCookie(w, &http.Cookie{Name: "expired", Value: "gone", Path: "/", MaxAge: -1})
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+	path := filepath.Join(t.TempDir(), "cookies.json")
+
+	first := addic7ed.New(addic7ed.WithBaseURL(server.URL), addic7ed.WithCookieFile(path))
+	_, err := first.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+	info, err := os.Stat(path)
+	if assert.NoError(t, err) {
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	}
+
+	// A new client, like the next run of a command, gets the session back
+	received = nil
+	second := addic7ed.New(addic7ed.WithBaseURL(server.URL), addic7ed.WithCookieFile(path))
+	_, err = second.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, received)
+	assert.Equal(t, "session", received[0])
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "expired")
+	assert.Contains(t, string(content), `"version": 1`)
+}
+
+func TestWithCookieFileWithoutVersion(t *testing.T) {
+	var received []string
+	handler := episodeHandler(t, nil)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("PHPSESSID"); err == nil {
+			received = append(received, cookie.Value)
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	// Cookie files of the first releases are bare arrays of cookies
+	path := filepath.Join(t.TempDir(), "cookies.json")
+	legacy := `[{"url": "` + server.URL + `/", "name": "PHPSESSID", "value": "session", "path": "/"}]`
+	assert.NoError(t, os.WriteFile(path, []byte(legacy), 0600))
+	c := addic7ed.New(addic7ed.WithBaseURL(server.URL), addic7ed.WithCookieFile(path))
+	_, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"session"}, received)
+}
+
+func TestWithCookieFileOfUnknownVersion(t *testing.T) {
+	handler := episodeHandler(t, nil)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "PHPSESSID", Value: "new", Path: "/"})
+		handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	// Files written by a newer version are left untouched, so that their session is not lost
+	path := filepath.Join(t.TempDir(), "cookies.json")
+	newer := `{"version": 2, "sessions": {"PHPSESSID": "session"}}`
+	assert.NoError(t, os.WriteFile(path, []byte(newer), 0600))
+	c := addic7ed.New(addic7ed.WithBaseURL(server.URL), addic7ed.WithCookieFile(path))
+	_, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, newer, string(content))
+}
+
+func TestWithCookieFileIgnoresBrokenFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cookies.json")
+	assert.NoError(t, os.WriteFile(path, []byte("not json"), 0600))
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{episodeHandler(t, nil)}}), addic7ed.WithCookieFile(path))
+	_, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+}
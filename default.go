@@ -0,0 +1,42 @@
+package addic7ed
+
+import (
+	"context"
+	"sync"
+)
+
+var (
+	defaultClient     *Client
+	defaultClientOnce sync.Once
+)
+
+// DefaultClient returns the client used by the package-level functions SearchAll and SearchBest.
+// It is created on first use and is safe for concurrent use.
+func DefaultClient() *Client {
+	defaultClientOnce.Do(func() {
+		defaultClient = New()
+	})
+	return defaultClient
+}
+
+// SearchAll searches in the Addic7ed website for a given episode of a show, using the DefaultClient
+// See Client.SearchAll
+func SearchAll(showStr string, opts ...CallOption) (Show, error) {
+	return DefaultClient().SearchAll(showStr, opts...)
+}
+
+// SearchBest searches in the Addic7ed website for the best suitable subtitle of given episode of a show, using the DefaultClient
+// See Client.SearchBest
+func SearchBest(showStr, lang string, opts ...CallOption) (string, Subtitle, error) {
+	return DefaultClient().SearchBest(showStr, lang, opts...)
+}
+
+// SearchAllContext is like SearchAll, with a context to cancel the search
+func SearchAllContext(ctx context.Context, showStr string, opts ...CallOption) (Show, error) {
+	return DefaultClient().SearchAllContext(ctx, showStr, opts...)
+}
+
+// SearchBestContext is like SearchBest, with a context to cancel the search
+func SearchBestContext(ctx context.Context, showStr, lang string, opts ...CallOption) (string, Subtitle, error) {
+	return DefaultClient().SearchBestContext(ctx, showStr, lang, opts...)
+}
@@ -0,0 +1,230 @@
+package addic7ed
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Cue is a cue of a subtitle
+type Cue struct {
+	Start time.Duration
+	End   time.Duration
+	// Text is the text of the cue, with its lines separated by "\n"
+	Text string
+}
+
+// CueChangeKind is the kind of a change between two versions of a subtitle
+type CueChangeKind string
+
+const (
+	// CueAdded is a cue only found in the new version
+	CueAdded CueChangeKind = "added"
+	// CueRemoved is a cue only found in the old version
+	CueRemoved CueChangeKind = "removed"
+	// CueRetimed is a cue with the same text in both versions, but different timings
+	CueRetimed CueChangeKind = "retimed"
+	// CueEdited is a cue whose text changed, shown at about the same time in both versions
+	CueEdited CueChangeKind = "edited"
+)
+
+// CueChange is a change of a cue between two versions of a subtitle
+type CueChange struct {
+	Kind CueChangeKind
+	// Old is the cue in the old version, zero for added cues
+	Old Cue
+	// New is the cue in the new version, zero for removed cues
+	New Cue
+	// StartDelta and EndDelta are the shifts of the timings of retimed and edited cues, from the old version to the new one
+	StartDelta time.Duration
+	EndDelta   time.Duration
+}
+
+func (c CueChange) String() string {
+	switch c.Kind {
+	case CueAdded:
+		return fmt.Sprintf("+ %v %q", formatSRTTimestamp(c.New.Start), c.New.Text)
+	case CueRemoved:
+		return fmt.Sprintf("- %v %q", formatSRTTimestamp(c.Old.Start), c.Old.Text)
+	case CueRetimed:
+		sign := ""
+		if c.StartDelta > 0 {
+			sign = "+"
+		}
+		return fmt.Sprintf("~ %v -> %v (%v%v) %q", formatSRTTimestamp(c.Old.Start), formatSRTTimestamp(c.New.Start), sign, c.StartDelta, c.New.Text)
+	default:
+		return fmt.Sprintf("* %v %q -> %q", formatSRTTimestamp(c.New.Start), c.Old.Text, c.New.Text)
+	}
+}
+
+// SubtitleDiff is the cue-level difference between two versions of a subtitle, like an "original" and a "corrected" upload
+type SubtitleDiff struct {
+	// Changes are the changed cues, in the order of the subtitles
+	Changes []CueChange
+	// Unchanged is the number of cues with the same text and timings in both versions
+	Unchanged int
+	// Offset is the median shift of the cues with the same text, from the old version to the new one.
+	// A non-zero offset usually means that the versions are synchronized with different releases
+	Offset time.Duration
+}
+
+// Equal checks whether both versions have the same cues
+func (d SubtitleDiff) Equal() bool {
+	return len(d.Changes) == 0
+}
+
+// Diff downloads the subtitle and another version of it, and compares them cue by cue, the subtitle being the old version.
+// Both must be SRT subtitles, see DiffSRT.
+func (s Subtitle) Diff(other Subtitle) (SubtitleDiff, error) {
+	return s.DiffContext(context.Background(), other)
+}
+
+// DiffContext is like Diff, with a context to cancel the downloads
+func (s Subtitle) DiffContext(ctx context.Context, other Subtitle) (SubtitleDiff, error) {
+	old, err := s.download(ctx)
+	if err != nil {
+		return SubtitleDiff{}, err
+	}
+	new, err := other.download(ctx)
+	if err != nil {
+		return SubtitleDiff{}, err
+	}
+	for _, data := range [][]byte{old, new} {
+		if format := DetectFormat(data); format != FormatSRT {
+			return SubtitleDiff{}, newError(CodeUnacceptableContent, nil, "subtitles of format %q can't be compared", format)
+		}
+	}
+	return DiffSRT(bytes.NewReader(old), bytes.NewReader(new))
+}
+
+// DiffSRT compares two versions of a SRT subtitle cue by cue.
+// Cues are matched by text, ignoring case, spacing and formatting tags. Matched cues with different timings are retimed.
+// Unmatched cues shown at overlapping times are edited, other unmatched cues are added or removed.
+func DiffSRT(old, new io.Reader) (SubtitleDiff, error) {
+	oldCues, err := parseSRT(old)
+	if err != nil {
+		return SubtitleDiff{}, newError(CodeParseFailure, err, "Unable to read the old subtitle")
+	}
+	newCues, err := parseSRT(new)
+	if err != nil {
+		return SubtitleDiff{}, newError(CodeParseFailure, err, "Unable to read the new subtitle")
+	}
+
+	oldTexts, newTexts := make([]string, len(oldCues)), make([]string, len(newCues))
+	for i, c := range oldCues {
+		oldTexts[i] = comparableText(c)
+	}
+	for i, c := range newCues {
+		newTexts[i] = comparableText(c)
+	}
+
+	diff := SubtitleDiff{}
+	var shifts []time.Duration
+	i, j := 0, 0
+	for _, match := range matchTexts(oldTexts, newTexts) {
+		diff.Changes = append(diff.Changes, diffUnmatched(oldCues[i:match[0]], newCues[j:match[1]])...)
+		o, n := oldCues[match[0]], newCues[match[1]]
+		shifts = append(shifts, n.start-o.start)
+		if o.start == n.start && o.end == n.end {
+			diff.Unchanged++
+		} else {
+			diff.Changes = append(diff.Changes, cueChange(CueRetimed, o, n))
+		}
+		i, j = match[0]+1, match[1]+1
+	}
+	diff.Changes = append(diff.Changes, diffUnmatched(oldCues[i:], newCues[j:])...)
+	if len(shifts) > 0 {
+		slices.Sort(shifts)
+		diff.Offset = shifts[len(shifts)/2]
+	}
+	return diff, nil
+}
+
+// comparableText returns the text of a cue without formatting nor bidirectional control characters, lowered and with normalized spaces
+func comparableText(c cue) string {
+	text := strings.Join(c.lines, " ")
+	text = srtOverrideRegexp.ReplaceAllString(srtTagRegexp.ReplaceAllString(text, ""), "")
+	text = strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Bidi_Control, r) {
+			return -1
+		}
+		return r
+	}, text)
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
+// matchTexts returns the indexes of the longest common subsequence of two lists of texts, in order
+func matchTexts(a, b []string) [][2]int {
+	// lengths[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lengths := make([][]int32, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else {
+				lengths[i][j] = max(lengths[i+1][j], lengths[i][j+1])
+			}
+		}
+	}
+	matches := [][2]int{}
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			matches = append(matches, [2]int{i, j})
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return matches
+}
+
+// diffUnmatched returns the changes of cues found between two matched cues, pairing the cues shown at overlapping times as edited
+func diffUnmatched(old, new []cue) []CueChange {
+	changes := []CueChange{}
+	for len(old) > 0 && len(new) > 0 {
+		o, n := old[0], new[0]
+		switch {
+		case o.start < n.end && n.start < o.end:
+			changes = append(changes, cueChange(CueEdited, o, n))
+			old, new = old[1:], new[1:]
+		case o.end <= n.start:
+			changes = append(changes, cueChange(CueRemoved, o, cue{}))
+			old = old[1:]
+		default:
+			changes = append(changes, cueChange(CueAdded, cue{}, n))
+			new = new[1:]
+		}
+	}
+	for _, o := range old {
+		changes = append(changes, cueChange(CueRemoved, o, cue{}))
+	}
+	for _, n := range new {
+		changes = append(changes, cueChange(CueAdded, cue{}, n))
+	}
+	return changes
+}
+
+func cueChange(kind CueChangeKind, old, new cue) CueChange {
+	change := CueChange{
+		Kind: kind,
+		Old:  Cue{Start: old.start, End: old.end, Text: strings.Join(old.lines, "\n")},
+		New:  Cue{Start: new.start, End: new.end, Text: strings.Join(new.lines, "\n")},
+	}
+	if kind == CueRetimed || kind == CueEdited {
+		change.StartDelta = new.start - old.start
+		change.EndDelta = new.end - old.end
+	}
+	return change
+}
@@ -0,0 +1,85 @@
+package addic7ed_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+const (
+	originalSRT = "1\n00:00:01,000 --> 00:00:02,000\nHello\n\n" +
+		"2\n00:00:03,000 --> 00:00:04,000\nHow are you?\n\n" +
+		"3\n00:00:05,000 --> 00:00:06,000\nI'm fine.\n\n" +
+		"4\n00:00:07,000 --> 00:00:08,000\nBye\n"
+	correctedSRT = "1\r\n00:00:01,000 --> 00:00:02,000\r\n<i>hello</i>\r\n\r\n" +
+		"2\r\n00:00:03,250 --> 00:00:04,250\r\nHow are you?\r\n\r\n" +
+		"3\r\n00:00:05,000 --> 00:00:06,500\r\nI am fine.\r\n\r\n" +
+		"4\r\n00:00:06,600 --> 00:00:06,900\r\nThanks.\r\n"
+)
+
+func TestDiffSRT(t *testing.T) {
+	diff, err := addic7ed.DiffSRT(strings.NewReader(originalSRT), strings.NewReader(correctedSRT))
+	assert.NoError(t, err)
+	assert.False(t, diff.Equal())
+	// Formatting and case are ignored
+	assert.Equal(t, 1, diff.Unchanged)
+	assert.Equal(t, []addic7ed.CueChange{
+		{
+			Kind:       addic7ed.CueRetimed,
+			Old:        addic7ed.Cue{Start: 3 * time.Second, End: 4 * time.Second, Text: "How are you?"},
+			New:        addic7ed.Cue{Start: 3250 * time.Millisecond, End: 4250 * time.Millisecond, Text: "How are you?"},
+			StartDelta: 250 * time.Millisecond,
+			EndDelta:   250 * time.Millisecond,
+		},
+		{
+			Kind:     addic7ed.CueEdited,
+			Old:      addic7ed.Cue{Start: 5 * time.Second, End: 6 * time.Second, Text: "I'm fine."},
+			New:      addic7ed.Cue{Start: 5 * time.Second, End: 6500 * time.Millisecond, Text: "I am fine."},
+			EndDelta: 500 * time.Millisecond,
+		},
+		{
+			Kind: addic7ed.CueAdded,
+			New:  addic7ed.Cue{Start: 6600 * time.Millisecond, End: 6900 * time.Millisecond, Text: "Thanks."},
+		},
+		{
+			Kind: addic7ed.CueRemoved,
+			Old:  addic7ed.Cue{Start: 7 * time.Second, End: 8 * time.Second, Text: "Bye"},
+		},
+	}, diff.Changes)
+	assert.Equal(t, 250*time.Millisecond, diff.Offset)
+	assert.Equal(t, `~ 00:00:03,000 -> 00:00:03,250 (+250ms) "How are you?"`, diff.Changes[0].String())
+
+	same, err := addic7ed.DiffSRT(strings.NewReader(originalSRT), strings.NewReader(originalSRT))
+	assert.NoError(t, err)
+	assert.True(t, same.Equal())
+	assert.Equal(t, 4, same.Unchanged)
+}
+
+func TestSubtitleDiff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/original":
+			w.Write([]byte(originalSRT))
+		case "/updated":
+			w.Write([]byte(correctedSRT))
+		default:
+			w.Write([]byte("WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nHello\n"))
+		}
+	}))
+	defer server.Close()
+	original := addic7ed.Subtitle{Link: server.URL + "/original"}
+
+	diff, err := original.Diff(addic7ed.Subtitle{Link: server.URL + "/updated"})
+	assert.NoError(t, err)
+	assert.Len(t, diff.Changes, 4)
+
+	_, err = original.Diff(addic7ed.Subtitle{Link: server.URL + "/vtt"})
+	assert.True(t, errors.Is(err, addic7ed.ErrUnacceptableContent), "unexpected error %v", err)
+}
@@ -0,0 +1,307 @@
+package addic7ed
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// DownloadResult is a downloaded subtitle, with the metadata of its download
+type DownloadResult struct {
+	// Data is the content of the subtitle, extracted from its archive if any
+	Data []byte
+	// Archive is the raw .zip or .rar archive the subtitle was served in, or nil when it was served as is.
+	// ArchiveName is the name of the file of the subtitle in the archive
+	Archive     []byte
+	ArchiveName string
+	// Format is the detected format of the subtitle
+	Format Format
+	// URL is the URL the subtitle was finally downloaded from, after redirections and fallbacks on other variants of the subtitle
+	URL string
+	// ContentType is the Content-Type of the subtitle, as sent by Addic7ed
+	ContentType string
+	// Duration is the time taken by the download, including retries and fallbacks on other variants
+	Duration time.Duration
+	// SHA256 is the hex-encoded SHA-256 hash of Data
+	SHA256 string
+	// Warnings are the non-fatal issues of the download, like a WarningEncodingGuessed
+	Warnings []Warning
+}
+
+// Fetch downloads the subtitle in-memory, like Download, with the metadata of the download for logs, metrics or manifests
+func (s Subtitle) Fetch() (DownloadResult, error) {
+	return s.FetchContext(context.Background())
+}
+
+// download downloads the subtitle in-memory, see FetchContext
+func (s Subtitle) download(ctx context.Context) ([]byte, error) {
+	result, err := s.FetchContext(ctx)
+	return result.Data, err
+}
+
+// FetchContext is like Fetch, with a context to cancel the download
+// The subtitle is extracted from its archive if any, and its content is checked.
+// When the link of the subtitle is not found, the other variants of the subtitle (original, updated, most updated) are tried in order.
+// Subtitles found through a MultiProvider are downloaded by the provider that found them.
+func (s Subtitle) FetchContext(ctx context.Context) (DownloadResult, error) {
+	if s.provider != nil {
+		return s.provider.Download(ctx, s)
+	}
+	return s.fetch(ctx)
+}
+
+// fetch downloads the subtitle from Addic7ed, see FetchContext
+func (s Subtitle) fetch(ctx context.Context) (DownloadResult, error) {
+	if s.client != nil && s.client.readOnly {
+		return DownloadResult{}, ErrReadOnly
+	}
+	start := time.Now()
+	result, err := s.downloadLink(ctx, s.Link)
+	for _, variant := range s.variants {
+		if !isNotFound(err) {
+			break
+		}
+		result, err = s.downloadLink(ctx, variant)
+	}
+	if err != nil {
+		return DownloadResult{}, err
+	}
+	hash := sha256.Sum256(result.Data)
+	result.SHA256 = hex.EncodeToString(hash[:])
+	result.Duration = time.Since(start)
+	return result, nil
+}
+
+// isNotFound checks whether a download failed because the link doesn't exist on Addic7ed
+func isNotFound(err error) bool {
+	var statusErr *StatusError
+	return errors.As(err, &statusErr) && (statusErr.StatusCode == http.StatusNotFound || statusErr.StatusCode == http.StatusGone)
+}
+
+// downloadLink downloads a link of the subtitle in-memory
+func (s Subtitle) downloadLink(ctx context.Context, link string) (DownloadResult, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", link, nil)
+	if err != nil {
+		return DownloadResult{}, err
+	}
+	// Avoid getting cached pages
+	req.Header.Add("Cache-Control", "no-cache")
+	req.Header.Add("User-Agent", userAgent)
+	req.Header.Add("Referer", link) // Without it, the Addic7ed server redirect to the web page instead of dl the srt file
+
+	var resp *http.Response
+	if s.client != nil {
+		s.client.setHeaders(req)
+		resp, err = s.client.do(req, "", nil)
+	} else {
+		resp, err = http.DefaultClient.Do(req)
+	}
+	if err != nil {
+		return DownloadResult{}, newError(CodeServerUnreachable, err, "Unable to reach addic7ed server")
+	}
+	defer resp.Body.Close()
+	if err := checkStatus(resp.StatusCode); err != nil {
+		return DownloadResult{}, err
+	}
+
+	data, err := readDownload(resp, maxDownloadSize, "subtitle "+link)
+	if err != nil {
+		return DownloadResult{}, err
+	}
+	// When the quota is exceeded, Addic7ed serves a web page instead of the subtitle
+	if err := checkDownloadLimit(resp.Header.Get("Content-Type"), data, time.Now()); err != nil {
+		return DownloadResult{}, err
+	}
+	// Only downloads served by Addic7ed count towards its daily limit
+	if s.client != nil {
+		atomic.AddInt64(&s.client.downloads, 1)
+		if err := s.client.checkMIMEType(resp.Header.Get("Content-Type")); err != nil {
+			return DownloadResult{}, err
+		}
+	}
+	// Some subtitles are served in archives
+	var archive []byte
+	if isArchive(data) {
+		archive = data
+	}
+	data, archiveName, err := extractSubtitle(resp.Header.Get("Content-Type"), data)
+	if err != nil {
+		return DownloadResult{}, err
+	}
+	// Subtitles that are neither UTF-8 nor UTF-16 are converted from the legacy encoding of their language, which is a guess
+	var warnings []Warning
+	if s.client != nil && DetectCharset(data) == CharsetWindows1252 && s.client.convertsLegacy(s.Language) {
+		warnings = append(warnings, Warning{
+			Code:    WarningEncodingGuessed,
+			Message: fmt.Sprintf("subtitle %v is not in Unicode, converted from the guessed encoding %v", link, s.client.legacyCharset(s.Language)),
+		})
+	}
+	if s.client != nil && s.client.toUTF8 {
+		data = s.client.decode(data, s.Language)
+	}
+	// Subtitles are checked as text, whatever their encoding, so that UTF-16 subtitles are kept as is without WithUTF8
+	text := ToUTF8(data)
+	if isPlaceholder(text) {
+		return DownloadResult{}, newError(CodeEmptySubtitle, nil, "subtitle %v is empty or not available", link)
+	}
+	if err := checkSubtitle(resp.Header.Get("Content-Type"), text); err != nil {
+		return DownloadResult{}, err
+	}
+	format := DetectFormat(text)
+	if s.client != nil {
+		if err := s.client.checkFormat(format); err != nil {
+			return DownloadResult{}, err
+		}
+		if s.client.rtlMarks {
+			data = MarkRTL(s.client.decode(data, s.Language))
+		}
+		if charset, ok := s.client.encodingFor(s.Language); ok {
+			data, err = s.client.encode(data, s.Language, charset)
+			if err != nil {
+				return DownloadResult{}, err
+			}
+		}
+	}
+	result := DownloadResult{
+		Data:        data,
+		Archive:     archive,
+		ArchiveName: archiveName,
+		Format:      format,
+		URL:         link,
+		ContentType: resp.Header.Get("Content-Type"),
+		Warnings:    warnings,
+	}
+	if resp.Request != nil && resp.Request.URL != nil {
+		result.URL = resp.Request.URL.String()
+	}
+	return result, nil
+}
+
+// checkMIMEType checks that a Content-Type is accepted by the client
+func (c *Client) checkMIMEType(contentType string) error {
+	if len(c.acceptedMIMETypes) == 0 {
+		return nil
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = contentType
+	}
+	for _, accepted := range c.acceptedMIMETypes {
+		if strings.EqualFold(accepted, mediaType) {
+			return nil
+		}
+	}
+	return newError(CodeUnacceptableContent, nil, "downloaded file of type %q is not accepted", contentType)
+}
+
+// checkFormat checks that a subtitle format is accepted by the client
+func (c *Client) checkFormat(format Format) error {
+	if len(c.acceptedFormats) == 0 {
+		return nil
+	}
+	for _, accepted := range c.acceptedFormats {
+		if accepted == format {
+			return nil
+		}
+	}
+	return newError(CodeUnacceptableContent, nil, "downloaded subtitle of format %q is not accepted", format)
+}
+
+// verifiedBody is the body of a download, checking that all data announced by the server is received
+type verifiedBody struct {
+	io.ReadCloser
+	// expected is the Content-Length of the response, -1 if unknown
+	expected int64
+	received int64
+}
+
+func (b *verifiedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.received += int64(n)
+	if errors.Is(err, io.ErrUnexpectedEOF) || (err == io.EOF && b.expected >= 0 && b.received < b.expected) {
+		return n, newError(CodeIncompleteDownload, nil, "subtitle download is incomplete: received %v bytes out of %v", b.received, b.expected)
+	}
+	return n, err
+}
+
+// maxDownloadSize is the maximum size of a downloaded subtitle, archives included, like maxArchivedFileSize
+const maxDownloadSize = maxArchivedFileSize
+
+// readDownload reads the body of a download, checking that it is complete, and failing with CodeParseFailure if it is
+// bigger than max, so that a misbehaving server can't exhaust the memory
+func readDownload(resp *http.Response, max int64, name string) ([]byte, error) {
+	if resp.ContentLength > max {
+		return nil, newError(CodeParseFailure, nil, "%v is bigger than %v MB", name, max>>20)
+	}
+	data, err := io.ReadAll(io.LimitReader(&verifiedBody{ReadCloser: resp.Body, expected: resp.ContentLength}, max+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > max {
+		return nil, newError(CodeParseFailure, nil, "%v is bigger than %v MB", name, max>>20)
+	}
+	return data, nil
+}
+
+// placeholders are texts served instead of subtitles that are not available
+var placeholders = [][]byte{
+	[]byte("subtitle not available"),
+	[]byte("subtitles not available"),
+	[]byte("no subtitle"),
+}
+
+// maxPlaceholderSize is the size above which a file is considered as a real subtitle, whatever its content
+const maxPlaceholderSize = 256
+
+// isPlaceholder checks whether a downloaded file is empty or only contains a placeholder text
+func isPlaceholder(data []byte) bool {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return true
+	}
+	if len(data) > maxPlaceholderSize {
+		return false
+	}
+	lowered := bytes.ToLower(data)
+	for _, placeholder := range placeholders {
+		if bytes.Contains(lowered, placeholder) {
+			return true
+		}
+	}
+	return false
+}
+
+// lazyFile is a file created on the first write
+type lazyFile struct {
+	path string
+	file *os.File
+}
+
+func (f *lazyFile) Write(p []byte) (int, error) {
+	if f.file == nil {
+		file, err := os.Create(f.path)
+		if err != nil {
+			return 0, err
+		}
+		f.file = file
+	}
+	return f.file.Write(p)
+}
+
+// Close closes the file, if it was created
+func (f *lazyFile) Close() error {
+	if f.file == nil {
+		return nil
+	}
+	return f.file.Close()
+}
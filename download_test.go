@@ -0,0 +1,409 @@
+package addic7ed_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+func TestDownloadToWithTruncatedBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		buf.WriteString("HTTP/1.1 200 OK\r\nContent-Type: text/plain\r\nContent-Length: 100\r\n\r\n1\n00:00:01,000 --> 00:00:02,000\n")
+		buf.Flush()
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "truncated.srt")
+	err := addic7ed.Subtitle{Link: server.URL}.DownloadTo(path)
+	assert.True(t, errors.Is(err, addic7ed.ErrIncompleteDownload), "unexpected error %v", err)
+	_, err = os.Stat(path)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDownloadTo(t *testing.T) {
+	const srt = "1\n00:00:01,000 --> 00:00:02,000\nHello\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(srt))
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "sub.srt")
+	assert.NoError(t, addic7ed.Subtitle{Link: server.URL}.DownloadTo(path))
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, srt, string(content))
+}
+
+func TestDownloadFromZipArchive(t *testing.T) {
+	const srt = "1\n00:00:01,000 --> 00:00:02,000\nHello\n"
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	w, err := zw.Create("readme.txt")
+	assert.NoError(t, err)
+	_, err = w.Write([]byte("Downloaded from Addic7ed"))
+	assert.NoError(t, err)
+	w, err = zw.Create("Shameless.US.S08E11.srt")
+	assert.NoError(t, err)
+	_, err = w.Write([]byte(srt))
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/zip")
+		w.Write(archive.Bytes())
+	}))
+	defer server.Close()
+
+	sub, err := addic7ed.Subtitle{Link: server.URL}.Download()
+	assert.NoError(t, err)
+	defer sub.Close()
+	content, err := io.ReadAll(sub)
+	assert.NoError(t, err)
+	assert.Equal(t, srt, string(content))
+
+	// The raw archive is kept with the result
+	result, err := addic7ed.Subtitle{Link: server.URL}.Fetch()
+	assert.NoError(t, err)
+	assert.Equal(t, srt, string(result.Data))
+	assert.Equal(t, archive.Bytes(), result.Archive)
+	assert.Equal(t, "Shameless.US.S08E11.srt", result.ArchiveName)
+}
+
+func TestDownloadInvalidArchive(t *testing.T) {
+	const srt = "1\n00:00:01,000 --> 00:00:02,000\nHello\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/zip" {
+			w.Header().Set("Content-Type", "application/zip")
+		}
+		w.Write([]byte(srt))
+	}))
+	defer server.Close()
+
+	_, err := addic7ed.Subtitle{Link: server.URL + "/zip"}.Fetch()
+	assert.True(t, errors.Is(err, addic7ed.ErrParseFailure), "unexpected error %v", err)
+
+	result, err := addic7ed.Subtitle{Link: server.URL + "/srt"}.Fetch()
+	assert.NoError(t, err)
+	assert.Equal(t, srt, string(result.Data))
+	assert.Nil(t, result.Archive)
+	assert.Empty(t, result.ArchiveName)
+}
+
+func TestDownloadFromOversizedZipArchive(t *testing.T) {
+	var archive bytes.Buffer
+	zw := zip.NewWriter(&archive)
+	w, err := zw.Create("Shameless.US.S08E11.srt")
+	assert.NoError(t, err)
+	_, err = w.Write(bytes.Repeat([]byte("1\n00:00:01,000 --> 00:00:02,000\nHello\n\n"), 1<<18))
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/zip")
+		w.Write(archive.Bytes())
+	}))
+	defer server.Close()
+
+	_, err = addic7ed.Subtitle{Link: server.URL}.Fetch()
+	assert.True(t, errors.Is(err, addic7ed.ErrParseFailure), "unexpected error %v", err)
+}
+
+func TestDownloadOversizedBody(t *testing.T) {
+	body := bytes.Repeat([]byte("1\n00:00:01,000 --> 00:00:02,000\nHello\n\n"), 1<<18)
+	for _, chunked := range []bool{false, true} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if chunked {
+				// Without Content-Length, the body is only cut once read
+				w.(http.Flusher).Flush()
+			}
+			w.Write(body)
+		}))
+
+		_, err := addic7ed.Subtitle{Link: server.URL}.Fetch()
+		assert.True(t, errors.Is(err, addic7ed.ErrParseFailure), "unexpected error %v", err)
+		server.Close()
+	}
+}
+
+func TestDownloadFallsBackOnEmptySubtitles(t *testing.T) {
+	const srt = "1\n00:00:01,000 --> 00:00:02,000\nHello\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/empty":
+		case "/placeholder":
+			w.Write([]byte("Subtitle not available\n"))
+		default:
+			w.Write([]byte(srt))
+		}
+	}))
+	defer server.Close()
+
+	_, err := addic7ed.Subtitle{Link: server.URL + "/placeholder"}.Download()
+	assert.True(t, errors.Is(err, addic7ed.ErrEmptySubtitle), "unexpected error %v", err)
+
+	subs := addic7ed.Subtitles{
+		{Version: "A", Link: server.URL + "/empty"},
+		{Version: "B", Link: server.URL + "/placeholder"},
+		{Version: "C", Link: server.URL + "/good"},
+	}
+	sub, content, err := subs.Download()
+	assert.NoError(t, err)
+	defer content.Close()
+	assert.Equal(t, "C", sub.Version)
+
+	_, _, err = subs[:2].Download()
+	assert.True(t, errors.Is(err, addic7ed.ErrEmptySubtitle), "unexpected error %v", err)
+}
+
+func TestDownloadContextIsCancelled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := addic7ed.Subtitle{Link: server.URL}.DownloadContext(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "unexpected error %v", err)
+	assert.Equal(t, addic7ed.CodeServerUnreachable, addic7ed.ErrorCodeOf(err))
+}
+
+func TestDownloadAsUsesDetectedFormat(t *testing.T) {
+	const vtt = "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nHello\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(vtt))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	path, err := addic7ed.Subtitle{Link: server.URL}.DownloadAs(filepath.Join(dir, "sub.srt"))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "sub.vtt"), path)
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, vtt, string(content))
+}
+
+func TestDownloadAlongside(t *testing.T) {
+	const srt = "1\n00:00:01,000 --> 00:00:02,000\nHello\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(srt))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	video := filepath.Join(dir, "Show.S01E01.GROUP.mkv")
+	path, err := addic7ed.Subtitle{Link: server.URL, Language: "English"}.DownloadAlongside(video, true)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "Show.S01E01.GROUP.en.srt"), path)
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, srt, string(content))
+
+	path, err = addic7ed.Subtitle{Link: server.URL, Language: "Portuguese (Brazilian)"}.DownloadAlongside(video, true)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "Show.S01E01.GROUP.pt-BR.srt"), path)
+
+	path, err = addic7ed.Subtitle{Link: server.URL, Language: "English"}.DownloadAlongside(video, false)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "Show.S01E01.GROUP.srt"), path)
+}
+
+func TestDownloadWithLimitExceeded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+		w.Write([]byte("<html><body>Daily Download count exceeded. Your counter will be reset in 3 hours and 20 minutes.</body></html>"))
+	}))
+	defer server.Close()
+
+	before := time.Now()
+	_, err := addic7ed.Subtitle{Link: server.URL}.Download()
+	assert.True(t, errors.Is(err, addic7ed.ErrDownloadLimitExceeded), "unexpected error %v", err)
+	var limitErr *addic7ed.DownloadLimitError
+	if assert.True(t, errors.As(err, &limitErr)) {
+		assert.WithinDuration(t, before.Add(3*time.Hour+20*time.Minute), limitErr.ResetAt, time.Minute)
+	}
+}
+
+func TestDownloadWithErrorPage(t *testing.T) {
+	const page = "<!DOCTYPE html><html><body>Something went wrong</body></html>"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=UTF-8")
+		w.Write([]byte(page))
+	}))
+	defer server.Close()
+
+	_, err := addic7ed.Subtitle{Link: server.URL}.Download()
+	assert.True(t, errors.Is(err, addic7ed.ErrInvalidSubtitle), "unexpected error %v", err)
+	var invalidErr *addic7ed.InvalidSubtitleError
+	if assert.True(t, errors.As(err, &invalidErr)) {
+		assert.Equal(t, "text/html; charset=UTF-8", invalidErr.ContentType)
+		assert.Equal(t, page, invalidErr.HTML)
+	}
+}
+
+func TestDownloadWithUnknownFormat(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("This is not a subtitle. ", 20)))
+	}))
+	defer server.Close()
+
+	_, err := addic7ed.Subtitle{Link: server.URL}.Download()
+	assert.True(t, errors.Is(err, addic7ed.ErrInvalidSubtitle), "unexpected error %v", err)
+	var invalidErr *addic7ed.InvalidSubtitleError
+	if assert.True(t, errors.As(err, &invalidErr)) {
+		assert.Empty(t, invalidErr.HTML)
+	}
+}
+
+// handlerTransport serves requests with a handler, without reaching the network
+type handlerTransport struct {
+	http.Handler
+}
+
+func (t handlerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorder := httptest.NewRecorder()
+	t.ServeHTTP(recorder, req)
+	return recorder.Result(), nil
+}
+
+// episodeHandler serves the episode fixture as search results and as episode pages, and subtitles from the given contents by path.
+// Paths without content are not found.
+func episodeHandler(t *testing.T, contents map[string]string) http.Handler {
+	page, err := os.ReadFile("testdata/episode.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/srch.php" || strings.HasPrefix(r.URL.Path, "/serie/") {
+			w.Write(page)
+			return
+		}
+		content, ok := contents[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(content))
+	})
+}
+
+func TestDownloadFallsBackOnOtherVariants(t *testing.T) {
+	const srt = "1\n00:00:01,000 --> 00:00:02,000\nBonjour\n"
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{episodeHandler(t, map[string]string{
+		"/original/131967/1": srt,
+		"/original/131967/2": srt,
+	})}}))
+	show, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+
+	updated := show.Subtitles.Filter(func(s addic7ed.Subtitle) bool { return s.Language == "French" && s.IsUpdated() })
+	assert.Len(t, updated, 1)
+	content, err := updated[0].Download()
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(content)
+	assert.Equal(t, srt, string(data))
+
+	// Once all variants of a version are not found, other versions are tried
+	english := show.Subtitles.Filter(addic7ed.WithLanguage("English"))
+	sub, _, err := english.Download()
+	assert.NoError(t, err)
+	assert.Equal(t, "WEB.x264-TBS", sub.Version)
+
+	_, err = english[0].Download()
+	var statusErr *addic7ed.StatusError
+	assert.True(t, errors.As(err, &statusErr), "unexpected error %v", err)
+	assert.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+}
+
+func TestUsageCountsServedDownloadsOnly(t *testing.T) {
+	const srt = "1\n00:00:01,000 --> 00:00:02,000\nBonjour\n"
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{episodeHandler(t, map[string]string{
+		"/original/131967/1":  "<html><body>Daily Download count exceeded.</body></html>",
+		"/updated/8/131967/1": srt,
+	})}}), addic7ed.WithoutRetry())
+	show, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+
+	// Pages of errors and of exceeded quota are not downloads of subtitles
+	_, err = show.Subtitles.Filter(addic7ed.WithLanguage("English"))[0].Download()
+	assert.Error(t, err)
+	_, err = show.Subtitles.Filter(func(s addic7ed.Subtitle) bool { return s.Language == "French" && !s.IsUpdated() })[0].Download()
+	assert.True(t, errors.Is(err, addic7ed.ErrDownloadLimitExceeded), "unexpected error %v", err)
+	assert.Equal(t, 0, c.Usage().Downloads)
+
+	_, err = show.Subtitles.Filter(func(s addic7ed.Subtitle) bool { return s.Language == "French" && s.IsUpdated() })[0].Download()
+	assert.NoError(t, err)
+	assert.Equal(t, 1, c.Usage().Downloads)
+}
+
+func TestDownloadToWriter(t *testing.T) {
+	const srt = "1\n00:00:01,000 --> 00:00:02,000\nHello\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/placeholder" {
+			w.Write([]byte("Subtitle not available\n"))
+			return
+		}
+		w.Write([]byte(srt))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	assert.NoError(t, addic7ed.Subtitle{Link: server.URL + "/good"}.DownloadToWriter(&buf))
+	assert.Equal(t, srt, buf.String())
+
+	buf.Reset()
+	err := addic7ed.Subtitle{Link: server.URL + "/placeholder"}.DownloadToWriter(&buf)
+	assert.True(t, errors.Is(err, addic7ed.ErrEmptySubtitle), "unexpected error %v", err)
+	assert.Zero(t, buf.Len())
+
+	// A failed download does not overwrite an existing file
+	path := filepath.Join(t.TempDir(), "existing.srt")
+	assert.NoError(t, os.WriteFile(path, []byte(srt), 0o644))
+	assert.Error(t, addic7ed.Subtitle{Link: server.URL + "/placeholder"}.DownloadTo(path))
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, srt, string(data))
+}
+
+func TestFetch(t *testing.T) {
+	const srt = "1\n00:00:01,000 --> 00:00:02,000\nHello\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/original/131967/0" {
+			http.Redirect(w, r, "/files/sub.srt", http.StatusFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/x-subrip")
+		w.Write([]byte(srt))
+	}))
+	defer server.Close()
+
+	result, err := addic7ed.Subtitle{Link: server.URL + "/original/131967/0"}.Fetch()
+	assert.NoError(t, err)
+	assert.Equal(t, srt, string(result.Data))
+	assert.Equal(t, addic7ed.FormatSRT, result.Format)
+	assert.Equal(t, server.URL+"/files/sub.srt", result.URL)
+	assert.Equal(t, "application/x-subrip", result.ContentType)
+	assert.Equal(t, "f3dbec9985e9a5adec726c4be0beab46d00d9b6ef7041c807e4c9b01df6bbf24", result.SHA256)
+	assert.True(t, result.Duration > 0)
+}
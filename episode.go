@@ -0,0 +1,184 @@
+package addic7ed
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// EpisodeNumber identifies an episode of a show by its season and episode numbers
+type EpisodeNumber struct {
+	Season  int
+	Episode int
+}
+
+func (e EpisodeNumber) String() string {
+	return fmt.Sprintf("S%02dE%02d", e.Season, e.Episode)
+}
+
+// EpisodeMapping maps episode numbers as found in filenames to episode numbers as known by Addic7ed.
+// For example, Addic7ed may count a special as S05E00 while files use S00E12.
+type EpisodeMapping map[EpisodeNumber]EpisodeNumber
+
+// WithEpisodeMapping sets the mapping of the episodes of a show, applied when searching for an episode of this show.
+// The show is identified by its name, regardless of case and separators ("The.Office.US" matches "The Office (US)")
+func WithEpisodeMapping(show string, mapping EpisodeMapping) Option {
+	return func(c *Client) {
+		if c.episodeMappings == nil {
+			c.episodeMappings = map[string]EpisodeMapping{}
+		}
+		c.episodeMappings[normalizeShowName(show)] = mapping
+	}
+}
+
+var (
+	seasonEpisodeRegexp = regexp.MustCompile(`(?i)\bS(\d{1,2})E(\d{1,3})\b`)
+	crossEpisodeRegexp  = regexp.MustCompile(`(?i)\b(\d{1,2})x(\d{2,3})\b`)
+	multiEpisodeRegexp  = regexp.MustCompile(`(?i)\bS(\d{1,2})((?:[-.]?E\d{1,3}){2,})\b`)
+	episodeRegexp       = regexp.MustCompile(`(?i)E(\d{1,3})`)
+)
+
+// normalizeShowName gives a form of a show name that does not depend on case and separators
+func normalizeShowName(show string) string {
+	return strings.ToLower(strings.Join(Words(show), " "))
+}
+
+// findEpisodeNumber finds the episode number in a search, like "S01E02" or "01x02"
+// It returns the episode number and the position of the episode number in the search, or false if not found
+func findEpisodeNumber(search string) (EpisodeNumber, []int, bool) {
+	for _, r := range []*regexp.Regexp{seasonEpisodeRegexp, crossEpisodeRegexp} {
+		if loc := r.FindStringSubmatchIndex(search); loc != nil {
+			season, _ := strconv.Atoi(search[loc[2]:loc[3]])
+			episode, _ := strconv.Atoi(search[loc[4]:loc[5]])
+			return EpisodeNumber{Season: season, Episode: episode}, loc[:2], true
+		}
+	}
+	return EpisodeNumber{}, nil, false
+}
+
+// mapEpisode applies the episode mapping of the show on a search.
+// The search is returned as is when the show has no mapping for the episode
+func (c *Client) mapEpisode(search string) string {
+	if len(c.episodeMappings) == 0 {
+		return search
+	}
+	number, loc, ok := findEpisodeNumber(search)
+	if !ok {
+		return search
+	}
+	mapping, ok := c.episodeMappings[normalizeShowName(search[:loc[0]])]
+	if !ok {
+		return search
+	}
+	mapped, ok := mapping[number]
+	if !ok {
+		return search
+	}
+	return search[:loc[0]] + mapped.String() + search[loc[1]:]
+}
+
+// findMultiPartEpisodes finds the episode numbers of a search covering multiple episodes, like "S01E01-E02" or "S01E01E02"
+// It returns the episode numbers and the position of the episode numbers in the search, or false if the search covers one episode
+func findMultiPartEpisodes(search string) ([]EpisodeNumber, []int, bool) {
+	loc := multiEpisodeRegexp.FindStringSubmatchIndex(search)
+	if loc == nil {
+		return nil, nil, false
+	}
+	season, _ := strconv.Atoi(search[loc[2]:loc[3]])
+	numbers := []EpisodeNumber{}
+	for _, m := range episodeRegexp.FindAllStringSubmatch(search[loc[4]:loc[5]], -1) {
+		episode, _ := strconv.Atoi(m[1])
+		numbers = append(numbers, EpisodeNumber{Season: season, Episode: episode})
+	}
+	return numbers, loc[:2], true
+}
+
+// SearchEpisode searches in the Addic7ed website for the subtitles of an episode, given the name of the show and the episode numbers.
+// The name of the show must be the one used by Addic7ed, like "Shameless (US)". The episode mapping of the show applies, see WithEpisodeMapping.
+// Unlike SearchAll, it does not rely on the search feature of the website, so it is more reliable when the show and the episode are known.
+// It returns the episode with its subtitles in the given language.
+func (c *Client) SearchEpisode(show string, season, episode int, lang string, opts ...CallOption) (Show, error) {
+	return c.SearchEpisodeContext(context.Background(), show, season, episode, lang, opts...)
+}
+
+// SearchEpisodeContext is like SearchEpisode, with a context to cancel the search
+func (c *Client) SearchEpisodeContext(ctx context.Context, show string, season, episode int, lang string, opts ...CallOption) (Show, error) {
+	call := c.newCall(ctx, opts)
+	number := EpisodeNumber{Season: season, Episode: episode}
+	if mapped, ok := c.episodeMappings[normalizeShowName(show)][number]; ok {
+		call.infof("Episode is mapped, searching %v instead of %v", mapped, number)
+		number = mapped
+	}
+
+	found, err := call.cachedShow(c.episodeURL(show, number), fetchEpisode(show, number))
+	if err != nil {
+		return found, err
+	}
+	found.Subtitles = found.Subtitles.Filter(WithLanguage(lang))
+	if len(found.Subtitles) == 0 {
+		return found, newError(CodeNoSubtitlesForLanguage, nil, "Unable to find any subtitles for show %q in %q. Check available languages on Addic7ed website and retry", found.Name, lang)
+	}
+	return found, nil
+}
+
+// fetchEpisode returns the function fetching the page of an episode, with all its subtitles
+func fetchEpisode(show string, number EpisodeNumber) func(c *call) (Show, error) {
+	return func(c *call) (Show, error) {
+		doc, err := c.createDocFromURL(c.episodeURL(show, number))
+		if err != nil {
+			return Show{}, err
+		}
+		name, err := c.findShowName(doc)
+		if err != nil {
+			return Show{}, newError(CodeShowNotFound, err, "episode %v of show %v not found", number, show)
+		}
+		return c.showFromPage(name, doc)
+	}
+}
+
+// episodeURL returns the URL of the page of an episode, listing subtitles in all languages
+func (c *Client) episodeURL(show string, number EpisodeNumber) string {
+	return c.url(fmt.Sprintf("serie/%v/%v/%v/0", url.PathEscape(strings.ReplaceAll(show, " ", "_")), number.Season, number.Episode))
+}
+
+var (
+	// episodeNameRegexp matches the names of the episodes of Addic7ed, like "Shameless (US) - 08x11 - A Gallagher Pedicure"
+	episodeNameRegexp = regexp.MustCompile(`^.+ - (\d{1,2})x(\d{1,3}) - (.*)$`)
+	// episodePathRegexp matches the paths of the pages of episodes, like "/serie/Shameless_(US)/8/11/A_Gallagher_Pedicure"
+	episodePathRegexp = regexp.MustCompile(`^/serie/[^/]+/(\d+)/(\d+)/([^/]*)$`)
+	// languageIDRegexp matches the IDs of languages ending the paths of the pages of episodes, like "/serie/Shameless_(US)/8/11/0"
+	languageIDRegexp = regexp.MustCompile(`^\d*$`)
+)
+
+// parseEpisodeName parses the number and the title of an episode from its name, or from the URL of its page when the
+// name isn't like "Show - 08x11 - Title". It returns empty values when neither gives them.
+func parseEpisodeName(name, page string) (EpisodeNumber, string) {
+	if m := episodeNameRegexp.FindStringSubmatch(strings.TrimSpace(name)); m != nil {
+		season, _ := strconv.Atoi(m[1])
+		episode, _ := strconv.Atoi(m[2])
+		return EpisodeNumber{Season: season, Episode: episode}, strings.TrimSpace(m[3])
+	}
+	u, err := url.Parse(page)
+	if err != nil {
+		return EpisodeNumber{}, ""
+	}
+	m := episodePathRegexp.FindStringSubmatch(u.EscapedPath())
+	if m == nil {
+		return EpisodeNumber{}, ""
+	}
+	season, _ := strconv.Atoi(m[1])
+	episode, _ := strconv.Atoi(m[2])
+	number := EpisodeNumber{Season: season, Episode: episode}
+	// Pages of episodes in a language, like the ones of SearchEpisode, end with the ID of the language instead of the title
+	if languageIDRegexp.MatchString(m[3]) {
+		return number, ""
+	}
+	title, err := url.PathUnescape(m[3])
+	if err != nil {
+		return number, ""
+	}
+	return number, strings.ReplaceAll(title, "_", " ")
+}
@@ -0,0 +1,162 @@
+package addic7ed
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorCode identifies the kind of an error returned by the package.
+// Codes are stable and can be used by frontends to present errors in the language of the user, see Localize
+type ErrorCode string
+
+const (
+	// CodeServerUnreachable is the code of errors happening while requesting Addic7ed website
+	CodeServerUnreachable ErrorCode = "server_unreachable"
+	// CodeParseFailure is the code of errors happening while reading pages of Addic7ed website
+	CodeParseFailure ErrorCode = "parse_failure"
+	// CodeShowNotFound is the code of errors returned when no show matches the search
+	CodeShowNotFound ErrorCode = "show_not_found"
+	// CodeNoSubtitlesYet is the code of ErrNoSubtitlesYet
+	CodeNoSubtitlesYet ErrorCode = "no_subtitles_yet"
+	// CodeNoSubtitlesForLanguage is the code of errors returned when a show does not have subtitles in the wanted language
+	CodeNoSubtitlesForLanguage ErrorCode = "no_subtitles_for_language"
+	// CodeIncompleteDownload is the code of ErrIncompleteDownload
+	CodeIncompleteDownload ErrorCode = "incomplete_download"
+	// CodeEmptySubtitle is the code of ErrEmptySubtitle
+	CodeEmptySubtitle ErrorCode = "empty_subtitle"
+	// CodeUnacceptableContent is the code of ErrUnacceptableContent
+	CodeUnacceptableContent ErrorCode = "unacceptable_content"
+	// CodeLoginFailed is the code of ErrLoginFailed
+	CodeLoginFailed ErrorCode = "login_failed"
+	// CodeDownloadLimitExceeded is the code of ErrDownloadLimitExceeded
+	CodeDownloadLimitExceeded ErrorCode = "download_limit_exceeded"
+	// CodeReadOnly is the code of ErrReadOnly
+	CodeReadOnly ErrorCode = "read_only"
+	// CodeInvalidSubtitle is the code of ErrInvalidSubtitle
+	CodeInvalidSubtitle ErrorCode = "invalid_subtitle"
+	// CodeEpisodeMismatch is the code of ErrEpisodeMismatch
+	CodeEpisodeMismatch ErrorCode = "episode_mismatch"
+)
+
+// Error is an error returned by the package, identified by a code
+type Error struct {
+	// Code identifies the kind of error
+	Code ErrorCode
+	// Message is the english description of the error
+	Message string
+	// Err is the underlying error, if any
+	Err error
+}
+
+func newError(code ErrorCode, err error, message string, params ...interface{}) *Error {
+	return &Error{
+		Code:    code,
+		Message: fmt.Sprintf(message, params...),
+		Err:     err,
+	}
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Unwrap returns the underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is checks whether the target is an error of the same kind, so that errors.Is(err, addic7ed.ErrNoSubtitlesYet) works
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// ErrorCodeOf returns the code of the given error, or an empty code if the error does not come from the package
+func ErrorCodeOf(err error) ErrorCode {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ""
+}
+
+// Localizer gives the localized message of an error of the package, usually from its code.
+// It returns an empty string when it does not know the error.
+type Localizer func(err *Error) string
+
+// Localize returns the message of the given error in the language of the localizer.
+// The original message is returned if the error does not come from the package or if the localizer does not know it
+func Localize(err error, localizer Localizer) string {
+	var e *Error
+	if errors.As(err, &e) {
+		if message := localizer(e); message != "" {
+			return message
+		}
+	}
+	return err.Error()
+}
+
+// StatusError is the underlying error of ErrServerUnreachable errors returned when Addic7ed answers with an unexpected HTTP status
+type StatusError struct {
+	// StatusCode is the HTTP status code of the response
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected HTTP status %v", e.StatusCode)
+}
+
+// checkStatus checks that Addic7ed answered with a successful HTTP status
+func checkStatus(statusCode int) error {
+	if statusCode < 200 || statusCode > 299 {
+		return newError(CodeServerUnreachable, &StatusError{StatusCode: statusCode}, "Addic7ed server answered with an error")
+	}
+	return nil
+}
+
+// Errors returned by the package can be compared with these values using errors.Is, as errors of the same code match.
+// Errors usually carry more details in their message and their underlying error.
+var (
+	// ErrServerUnreachable is returned when Addic7ed can't be reached or answers with an error. It is worth retrying later
+	ErrServerUnreachable = &Error{Code: CodeServerUnreachable, Message: "Unable to reach addic7ed server"}
+	// ErrParseFailure is returned when a page or a file served by Addic7ed can't be read, usually because the website has changed
+	ErrParseFailure = &Error{Code: CodeParseFailure, Message: "Unable to read Addic7ed page"}
+	// ErrShowNotFound is returned when no show matches the search
+	ErrShowNotFound = &Error{Code: CodeShowNotFound, Message: "show not found"}
+	// ErrNoSubtitlesForLanguage is returned when a show does not have any subtitle in the wanted language
+	ErrNoSubtitlesForLanguage = &Error{Code: CodeNoSubtitlesForLanguage, Message: "no subtitles for language"}
+)
+
+// ErrNoSubtitlesYet is returned when the page of an episode exists but does not have any subtitle yet.
+// Subtitles are usually uploaded a few hours after the episode is aired, so it is worth retrying later
+var ErrNoSubtitlesYet = &Error{Code: CodeNoSubtitlesYet, Message: "no subtitles yet for this episode"}
+
+// ErrReadOnly is returned when trying to download a subtitle with a client in read-only mode
+var ErrReadOnly = &Error{Code: CodeReadOnly, Message: "client is in read-only mode, downloads are disabled"}
+
+// ErrIncompleteDownload is returned when the download of a subtitle stopped before the end of the file
+var ErrIncompleteDownload = &Error{Code: CodeIncompleteDownload, Message: "subtitle download is incomplete"}
+
+// ErrEmptySubtitle is returned when a downloaded subtitle is empty or only contains a placeholder text like "Subtitle not available"
+var ErrEmptySubtitle = &Error{Code: CodeEmptySubtitle, Message: "subtitle is empty or not available"}
+
+// ErrUnacceptableContent is returned when a downloaded file is not of a MIME type or a format accepted by the client
+var ErrUnacceptableContent = &Error{Code: CodeUnacceptableContent, Message: "downloaded file is not accepted"}
+
+// ErrLoginFailed is returned when Addic7ed refuses the credentials of a user
+var ErrLoginFailed = &Error{Code: CodeLoginFailed, Message: "Unable to log in to Addic7ed"}
+
+// ErrDownloadLimitExceeded is returned when the daily download limit of Addic7ed is exceeded.
+// The underlying error is a *DownloadLimitError telling when downloads are allowed again.
+var ErrDownloadLimitExceeded = &Error{Code: CodeDownloadLimitExceeded, Message: "daily download limit exceeded"}
+
+// ErrInvalidSubtitle is returned when a downloaded file is not a subtitle of a known format, like an HTML error page.
+// The underlying error is an *InvalidSubtitleError capturing the served page.
+var ErrInvalidSubtitle = &Error{Code: CodeInvalidSubtitle, Message: "downloaded file is not a subtitle"}
+
+// ErrEpisodeMismatch is returned when the episode found by a search is not the episode of the filename, like S03E06 for S03E07.
+// The underlying error is an *EpisodeMismatchError telling both episodes, see WithEpisodeMismatchWarnings.
+var ErrEpisodeMismatch = &Error{Code: CodeEpisodeMismatch, Message: "found episode is not the searched one"}
@@ -0,0 +1,54 @@
+package addic7ed_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+func TestErrorCodes(t *testing.T) {
+	err := fmt.Errorf("checking show: %w", addic7ed.ErrNoSubtitlesYet)
+	assert.True(t, errors.Is(err, addic7ed.ErrNoSubtitlesYet))
+	assert.False(t, errors.Is(err, addic7ed.ErrReadOnly))
+	assert.Equal(t, addic7ed.CodeNoSubtitlesYet, addic7ed.ErrorCodeOf(err))
+	assert.Equal(t, addic7ed.ErrorCode(""), addic7ed.ErrorCodeOf(errors.New("unknown")))
+}
+
+func TestLocalize(t *testing.T) {
+	french := func(err *addic7ed.Error) string {
+		if err.Code == addic7ed.CodeReadOnly {
+			return "Les téléchargements sont désactivés"
+		}
+		return ""
+	}
+	assert.Equal(t, "Les téléchargements sont désactivés", addic7ed.Localize(addic7ed.ErrReadOnly, french))
+	assert.Equal(t, addic7ed.ErrNoSubtitlesYet.Error(), addic7ed.Localize(addic7ed.ErrNoSubtitlesYet, french))
+	assert.Equal(t, "unknown", addic7ed.Localize(errors.New("unknown"), french))
+}
+
+func TestDownloadWithServerError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := addic7ed.Subtitle{Link: server.URL}.Download()
+	assert.True(t, errors.Is(err, addic7ed.ErrServerUnreachable), "unexpected error %v", err)
+	assert.False(t, errors.Is(err, addic7ed.ErrShowNotFound))
+	var statusErr *addic7ed.StatusError
+	if assert.True(t, errors.As(err, &statusErr)) {
+		assert.Equal(t, http.StatusServiceUnavailable, statusErr.StatusCode)
+	}
+}
+
+func TestSearchWithUnreachableServer(t *testing.T) {
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: failingTransport{}}), addic7ed.WithoutRetry())
+	_, _, err := c.SearchBest("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", "English")
+	assert.True(t, errors.Is(err, addic7ed.ErrServerUnreachable), "unexpected error %v", err)
+}
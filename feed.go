@@ -0,0 +1,130 @@
+package addic7ed
+
+import (
+	"context"
+	"encoding/xml"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// feedTitleRegexp matches the titles of the items of the feeds of Addic7ed, like "Shameless (US) - 08x12 - Church of Gay Jesus AVS French"
+var feedTitleRegexp = regexp.MustCompile(`^(.+?) - (\d{1,2})x(\d{2,3}) - (.*)$`)
+
+// RecentSubtitle is a subtitle recently added on Addic7ed, as listed in its feeds
+type RecentSubtitle struct {
+	// Show is the name of the show, like "Shameless (US)"
+	Show string
+	// Number is the number of the episode
+	Number EpisodeNumber
+	// Title is the title of the episode and the version of the subtitle, as given by the feed
+	Title string
+	// Version is the version of the subtitle, if given by the feed
+	Version string
+	// Language is the Addic7ed language of the subtitle
+	Language string
+	// Link is the link to the page of the episode
+	Link string
+	// PublishedAt is the date the subtitle was added, or the zero time if unknown
+	PublishedAt time.Time
+}
+
+// rss is the RSS document of a feed
+type rss struct {
+	Items []struct {
+		Title       string `xml:"title"`
+		Link        string `xml:"link"`
+		Description string `xml:"description"`
+		PubDate     string `xml:"pubDate"`
+	} `xml:"channel>item"`
+}
+
+// RecentSubtitles gets the subtitles recently added on Addic7ed in a given language, from the feed of new versions.
+// Poll it to watch for new subtitles of tracked shows, without fetching the page of every episode.
+// An empty language gets the subtitles in all languages. Subtitles are in the order of the feed, usually the most recent first.
+func (c *Client) RecentSubtitles(lang string, opts ...CallOption) ([]RecentSubtitle, error) {
+	return c.RecentSubtitlesContext(context.Background(), lang, opts...)
+}
+
+// RecentSubtitlesContext is like RecentSubtitles, with a context to cancel the request
+func (c *Client) RecentSubtitlesContext(ctx context.Context, lang string, opts ...CallOption) ([]RecentSubtitle, error) {
+	call := c.newCall(ctx, opts)
+	feed, err := call.fetchFeed()
+	if err != nil {
+		return nil, err
+	}
+	recent := []RecentSubtitle{}
+	call.parseFeed(feed, lang, func(subtitle RecentSubtitle) bool {
+		recent = append(recent, subtitle)
+		return true
+	})
+	return recent, nil
+}
+
+// fetchFeed fetches the feed of new versions of Addic7ed
+func (c *call) fetchFeed() (rss, error) {
+	resp, err := c.get(c.url("rss.php?mode=versions"), nil)
+	if err != nil {
+		return rss{}, err
+	}
+	defer resp.Body.Close()
+
+	var feed rss
+	if err := xml.NewDecoder(resp.Body).Decode(&feed); err != nil {
+		return rss{}, newError(CodeParseFailure, err, "Unable to read the feed of Addic7ed")
+	}
+	return feed, nil
+}
+
+// parseFeed parses the items of a feed about subtitles in a language, or in all languages if empty, until yield returns false
+func (c *call) parseFeed(feed rss, lang string, yield func(RecentSubtitle) bool) {
+	for _, item := range feed.Items {
+		subtitle, ok := parseFeedItem(item.Title, item.Description)
+		if !ok {
+			c.tracef("Ignoring item %v of the feed", item.Title)
+			continue
+		}
+		if lang != "" && !sameLanguage(subtitle.Language, lang) {
+			continue
+		}
+		subtitle.Link = c.url(strings.TrimSpace(item.Link))
+		if published, err := time.Parse(time.RFC1123Z, strings.TrimSpace(item.PubDate)); err == nil {
+			subtitle.PublishedAt = published.UTC()
+		}
+		if !yield(subtitle) {
+			return
+		}
+	}
+}
+
+// parseFeedItem parses the title and the description of an item of a feed, like "Shameless (US) - 08x12 - Church of Gay Jesus AVS French"
+// and "Version AVS, French, uploaded by elderman". It returns false if the item is not about a subtitle
+func parseFeedItem(title, description string) (RecentSubtitle, bool) {
+	m := feedTitleRegexp.FindStringSubmatch(strings.TrimSpace(title))
+	if m == nil {
+		return RecentSubtitle{}, false
+	}
+	season, _ := strconv.Atoi(m[2])
+	episode, _ := strconv.Atoi(m[3])
+	subtitle := RecentSubtitle{
+		Show:   strings.TrimSpace(m[1]),
+		Number: EpisodeNumber{Season: season, Episode: episode},
+		Title:  strings.TrimSpace(m[4]),
+	}
+	// The description gives the version and the language, separated by commas
+	if parts := strings.Split(description, ","); len(parts) >= 2 && strings.HasPrefix(strings.TrimSpace(parts[0]), "Version ") {
+		subtitle.Version = CleanVersion(parts[0])
+		subtitle.Language = strings.TrimSpace(parts[1])
+		return subtitle, subtitle.Language != ""
+	}
+	// Without description, the language ends the title
+	for name := range languageCodes {
+		start := len(subtitle.Title) - len(name)
+		if start > 0 && subtitle.Title[start-1] == ' ' && strings.EqualFold(subtitle.Title[start:], name) {
+			subtitle.Language = subtitle.Title[start:]
+			return subtitle, true
+		}
+	}
+	return subtitle, false
+}
@@ -0,0 +1,74 @@
+package addic7ed_test
+
+import (
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+func TestRecentSubtitles(t *testing.T) {
+	feed, err := os.ReadFile("testdata/rss.xml")
+	assert.NoError(t, err)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rss.php" && r.URL.Query().Get("mode") == "versions" {
+			w.Write(feed)
+			return
+		}
+		http.NotFound(w, r)
+	})
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{handler}}))
+
+	recent, err := c.RecentSubtitles("English")
+	assert.NoError(t, err)
+	assert.Equal(t, []addic7ed.RecentSubtitle{{
+		Show:        "Shameless (US)",
+		Number:      addic7ed.EpisodeNumber{Season: 8, Episode: 12},
+		Title:       "Church of Gay Jesus AVS English",
+		Version:     "AVS",
+		Language:    "English",
+		Link:        "https://www.addic7ed.com/serie/Shameless_(US)/8/12/Church_of_Gay_Jesus",
+		PublishedAt: time.Date(2018, 4, 2, 20, 41, 52, 0, time.UTC),
+	}}, recent)
+
+	recent, err = c.RecentSubtitles("")
+	assert.NoError(t, err)
+	assert.Len(t, recent, 4)
+	assert.Equal(t, "Portuguese (Brazilian)", recent[2].Language)
+	// Without description, the language is found at the end of the title
+	assert.Equal(t, "Spanish (Latin America)", recent[3].Language)
+	assert.Equal(t, "", recent[3].Version)
+	assert.Equal(t, 2, c.Usage().Searches)
+}
+
+func TestRecentSubtitlesSeq(t *testing.T) {
+	feed, err := os.ReadFile("testdata/rss.xml")
+	assert.NoError(t, err)
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(feed)
+	})}}))
+	expected, err := c.RecentSubtitles("")
+	assert.NoError(t, err)
+
+	recent, err := c.RecentSubtitlesSeq("")
+	assert.NoError(t, err)
+	var all []addic7ed.RecentSubtitle
+	for s := range recent {
+		all = append(all, s)
+	}
+	assert.Equal(t, expected, all)
+
+	// Pollers stop at the first subtitle already seen
+	var next []addic7ed.RecentSubtitle
+	for s := range recent {
+		if s == expected[2] {
+			break
+		}
+		next = append(next, s)
+	}
+	assert.Equal(t, expected[:2], next)
+}
@@ -6,16 +6,26 @@ import (
 )
 
 // WithLanguage is a filter first-class function, used to keep subtitle with given language
+// The language is given by name, like "French", or by ISO 639-1 code, like "fr" or "pt-BR", see ParseLanguage
 func WithLanguage(lang string) func(s Subtitle) bool {
 	return func(s Subtitle) bool {
-		return strings.EqualFold(strings.TrimSpace(s.Language), strings.TrimSpace(lang))
+		return sameLanguage(s.Language, lang)
 	}
 }
 
 // WithVersion is a filter first-class function, used to keep subtitle with given subtitle version
+// Versions are compared regardless of case, separators and order of tags, see Version.Equal
 func WithVersion(version string) func(s Subtitle) bool {
+	wanted := ParseVersion(version)
 	return func(s Subtitle) bool {
-		return strings.EqualFold(strings.TrimSpace(s.Version), strings.TrimSpace(version))
+		return s.parsedVersion().Equal(wanted)
+	}
+}
+
+// WithGroup is a filter first-class function, used to keep subtitle ripped by the given team, like "NTb"
+func WithGroup(group string) func(s Subtitle) bool {
+	return func(s Subtitle) bool {
+		return strings.EqualFold(s.parsedVersion().Group, strings.TrimSpace(group))
 	}
 }
 
@@ -25,3 +35,54 @@ func WithVersionRegexp(version *regexp.Regexp) func(s Subtitle) bool {
 		return version.MatchString(strings.TrimSpace(s.Version))
 	}
 }
+
+// WithHearingImpaired is a filter first-class function, used to keep subtitle for hearing impaired people
+// Use Not(WithHearingImpaired()) to keep the other ones
+func WithHearingImpaired() func(s Subtitle) bool {
+	return func(s Subtitle) bool {
+		return s.HearingImpaired
+	}
+}
+
+// WithCompleted is a filter first-class function, used to keep subtitle whose translation is completed
+func WithCompleted() func(s Subtitle) bool {
+	return Subtitle.IsCompleted
+}
+
+// WithMinDownloads is a filter first-class function, used to keep subtitle downloaded at least the given number of times
+func WithMinDownloads(downloads int) func(s Subtitle) bool {
+	return func(s Subtitle) bool {
+		return s.Downloads >= downloads
+	}
+}
+
+// And combines filters, to keep subtitle kept by all filters
+func And(filters ...func(s Subtitle) bool) func(s Subtitle) bool {
+	return func(s Subtitle) bool {
+		for _, filter := range filters {
+			if !filter(s) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or combines filters, to keep subtitle kept by at least one filter
+func Or(filters ...func(s Subtitle) bool) func(s Subtitle) bool {
+	return func(s Subtitle) bool {
+		for _, filter := range filters {
+			if filter(s) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not inverts a filter, to keep subtitle ignored by the filter
+func Not(filter func(s Subtitle) bool) func(s Subtitle) bool {
+	return func(s Subtitle) bool {
+		return !filter(s)
+	}
+}
@@ -0,0 +1,69 @@
+package addic7ed
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+// Format is the format of a subtitle file
+type Format string
+
+const (
+	// FormatUnknown is the format of files that are not recognized as subtitles
+	FormatUnknown Format = ""
+	// FormatSRT is the SubRip format, the most common one on Addic7ed
+	FormatSRT Format = "srt"
+	// FormatVTT is the WebVTT format
+	FormatVTT Format = "vtt"
+	// FormatASS is the Advanced SubStation Alpha format
+	FormatASS Format = "ass"
+	// FormatSSA is the SubStation Alpha format
+	FormatSSA Format = "ssa"
+	// FormatSUB is the MicroDVD format
+	FormatSUB Format = "sub"
+)
+
+var (
+	utf8BOM            = []byte("\xef\xbb\xbf")
+	srtTimingRegexp    = regexp.MustCompile(`^\d{1,2}:\d{2}:\d{2}[,.]\d{1,3}\s*-->\s*\d{1,2}:\d{2}:\d{2}[,.]\d{1,3}`)
+	microDVDLineRegexp = regexp.MustCompile(`^\{\d+\}\{\d*\}`)
+)
+
+// Extension returns the file extension of the format, like ".srt", or an empty string for unknown formats
+func (f Format) Extension() string {
+	if f == FormatUnknown {
+		return ""
+	}
+	return "." + string(f)
+}
+
+// DetectFormat detects the format of a subtitle from its content
+func DetectFormat(data []byte) Format {
+	data = bytes.TrimPrefix(data, utf8BOM)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	var lines []string
+	for scanner.Scan() && len(lines) < 3 {
+		if line := trimSpaceAndBidi(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return FormatUnknown
+	}
+	switch {
+	case strings.HasPrefix(lines[0], "WEBVTT"):
+		return FormatVTT
+	case strings.EqualFold(lines[0], "[Script Info]"):
+		if bytes.Contains(data, []byte("[V4+ Styles]")) || bytes.Contains(bytes.ToLower(data), []byte("scripttype: v4.00+")) {
+			return FormatASS
+		}
+		return FormatSSA
+	case microDVDLineRegexp.MatchString(lines[0]):
+		return FormatSUB
+	case isNumber(lines[0]) && len(lines) > 1 && srtTimingRegexp.MatchString(lines[1]):
+		return FormatSRT
+	}
+	return FormatUnknown
+}
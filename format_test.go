@@ -0,0 +1,31 @@
+package addic7ed_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+func TestDetectFormat(t *testing.T) {
+	var formattests = []struct {
+		in       string
+		expected addic7ed.Format
+	}{
+		{"1\n00:00:01,000 --> 00:00:02,000\nHello\n", addic7ed.FormatSRT},
+		{"\xef\xbb\xbf\r\n1\r\n00:00:01,000 --> 00:00:02,000\r\nHello\r\n", addic7ed.FormatSRT},
+		{"WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nHello\n", addic7ed.FormatVTT},
+		{"[Script Info]\nScriptType: v4.00+\n\n[V4+ Styles]\n", addic7ed.FormatASS},
+		{"[Script Info]\nScriptType: v4.00\n\n[V4 Styles]\n", addic7ed.FormatSSA},
+		{"{1025}{1110}Hello\n", addic7ed.FormatSUB},
+		{"<html><body>Daily download count exceeded</body></html>", addic7ed.FormatUnknown},
+		{"", addic7ed.FormatUnknown},
+	}
+
+	for _, test := range formattests {
+		assert.Equal(t, test.expected, addic7ed.DetectFormat([]byte(test.in)), test.in)
+	}
+	assert.Equal(t, ".srt", addic7ed.FormatSRT.Extension())
+	assert.Equal(t, "", addic7ed.FormatUnknown.Extension())
+}
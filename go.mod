@@ -1,10 +1,21 @@
 module github.com/matcornic/addic7ed
 
-go 1.13
+go 1.23
 
 require (
 	github.com/PuerkitoBio/goquery v1.5.0
-	github.com/deckarep/golang-set v1.7.1 // indirect
+	github.com/fsnotify/fsnotify v1.10.1
 	github.com/masatana/go-textdistance v0.0.0-20191005053614-738b0edac985
+	github.com/nwaples/rardecode/v2 v2.4.1
 	github.com/stretchr/testify v1.4.0
 )
+
+require (
+	github.com/andybalholm/cascadia v1.0.0 // indirect
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/deckarep/golang-set v1.7.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/net v0.0.0-20181114220301-adae6a3d119a // indirect
+	golang.org/x/sys v0.13.0 // indirect
+	gopkg.in/yaml.v2 v2.2.2 // indirect
+)
@@ -0,0 +1,96 @@
+package addic7ed
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// hashChunkSize is the size of the chunks of the beginning and the end of a video that are hashed
+const hashChunkSize = 64 * 1024
+
+// VideoHash is the OpenSubtitles hash of a video file, with its size. Unlike names, it identifies a release exactly,
+// so that providers supporting hashes, like OpenSubtitles, find the subtitles synchronized with the video, see HashSearcher
+type VideoHash struct {
+	// Hash is the sum of the size of the file and of the 64-bit words of its first and last 64 KiB
+	Hash uint64
+	// Size is the size of the file in bytes
+	Size int64
+}
+
+// String returns the hash in hexadecimal, as sent to the providers, like "8e245d9679d31e12"
+func (h VideoHash) String() string {
+	return fmt.Sprintf("%016x", h.Hash)
+}
+
+// FileHash computes the OpenSubtitles hash of a video file, see VideoHash
+func FileHash(path string) (VideoHash, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return VideoHash{}, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return VideoHash{}, err
+	}
+	return ComputeHash(f, info.Size())
+}
+
+// ComputeHash computes the OpenSubtitles hash of a video of a given size, see VideoHash.
+// Only the first and last 64 KiB are read, so videos of any size are hashed in an instant. Videos must be at least 128 KiB.
+func ComputeHash(r io.ReaderAt, size int64) (VideoHash, error) {
+	if size < 2*hashChunkSize {
+		return VideoHash{}, fmt.Errorf("video of %v bytes is too small to be hashed, at least %v bytes are needed", size, 2*hashChunkSize)
+	}
+	hash := uint64(size)
+	chunk := make([]byte, hashChunkSize)
+	for _, offset := range []int64{0, size - hashChunkSize} {
+		if _, err := r.ReadAt(chunk, offset); err != nil {
+			return VideoHash{}, err
+		}
+		for i := 0; i < hashChunkSize; i += 8 {
+			hash += binary.LittleEndian.Uint64(chunk[i:])
+		}
+	}
+	return VideoHash{Hash: hash, Size: size}, nil
+}
+
+// HashSearcher is implemented by the providers able to search subtitles by the hash of the video, like OpenSubtitles.
+// Addic7ed doesn't know the hashes of the videos, so a Client doesn't implement it.
+type HashSearcher interface {
+	// SearchHash searches the subtitles of a video in a language by its hash, like Provider.Search
+	SearchHash(ctx context.Context, hash VideoHash, lang string) (string, Subtitles, error)
+}
+
+// SearchHash searches the subtitles of a video by its hash on each provider supporting hashes in order, and returns those
+// of the first one that has any, see HashSearcher
+func (m MultiProvider) SearchHash(ctx context.Context, hash VideoHash, lang string) (string, Subtitles, error) {
+	var errs []error
+	for _, provider := range m {
+		searcher, ok := provider.(HashSearcher)
+		if !ok {
+			continue
+		}
+		name, subtitles, err := searcher.SearchHash(ctx, hash, lang)
+		if err == nil && len(subtitles) > 0 {
+			for i := range subtitles {
+				if subtitles[i].provider == nil {
+					subtitles[i].provider = provider
+				}
+			}
+			return name, subtitles, nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", nil, ctxErr
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	errs = append(errs, newError(CodeNoSubtitlesForLanguage, nil, "Unable to find any subtitles for hash %v in %q", hash, lang))
+	return "", nil, errors.Join(errs...)
+}
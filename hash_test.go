@@ -0,0 +1,85 @@
+package addic7ed_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+func TestFileHash(t *testing.T) {
+	dir := t.TempDir()
+	video := make([]byte, 300*1024)
+	video[0] = 1
+	video[150*1024] = 0xff // Only the first and last 64 KiB are hashed
+	video[len(video)-8] = 2
+	path := filepath.Join(dir, "Show.S01E01.mkv")
+	assert.NoError(t, os.WriteFile(path, video, 0644))
+
+	hash, err := addic7ed.FileHash(path)
+	assert.NoError(t, err)
+	assert.Equal(t, addic7ed.VideoHash{Hash: 300*1024 + 3, Size: 300 * 1024}, hash)
+	assert.Equal(t, "000000000004b003", hash.String())
+
+	hash, err = addic7ed.ComputeHash(bytes.NewReader(make([]byte, 128*1024)), 128*1024)
+	assert.NoError(t, err)
+	assert.Equal(t, "0000000000020000", hash.String())
+
+	_, err = addic7ed.ComputeHash(bytes.NewReader(make([]byte, 1024)), 1024)
+	assert.Error(t, err)
+	_, err = addic7ed.FileHash(filepath.Join(dir, "missing.mkv"))
+	assert.Error(t, err)
+}
+
+// hashProvider is a provider also finding subtitles by hash
+type hashProvider struct {
+	fakeProvider
+	hashes map[addic7ed.VideoHash]addic7ed.Subtitles
+}
+
+func (p *hashProvider) SearchHash(ctx context.Context, hash addic7ed.VideoHash, lang string) (string, addic7ed.Subtitles, error) {
+	subtitles, ok := p.hashes[hash]
+	if !ok {
+		return "", nil, addic7ed.ErrShowNotFound
+	}
+	return "Other Show - 01x01", subtitles.Filter(addic7ed.WithLanguage(lang)), nil
+}
+
+func TestSearchBestFromWithHash(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Other.Show.S01E01.720p.HDTV.x264-KILLERS.mkv")
+	assert.NoError(t, os.WriteFile(path, make([]byte, 128*1024), 0644))
+	hash, err := addic7ed.FileHash(path)
+	assert.NoError(t, err)
+
+	provider := &hashProvider{
+		fakeProvider: fakeProvider{content: "other", shows: map[string]addic7ed.Subtitles{
+			"Other.Show.S01E01": {{Language: "English", Version: "KILLERS", Link: "other://1"}},
+		}},
+		hashes: map[addic7ed.VideoHash]addic7ed.Subtitles{
+			hash: {{Language: "English", Version: "LOL", Link: "other://2"}},
+		},
+	}
+	c := addic7ed.New()
+
+	// The subtitle matching the hash wins over the one matching the name, also through a MultiProvider
+	for _, p := range []addic7ed.Provider{provider, addic7ed.MultiProvider{provider}} {
+		name, best, err := c.SearchBestFrom(p, path, "English")
+		assert.NoError(t, err)
+		assert.Equal(t, "Other Show - 01x01", name)
+		assert.Equal(t, "LOL", best.Version)
+		assert.True(t, best.MatchedByHash)
+	}
+
+	// Searches fall back on names when no subtitle matches the hash
+	delete(provider.hashes, hash)
+	_, best, err := c.SearchBestFrom(provider, path, "English")
+	assert.NoError(t, err)
+	assert.Equal(t, "KILLERS", best.Version)
+	assert.False(t, best.MatchedByHash)
+}
@@ -0,0 +1,130 @@
+package addic7ed
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// Index is an in-memory inverted index of the text of subtitles, to find which episode says what.
+// It is safe for concurrent use.
+type Index struct {
+	mu       sync.RWMutex
+	cues     []indexedCue
+	postings map[string][]int // postings are the indexes of the cues containing each word, in order
+}
+
+// indexedCue is a cue of an indexed subtitle
+type indexedCue struct {
+	name string
+	cue
+}
+
+// TextMatch is a cue of an indexed subtitle matching a search
+type TextMatch struct {
+	// Name is the name given to the subtitle when indexed, usually its path or the name of the episode
+	Name string
+	// Start and End are the timings of the cue
+	Start time.Duration
+	End   time.Duration
+	// Text is the text of the cue, lines being separated by "\n"
+	Text string
+}
+
+// NewIndex creates an empty index of subtitles
+func NewIndex() *Index {
+	return &Index{postings: map[string][]int{}}
+}
+
+// Add indexes the cues of a SRT subtitle under the given name
+func (idx *Index) Add(name string, r io.Reader) error {
+	cues, err := parseSRT(r)
+	if err != nil {
+		return err
+	}
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	for _, c := range cues {
+		i := len(idx.cues)
+		idx.cues = append(idx.cues, indexedCue{name: name, cue: c})
+		seen := map[string]bool{}
+		for _, token := range textTokens(strings.Join(c.lines, " ")) {
+			if !seen[token] {
+				seen[token] = true
+				idx.postings[token] = append(idx.postings[token], i)
+			}
+		}
+	}
+	return nil
+}
+
+// AddDir indexes all SRT subtitles of a directory and its subdirectories, named by their path
+func (idx *Index) AddDir(dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.EqualFold(filepath.Ext(path), FormatSRT.Extension()) {
+			return err
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return idx.Add(path, f)
+	})
+}
+
+// Search finds the cues containing all the words of the query, regardless of case and punctuation.
+// Matches are returned in the order subtitles were indexed.
+func (idx *Index) Search(query string) []TextMatch {
+	tokens := textTokens(query)
+	if len(tokens) == 0 {
+		return nil
+	}
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	found := idx.postings[tokens[0]]
+	for _, token := range tokens[1:] {
+		found = intersect(found, idx.postings[token])
+	}
+	matches := make([]TextMatch, 0, len(found))
+	for _, i := range found {
+		c := idx.cues[i]
+		matches = append(matches, TextMatch{Name: c.name, Start: c.start, End: c.end, Text: strings.Join(c.lines, "\n")})
+	}
+	return matches
+}
+
+// intersect returns the values of two sorted lists found in both
+func intersect(a, b []int) []int {
+	var both []int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			both = append(both, a[i])
+			i++
+			j++
+		}
+	}
+	return both
+}
+
+// markupRegexp matches the markup of subtitles, like <i> tags or {\an8} styles
+var markupRegexp = regexp.MustCompile(`<[^>]*>|\{\\[^}]*\}`)
+
+// textTokens splits the text of a subtitle in lowered words, without markup and punctuation
+func textTokens(text string) []string {
+	text = markupRegexp.ReplaceAllString(text, " ")
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
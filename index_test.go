@@ -0,0 +1,46 @@
+package addic7ed_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+func TestIndexSearch(t *testing.T) {
+	idx := addic7ed.NewIndex()
+	assert.NoError(t, idx.Add("S08E11", strings.NewReader("1\n00:00:01,000 --> 00:00:02,500\n<i>Where's Frank?</i>\n\n2\n00:01:00,000 --> 00:01:02,000\nFrank is at the bar.\nAgain.\n")))
+	assert.NoError(t, idx.Add("S08E12", strings.NewReader("1\n00:00:03,000 --> 00:00:04,000\nNobody knows where FRANK is\n")))
+
+	matches := idx.Search("where is frank")
+	assert.Len(t, matches, 1)
+	assert.Equal(t, addic7ed.TextMatch{Name: "S08E12", Start: 3 * time.Second, End: 4 * time.Second, Text: "Nobody knows where FRANK is"}, matches[0])
+
+	matches = idx.Search("Frank!")
+	if assert.Len(t, matches, 3) {
+		assert.Equal(t, "S08E11", matches[0].Name)
+		assert.Equal(t, "Frank is at the bar.\nAgain.", matches[1].Text)
+	}
+	assert.Empty(t, idx.Search("Fiona"))
+	assert.Empty(t, idx.Search("..."))
+}
+
+func TestIndexAddDir(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "Season 8"), 0o755))
+	path := filepath.Join(dir, "Season 8", "Shameless.S08E11.srt")
+	assert.NoError(t, os.WriteFile(path, []byte("1\n00:00:01,000 --> 00:00:02,000\nHello Fiona\n"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("Hello Fiona"), 0o644))
+
+	idx := addic7ed.NewIndex()
+	assert.NoError(t, idx.AddDir(dir))
+	matches := idx.Search("hello")
+	if assert.Len(t, matches, 1) {
+		assert.Equal(t, path, matches[0].Name)
+	}
+}
@@ -0,0 +1,96 @@
+package addic7ed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Ladder is an escalating schedule of retries: Ladder[i] is the delay before the retry i+1, the last delay being repeated
+type Ladder []time.Duration
+
+// DefaultLadder is the ladder used to wait for the subtitles of fresh episodes, see SearchBestWhenAvailable
+var DefaultLadder = Ladder{15 * time.Minute, time.Hour, 6 * time.Hour, 24 * time.Hour}
+
+// Delay returns the delay before a retry, counting retries from 0. An empty ladder has no delay
+func (l Ladder) Delay(retry int) time.Duration {
+	if len(l) == 0 {
+		return 0
+	}
+	return l[min(max(retry, 0), len(l)-1)]
+}
+
+// WithLadder sets the ladder used to wait for the subtitles of fresh episodes. New clients use DefaultLadder
+func WithLadder(ladder Ladder) Option {
+	return func(c *Client) {
+		c.ladder = ladder
+	}
+}
+
+// WithShowLadder sets the ladder used to wait for the subtitles of the episodes of a show, overriding the ladder of the client.
+// The show is identified by its name, regardless of case and separators, like WithEpisodeMapping
+func WithShowLadder(show string, ladder Ladder) Option {
+	return func(c *Client) {
+		if c.showLadders == nil {
+			c.showLadders = map[string]Ladder{}
+		}
+		c.showLadders[normalizeShowName(show)] = ladder
+	}
+}
+
+// checkLadder checks that the ladder of a search waits between retries, so that waiting searches don't flood the website
+func (c *Client) checkLadder(showStr string) error {
+	ladder, ok := c.showLadders[normalizeShowName(ParseRelease(showStr).Title)]
+	if !ok {
+		ladder = c.ladder
+	}
+	if len(ladder) == 0 {
+		return fmt.Errorf("the ladder of %v is empty", showStr)
+	}
+	for _, delay := range ladder {
+		if delay <= 0 {
+			return fmt.Errorf("the ladder of %v has a delay of %v, delays must be positive", showStr, delay)
+		}
+	}
+	return nil
+}
+
+// RetryDelay returns the delay before retrying a search whose subtitles are not available yet, counting retries from 0.
+// Use it to schedule searches from a job queue, see SearchBestWhenAvailable to wait in the current goroutine.
+func (c *Client) RetryDelay(showStr string, retry int) time.Duration {
+	if ladder, ok := c.showLadders[normalizeShowName(ParseRelease(showStr).Title)]; ok {
+		return ladder.Delay(retry)
+	}
+	return c.ladder.Delay(retry)
+}
+
+// SearchBestWhenAvailable searches the best subtitle of an episode like SearchBest, waiting for the subtitles of fresh episodes.
+// While the episode has no subtitle yet, or no subtitle in the given language, the search is retried following the ladder
+// of the show (see WithLadder and WithShowLadder), bypassing the cache, until the context is done. Other errors are returned
+// right away, and ladders that are empty or have delays of 0 are refused.
+func (c *Client) SearchBestWhenAvailable(ctx context.Context, showStr, lang string, opts ...CallOption) (string, Subtitle, error) {
+	if err := c.checkLadder(showStr); err != nil {
+		return "", Subtitle{}, err
+	}
+	for retry := 0; ; retry++ {
+		call := c.newCall(ctx, opts)
+		show, err := fetchSearch(showStr)(call)
+		err = call.checkFoundEpisode(showStr, &show, err)
+		var name string
+		var subtitle Subtitle
+		if err == nil {
+			name, subtitle, err = call.bestOfShow(showStr, lang, show)
+		}
+		if !errors.Is(err, ErrNoSubtitlesYet) && !errors.Is(err, ErrNoSubtitlesForLanguage) {
+			return name, subtitle, err
+		}
+		timer := time.NewTimer(c.RetryDelay(showStr, retry))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return "", Subtitle{}, errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package addic7ed_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+func TestLadderDelay(t *testing.T) {
+	ladder := addic7ed.Ladder{time.Minute, time.Hour}
+	assert.Equal(t, time.Minute, ladder.Delay(0))
+	assert.Equal(t, time.Hour, ladder.Delay(1))
+	assert.Equal(t, time.Hour, ladder.Delay(5))
+	assert.Equal(t, time.Duration(0), addic7ed.Ladder{}.Delay(0))
+}
+
+func TestRetryDelay(t *testing.T) {
+	c := addic7ed.New(addic7ed.WithShowLadder("Shameless (US)", addic7ed.Ladder{time.Minute}))
+	assert.Equal(t, time.Minute, c.RetryDelay("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", 3))
+	assert.Equal(t, addic7ed.DefaultLadder.Delay(3), c.RetryDelay("The.Big.Bang.Theory.S06E12", 3))
+}
+
+// withoutSubtitlesFirst serves an episode page without subtitles to the first searches
+func withoutSubtitlesFirst(handler http.Handler, searches int64) http.Handler {
+	var served int64
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/srch.php" && atomic.AddInt64(&served, 1) <= searches {
+			w.Write([]byte(`<span class="titulo">Shameless (US) - 08x11 - A Gallagher Pedicure <small>Subtitle</small></span>`))
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func TestSearchBestWhenAvailable(t *testing.T) {
+	server := httptest.NewServer(withoutSubtitlesFirst(episodeHandler(t, nil), 2))
+	defer server.Close()
+	c := addic7ed.New(addic7ed.WithBaseURL(server.URL), addic7ed.WithLadder(addic7ed.Ladder{time.Millisecond, 2 * time.Millisecond}))
+
+	_, subtitle, err := c.SearchBestWhenAvailable(context.Background(), "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", "English")
+	assert.NoError(t, err)
+	assert.Equal(t, "BATV", subtitle.Version)
+
+	// The context is done while waiting for the next retry
+	c = addic7ed.New(addic7ed.WithBaseURL(server.URL), addic7ed.WithLadder(addic7ed.Ladder{time.Hour}))
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, _, err = c.SearchBestWhenAvailable(ctx, "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", "Klingon")
+	assert.True(t, errors.Is(err, addic7ed.ErrNoSubtitlesForLanguage), "unexpected error %v", err)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "unexpected error %v", err)
+}
+
+func TestSearchBestWhenAvailableBypassesCache(t *testing.T) {
+	server := httptest.NewServer(withoutSubtitlesFirst(episodeHandler(t, nil), 1))
+	defer server.Close()
+	c := addic7ed.New(addic7ed.WithBaseURL(server.URL), addic7ed.WithCache(time.Hour, 0), addic7ed.WithLadder(addic7ed.Ladder{time.Millisecond}))
+
+	// The episode without subtitles is cached by the first search
+	_, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.True(t, errors.Is(err, addic7ed.ErrNoSubtitlesYet), "unexpected error %v", err)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, subtitle, err := c.SearchBestWhenAvailable(ctx, "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", "English")
+	assert.NoError(t, err)
+	assert.Equal(t, "BATV", subtitle.Version)
+}
+
+func TestSearchBestWhenAvailableRefusesLaddersWithoutDelay(t *testing.T) {
+	server := httptest.NewServer(episodeHandler(t, nil))
+	defer server.Close()
+	for _, ladder := range []addic7ed.Ladder{{}, {time.Minute, 0}} {
+		c := addic7ed.New(addic7ed.WithBaseURL(server.URL), addic7ed.WithLadder(ladder))
+		_, _, err := c.SearchBestWhenAvailable(context.Background(), "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", "Klingon")
+		assert.Error(t, err, "%v", ladder)
+		assert.False(t, errors.Is(err, addic7ed.ErrNoSubtitlesForLanguage), "%v", ladder)
+	}
+}
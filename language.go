@@ -0,0 +1,141 @@
+package addic7ed
+
+import (
+	"strings"
+	"unicode"
+)
+
+// languageCodes maps the names of the languages of Addic7ed to their ISO 639-1 codes, with a region when Addic7ed distinguishes one
+var languageCodes = map[string]string{
+	"albanian":                "sq",
+	"arabic":                  "ar",
+	"basque":                  "eu",
+	"bengali":                 "bn",
+	"bosnian":                 "bs",
+	"bulgarian":               "bg",
+	"catalan":                 "ca",
+	"chinese (simplified)":    "zh-CN",
+	"chinese (traditional)":   "zh-TW",
+	"croatian":                "hr",
+	"czech":                   "cs",
+	"danish":                  "da",
+	"dutch":                   "nl",
+	"english":                 "en",
+	"estonian":                "et",
+	"finnish":                 "fi",
+	"french":                  "fr",
+	"french (canadian)":       "fr-CA",
+	"galician":                "gl",
+	"german":                  "de",
+	"greek":                   "el",
+	"hebrew":                  "he",
+	"hindi":                   "hi",
+	"hungarian":               "hu",
+	"icelandic":               "is",
+	"indonesian":              "id",
+	"italian":                 "it",
+	"japanese":                "ja",
+	"korean":                  "ko",
+	"latvian":                 "lv",
+	"lithuanian":              "lt",
+	"macedonian":              "mk",
+	"malay":                   "ms",
+	"norwegian":               "no",
+	"persian":                 "fa",
+	"polish":                  "pl",
+	"portuguese":              "pt",
+	"portuguese (brazilian)":  "pt-BR",
+	"romanian":                "ro",
+	"russian":                 "ru",
+	"serbian (cyrillic)":      "sr",
+	"serbian (latin)":         "sr",
+	"slovak":                  "sk",
+	"slovenian":               "sl",
+	"spanish":                 "es",
+	"spanish (latin america)": "es-419",
+	"spanish (spain)":         "es",
+	"swedish":                 "sv",
+	"thai":                    "th",
+	"turkish":                 "tr",
+	"ukrainian":               "uk",
+	"vietnamese":              "vi",
+}
+
+// languageNames maps the lowered ISO 639-1 codes to the names of the languages of Addic7ed.
+// When Addic7ed has several languages for a code, like "Spanish" and "Spanish (Spain)", the shortest name is kept
+var languageNames = func() map[string]string {
+	names := map[string]string{}
+	for name, code := range languageCodes {
+		code = strings.ToLower(code)
+		if other, ok := names[code]; !ok || len(name) < len(other) || (len(name) == len(other) && name < other) {
+			names[code] = name
+		}
+	}
+	return names
+}()
+
+// Language is a language of Addic7ed, known both by its name on the website and by its ISO 639-1 code
+type Language struct {
+	// Name is the name of the language on Addic7ed, like "Portuguese (Brazilian)"
+	Name string
+	// Code is the ISO 639-1 code of the language, with a region when Addic7ed distinguishes one, like "pt-BR"
+	Code string
+}
+
+// ParseLanguage parses a language given by name, like "French", or by ISO 639-1 code, like "fr" or "pt-BR", regardless of case.
+// Unknown languages are kept as names, see Subtitle.LanguageCode
+func ParseLanguage(lang string) Language {
+	lang = strings.TrimSpace(lang)
+	if name, ok := languageNames[normalizedLanguageCode(lang)]; ok {
+		return Language{Name: languageTitle(name), Code: languageCodes[name]}
+	}
+	if code, ok := languageCodes[strings.ToLower(lang)]; ok {
+		return Language{Name: languageTitle(strings.ToLower(lang)), Code: code}
+	}
+	return Language{Name: lang, Code: languageCode(lang)}
+}
+
+func (l Language) String() string {
+	return l.Name
+}
+
+// LanguageCode returns the ISO 639-1 code of the language of the subtitle, like "en" for "English" or "pt-BR" for "Portuguese (Brazilian)"
+func (s Subtitle) LanguageCode() string {
+	return languageCode(s.Language)
+}
+
+// sameLanguage checks whether an Addic7ed language is lang, given by name or by ISO 639-1 code.
+// Codes match all the languages of the code, like "es" matching "Spanish" and "Spanish (Spain)", names only match themselves
+func sameLanguage(name, lang string) bool {
+	lang = strings.TrimSpace(lang)
+	if code := normalizedLanguageCode(lang); languageNames[code] != "" {
+		return strings.EqualFold(languageCode(name), code)
+	}
+	return strings.EqualFold(strings.TrimSpace(name), lang)
+}
+
+// normalizedLanguageCode lowers a language code, accepting underscores as separators like in "pt_BR"
+func normalizedLanguageCode(code string) string {
+	return strings.ReplaceAll(strings.ToLower(code), "_", "-")
+}
+
+// languageTitle capitalizes the words of a lowered language name, like Addic7ed does
+func languageTitle(name string) string {
+	runes := []rune(name)
+	for i, r := range runes {
+		if i == 0 || runes[i-1] == ' ' || runes[i-1] == '(' {
+			runes[i] = unicode.ToUpper(r)
+		}
+	}
+	return string(runes)
+}
+
+// languageCode returns the ISO 639-1 code of an Addic7ed language, like "en" for "English".
+// Unknown languages are lowercased without spaces, so that they still make a file name suffix.
+func languageCode(lang string) string {
+	name := strings.ToLower(strings.TrimSpace(lang))
+	if code, ok := languageCodes[name]; ok {
+		return code
+	}
+	return strings.Join(strings.Fields(name), "")
+}
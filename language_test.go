@@ -0,0 +1,89 @@
+package addic7ed_test
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+func TestParseLanguage(t *testing.T) {
+	var languagetests = []struct {
+		in       string
+		expected addic7ed.Language
+	}{
+		{"French", addic7ed.Language{Name: "French", Code: "fr"}},
+		{"fr", addic7ed.Language{Name: "French", Code: "fr"}},
+		{" FR ", addic7ed.Language{Name: "French", Code: "fr"}},
+		{"pt-BR", addic7ed.Language{Name: "Portuguese (Brazilian)", Code: "pt-BR"}},
+		{"pt_br", addic7ed.Language{Name: "Portuguese (Brazilian)", Code: "pt-BR"}},
+		{"portuguese (brazilian)", addic7ed.Language{Name: "Portuguese (Brazilian)", Code: "pt-BR"}},
+		{"es", addic7ed.Language{Name: "Spanish", Code: "es"}},
+		{"Klingon", addic7ed.Language{Name: "Klingon", Code: "klingon"}},
+	}
+	for _, test := range languagetests {
+		assert.Equal(t, test.expected, addic7ed.ParseLanguage(test.in), test.in)
+	}
+}
+
+func TestWithLanguageCode(t *testing.T) {
+	subtitles := addic7ed.Subtitles{
+		{Language: "French"},
+		{Language: "French (Canadian)"},
+		{Language: "Portuguese (Brazilian)"},
+		{Language: "Spanish"},
+		{Language: "Spanish (Spain)"},
+		{Language: "Spanish (Latin America)"},
+	}
+	assert.Equal(t, subtitles[:1], subtitles.Filter(addic7ed.WithLanguage("fr")))
+	assert.Equal(t, subtitles[:1], subtitles.Filter(addic7ed.WithLanguage("French")))
+	assert.Equal(t, subtitles[1:2], subtitles.Filter(addic7ed.WithLanguage("fr-CA")))
+	assert.Equal(t, subtitles[2:3], subtitles.Filter(addic7ed.WithLanguage("pt-BR")))
+	// Codes match all the languages of the code, names only match themselves
+	assert.Equal(t, subtitles[3:5], subtitles.Filter(addic7ed.WithLanguage("es")))
+	assert.Equal(t, subtitles[3:4], subtitles.Filter(addic7ed.WithLanguage("Spanish")))
+
+	assert.Equal(t, "pt-BR", subtitles[2].LanguageCode())
+	assert.Equal(t, "es-419", subtitles[5].LanguageCode())
+}
+
+func TestSearchBestWithLanguageCode(t *testing.T) {
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{episodeHandler(t, nil)}}))
+	_, subtitle, err := c.SearchBest("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", "fr")
+	assert.NoError(t, err)
+	assert.Equal(t, "French", subtitle.Language)
+}
+
+func TestSearchBestWithFallback(t *testing.T) {
+	var searches int64
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{countSearches(episodeHandler(t, nil), &searches)}}))
+	_, subtitle, err := c.SearchBestWithFallback("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", []string{"de", "fr", "en"})
+	assert.NoError(t, err)
+	assert.Equal(t, "French", subtitle.Language)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&searches))
+
+	_, _, err = c.SearchBestWithFallback("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", []string{"de", "Italian"})
+	assert.True(t, errors.Is(err, addic7ed.ErrNoSubtitlesForLanguage), "unexpected error %v", err)
+	assert.Contains(t, err.Error(), "de, Italian")
+}
+
+func TestSearchBestMulti(t *testing.T) {
+	var searches int64
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{countSearches(episodeHandler(t, nil), &searches)}}))
+	name, best, err := c.SearchBestMulti("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", []string{"en", "French", "de"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Shameless (US) - 08x11 - A Gallagher Pedicure", name)
+	assert.Len(t, best, 2)
+	assert.Equal(t, "English", best["en"].Language)
+	assert.Equal(t, "BATV", best["en"].Version)
+	assert.Equal(t, "French", best["French"].Language)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&searches))
+
+	_, best, err = c.SearchBestMulti("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", []string{"de"})
+	assert.True(t, errors.Is(err, addic7ed.ErrNoSubtitlesForLanguage), "unexpected error %v", err)
+	assert.Empty(t, best)
+}
@@ -0,0 +1,66 @@
+package addic7ed
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DownloadLimitError details why the daily download limit is exceeded. It is the underlying error of ErrDownloadLimitExceeded errors:
+//
+//	var limitErr *addic7ed.DownloadLimitError
+//	if errors.As(err, &limitErr) {
+//		time.Sleep(time.Until(limitErr.ResetAt))
+//	}
+type DownloadLimitError struct {
+	// ResetAt is the time when downloads are allowed again, or the zero time if Addic7ed did not tell it
+	ResetAt time.Time
+}
+
+func (e *DownloadLimitError) Error() string {
+	if e.ResetAt.IsZero() {
+		return "reset time is unknown"
+	}
+	return fmt.Sprintf("downloads are allowed again at %v", e.ResetAt.Format(time.RFC3339))
+}
+
+// limitPageMarkers are lowered texts of the page Addic7ed serves when the daily download limit is exceeded
+var limitPageMarkers = [][]byte{
+	[]byte("download count exceeded"),
+	[]byte("daily download limit"),
+	[]byte("downloads per day"),
+}
+
+var limitResetRegexp = regexp.MustCompile(`(?i)(?:reset|again)[^0-9]{0,40}(\d+)\s*(hours?|minutes?)(?:\s*(?:and\s*)?(\d+)\s*minutes?)?`)
+
+// checkDownloadLimit checks whether a downloaded file is the page served by Addic7ed when the daily download limit is exceeded
+// now is the time of the download, used to compute when downloads are allowed again.
+func checkDownloadLimit(contentType string, data []byte, now time.Time) error {
+	if !isHTML(contentType, data) {
+		return nil
+	}
+	lowered := bytes.ToLower(data)
+	for _, marker := range limitPageMarkers {
+		if bytes.Contains(lowered, marker) {
+			return newError(CodeDownloadLimitExceeded, &DownloadLimitError{ResetAt: limitResetTime(data, now)}, "daily download limit exceeded")
+		}
+	}
+	return nil
+}
+
+// limitResetTime finds when downloads are allowed again from the page served when the daily download limit is exceeded
+func limitResetTime(page []byte, now time.Time) time.Time {
+	m := limitResetRegexp.FindSubmatch(page)
+	if m == nil {
+		return time.Time{}
+	}
+	n, _ := strconv.Atoi(string(m[1]))
+	if strings.HasPrefix(strings.ToLower(string(m[2])), "hour") {
+		minutes, _ := strconv.Atoi(string(m[3]))
+		return now.Add(time.Duration(n)*time.Hour + time.Duration(minutes)*time.Minute)
+	}
+	return now.Add(time.Duration(n) * time.Minute)
+}
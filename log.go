@@ -0,0 +1,98 @@
+package addic7ed
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// LogLevel is the verbosity of the logs of a client
+type LogLevel int
+
+const (
+	// LevelOff disables logs. It is the default level
+	LevelOff LogLevel = iota
+	// LevelError logs errors only
+	LevelError
+	// LevelWarn logs errors and things that went wrong but did not prevent getting a result
+	LevelWarn
+	// LevelInfo logs the decisions taken by the client: which page is used, which subtitle is the best...
+	LevelInfo
+	// LevelTrace logs everything, including the very detailed trace of the scoring
+	LevelTrace
+)
+
+func (l LogLevel) String() string {
+	switch l {
+	case LevelOff:
+		return "off"
+	case LevelError:
+		return "error"
+	case LevelWarn:
+		return "warn"
+	case LevelInfo:
+		return "info"
+	case LevelTrace:
+		return "trace"
+	}
+	return fmt.Sprintf("LogLevel(%d)", int(l))
+}
+
+// slogLevel maps the log level to a slog level. Trace logs are logged below the debug level of slog
+func (l LogLevel) slogLevel() slog.Level {
+	switch l {
+	case LevelError:
+		return slog.LevelError
+	case LevelWarn:
+		return slog.LevelWarn
+	case LevelInfo:
+		return slog.LevelInfo
+	}
+	return slog.LevelDebug - 4
+}
+
+// WithLogger sends the logs of the client to the given logger instead of stdout.
+// The log level of the client still applies, see WithLogLevel. Trace logs are logged at level slog.LevelDebug-4.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithLogLevel sets the verbosity of the logs of the client, see SetLogLevel
+func WithLogLevel(level LogLevel) Option {
+	return func(c *Client) {
+		c.level = level
+	}
+}
+
+// SetLogLevel is used to set the verbosity of the logs
+func (c *Client) SetLogLevel(level LogLevel) {
+	c.level = level
+}
+
+func (c *call) logf(level LogLevel, message string, params ...interface{}) {
+	if c.level < level {
+		return
+	}
+	if c.logger != nil {
+		c.logger.Log(c.ctx, level.slogLevel(), fmt.Sprintf(message, params...))
+		return
+	}
+	fmt.Printf(message+"\n", params...)
+}
+
+func (c *call) errorf(message string, params ...interface{}) {
+	c.logf(LevelError, message, params...)
+}
+
+func (c *call) warnf(message string, params ...interface{}) {
+	c.logf(LevelWarn, message, params...)
+}
+
+func (c *call) infof(message string, params ...interface{}) {
+	c.logf(LevelInfo, message, params...)
+}
+
+func (c *call) tracef(message string, params ...interface{}) {
+	c.logf(LevelTrace, message, params...)
+}
@@ -0,0 +1,41 @@
+package addic7ed_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+// failingTransport fails all requests, without reaching the network
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("network is down")
+}
+
+func TestWithLogger(t *testing.T) {
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug - 4}))
+	c := addic7ed.New(
+		addic7ed.WithHTTPClient(&http.Client{Transport: failingTransport{}}),
+		addic7ed.WithoutRetry(),
+		addic7ed.WithLogger(logger),
+		addic7ed.WithLogLevel(addic7ed.LevelError),
+	)
+	_, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.Equal(t, addic7ed.CodeServerUnreachable, addic7ed.ErrorCodeOf(err))
+	assert.Contains(t, logs.String(), "level=ERROR")
+	assert.Contains(t, logs.String(), "network is down")
+	assert.NotContains(t, logs.String(), "Searching show")
+
+	logs.Reset()
+	_, err = c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", addic7ed.WithTrace())
+	assert.Error(t, err)
+	assert.Contains(t, logs.String(), "level=DEBUG-4")
+}
@@ -0,0 +1,63 @@
+package addic7ed
+
+import (
+	"context"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strings"
+)
+
+// Login logs in to Addic7ed with a user account. Logged-in users get a higher daily download quota.
+// Session cookies are stored in the cookie jar of the HTTP client of the client, and sent with every subsequent search and download.
+// A cookie jar is added to the HTTP client if it has none. With WithCookieFile, the session is also saved for later clients.
+// Login must be called before using the client concurrently. It returns ErrLoginFailed if Addic7ed refuses the credentials.
+func (c *Client) Login(username, password string) error {
+	return c.LoginContext(context.Background(), username, password)
+}
+
+// LoginContext is like Login, with a context to cancel the login
+func (c *Client) LoginContext(ctx context.Context, username, password string) error {
+	if c.httpClient.Jar == nil {
+		jar, err := cookiejar.New(nil)
+		if err != nil {
+			return err
+		}
+		// The given HTTP client is copied, so that the jar of the session is not shared with other users of the HTTP client
+		httpClient := *c.httpClient
+		httpClient.Jar = jar
+		c.httpClient = &httpClient
+	}
+
+	form := url.Values{}
+	form.Set("username", username)
+	form.Set("password", password)
+	form.Set("Submit", "Log in")
+	loginURL := c.url("dologin.php")
+	req, err := http.NewRequestWithContext(ctx, "POST", loginURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Add("User-Agent", userAgent)
+	req.Header.Add("Referer", c.url("login.php"))
+	c.setHeaders(req)
+
+	// Addic7ed redirects to the home page when the login succeeds, and shows an error page otherwise
+	loginClient := *c.httpClient
+	loginClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+	if err := c.waitTurn(ctx, ""); err != nil {
+		return err
+	}
+	resp, err := loginClient.Do(req)
+	if err != nil {
+		return newError(CodeServerUnreachable, err, "Unable to reach addic7ed server")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		return newError(CodeLoginFailed, nil, "Unable to log in to Addic7ed as %v", username)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package addic7ed
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var (
+	completionRegexp   = regexp.MustCompile(`(\d+(?:\.\d+)?)\s*%`)
+	downloadsRegexp    = regexp.MustCompile(`(?i)([\d,]+)\s+downloads`)
+	isoDateRegexp      = regexp.MustCompile(`\d{4}-\d{2}-\d{2}`)
+	textDateRegexp     = regexp.MustCompile(`[A-Z][a-z]{2} \d{1,2}, \d{4}`)
+	relativeDateRegexp = regexp.MustCompile(`(?i)(\d+)\s+(minute|hour|day|week|month|year)s?\s+ago`)
+)
+
+// subtitleInfo is the metadata of a subtitle, shown on the page of the episode
+type subtitleInfo struct {
+	completion      float64
+	hearingImpaired bool
+	downloads       int
+	uploader        string
+	uploadedAt      time.Time
+}
+
+// parseSubtitleInfo parses the metadata of a subtitle from the table of its version and the row of its language
+// The row following the row of the language holds the details of the subtitle, like the number of downloads.
+// Missing metadata are left empty.
+func parseSubtitleInfo(version, languageRow *goquery.Selection, now time.Time) subtitleInfo {
+	info := subtitleInfo{
+		uploader: strings.TrimSpace(version.Find(`a[href^="/user/"]`).First().Text()),
+	}
+
+	info.completion = parseCompletion(languageRow.Text())
+
+	details := languageRow.Next()
+	if details.Find(".language").Length() > 0 {
+		details = details.Slice(0, 0) // The next row is another language, the subtitle has no details
+	}
+	info.hearingImpaired = languageRow.Find(`img[title="Hearing Impaired"]`).Length() > 0 ||
+		details.Find(`img[title="Hearing Impaired"]`).Length() > 0
+	if m := downloadsRegexp.FindStringSubmatch(details.Text()); m != nil {
+		info.downloads, _ = strconv.Atoi(strings.ReplaceAll(m[1], ",", ""))
+	}
+
+	version.Find(".newsDate").EachWithBreak(func(i int, s *goquery.Selection) bool {
+		info.uploadedAt = parseUploadDate(s.Text(), now)
+		return info.uploadedAt.IsZero()
+	})
+	return info
+}
+
+// parseCompletion parses the completion of a subtitle, like "67.19% Completed" or "Completed"
+func parseCompletion(status string) float64 {
+	if m := completionRegexp.FindStringSubmatch(status); m != nil {
+		completion, _ := strconv.ParseFloat(m[1], 64)
+		return completion
+	}
+	if strings.Contains(strings.ToLower(status), "completed") {
+		return 100
+	}
+	return 0
+}
+
+// parseUploadDate parses an upload date, either absolute like "2018-03-26" or "Mar 26, 2018",
+// or relative to now like "3 days ago". It returns the zero time if the text contains no date.
+func parseUploadDate(text string, now time.Time) time.Time {
+	if date := isoDateRegexp.FindString(text); date != "" {
+		if t, err := time.Parse("2006-01-02", date); err == nil {
+			return t
+		}
+	}
+	if date := textDateRegexp.FindString(text); date != "" {
+		if t, err := time.Parse("Jan 2, 2006", date); err == nil {
+			return t
+		}
+	}
+	if m := relativeDateRegexp.FindStringSubmatch(text); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			return time.Time{}
+		}
+		switch strings.ToLower(m[2]) {
+		case "minute":
+			return now.Add(-time.Duration(n) * time.Minute)
+		case "hour":
+			return now.Add(-time.Duration(n) * time.Hour)
+		case "day":
+			return now.AddDate(0, 0, -n)
+		case "week":
+			return now.AddDate(0, 0, -7*n)
+		case "month":
+			return now.AddDate(0, -n, 0)
+		case "year":
+			return now.AddDate(-n, 0, 0)
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,99 @@
+package addic7ed
+
+import (
+	"math"
+	"sync"
+)
+
+// scoreMarginBounds are the upper bounds of the buckets of score margins
+var scoreMarginBounds = []float64{0.1, 0.5, 1, 2, 5, 10, 20}
+
+// ScoreMargins is the distribution of the margins between the scores of the best and the second best versions,
+// over the searches of the best subtitle of a client (see SearchBest). Searches with a single version have no margin.
+// A low margin means that the best version was picked with a low confidence.
+type ScoreMargins struct {
+	// Count is the number of margins
+	Count int
+	// Min, Max and Mean are the smallest, largest and mean margins, 0 without margin
+	Min  float64
+	Max  float64
+	Mean float64
+	// Bounds are the upper bounds of the buckets. Buckets[i] counts the margins lower than Bounds[i],
+	// and not counted in previous buckets. The last bucket counts the margins above all bounds.
+	Bounds  []float64
+	Buckets []int
+}
+
+// scoreMarginStats records the score margins of a client, safe for concurrent use
+type scoreMarginStats struct {
+	mu      sync.Mutex
+	count   int
+	min     float64
+	max     float64
+	sum     float64
+	buckets []int
+}
+
+func (s *scoreMarginStats) record(margin float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.count == 0 {
+		s.min, s.max = margin, margin
+	}
+	s.count++
+	s.min = math.Min(s.min, margin)
+	s.max = math.Max(s.max, margin)
+	s.sum += margin
+	if s.buckets == nil {
+		s.buckets = make([]int, len(scoreMarginBounds)+1)
+	}
+	i := 0
+	for i < len(scoreMarginBounds) && margin >= scoreMarginBounds[i] {
+		i++
+	}
+	s.buckets[i]++
+}
+
+// ScoreMargins returns the distribution of the score margins of the searches of the best subtitle done by the client so far.
+// Use it to pick a threshold under which the best subtitle should be confirmed by the user.
+func (c *Client) ScoreMargins() ScoreMargins {
+	s := c.scoreMargins
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	margins := ScoreMargins{
+		Count:   s.count,
+		Min:     s.min,
+		Max:     s.max,
+		Bounds:  append([]float64(nil), scoreMarginBounds...),
+		Buckets: make([]int, len(scoreMarginBounds)+1),
+	}
+	copy(margins.Buckets, s.buckets)
+	if s.count > 0 {
+		margins.Mean = s.sum / float64(s.count)
+	}
+	return margins
+}
+
+// ResetScoreMargins forgets the score margins recorded so far, to start a new run
+func (c *Client) ResetScoreMargins() {
+	s := c.scoreMargins
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.count, s.min, s.max, s.sum, s.buckets = 0, 0, 0, 0, nil
+}
+
+// scoreMargin returns the margin between the best and the second best scores, and false with less than two scores
+func scoreMargin(scores map[string]float64) (float64, bool) {
+	if len(scores) < 2 {
+		return 0, false
+	}
+	best, second := math.Inf(-1), math.Inf(-1)
+	for _, score := range scores {
+		if score > best {
+			best, second = score, best
+		} else if score > second {
+			second = score
+		}
+	}
+	return best - second, true
+}
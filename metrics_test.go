@@ -0,0 +1,36 @@
+package addic7ed_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+func TestScoreMargins(t *testing.T) {
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{episodeHandler(t, nil)}}))
+	assert.Equal(t, 0, c.ScoreMargins().Count)
+
+	for _, lang := range []string{"English", "English", "French"} {
+		_, subtitle, err := c.SearchBest("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", lang)
+		assert.NoError(t, err)
+		assert.Equal(t, "BATV", subtitle.Version)
+	}
+	// French subtitles have a single version, so there is no margin
+	margins := c.ScoreMargins()
+	assert.Equal(t, 2, margins.Count)
+	assert.True(t, margins.Min > 0)
+	assert.Equal(t, margins.Min, margins.Max)
+	assert.Equal(t, margins.Min, margins.Mean)
+	assert.Len(t, margins.Buckets, len(margins.Bounds)+1)
+	total := 0
+	for _, count := range margins.Buckets {
+		total += count
+	}
+	assert.Equal(t, 2, total)
+
+	c.ResetScoreMargins()
+	assert.Equal(t, 0, c.ScoreMargins().Count)
+}
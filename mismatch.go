@@ -0,0 +1,59 @@
+package addic7ed
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// WithEpisodeMismatchWarnings only raises a WarningEpisodeMismatch when the episode found by a search is not the episode
+// of the filename, instead of returning ErrEpisodeMismatch, for applications that would rather check the found episode themselves
+func WithEpisodeMismatchWarnings() Option {
+	return func(c *Client) {
+		c.warnOnMismatch = true
+	}
+}
+
+// EpisodeMismatchError is the underlying error of ErrEpisodeMismatch errors
+type EpisodeMismatchError struct {
+	// Expected is the episode of the filename, after the episode mapping of the show, see WithEpisodeMapping
+	Expected EpisodeNumber
+	// Found is the episode of the page found by the search
+	Found EpisodeNumber
+}
+
+func (e *EpisodeMismatchError) Error() string {
+	return fmt.Sprintf("expected episode %v, found %v", e.Expected, e.Found)
+}
+
+// checkEpisode checks that the episode found by a search is the episode of the filename, when both are known.
+// Searches of multi-part episodes aren't checked, as their page may be the one of any of their parts.
+func (c *call) checkEpisode(showStr string, show *Show) error {
+	if _, _, ok := findMultiPartEpisodes(showStr); ok || show.Number == (EpisodeNumber{}) {
+		return nil
+	}
+	expected, _, ok := findEpisodeNumber(c.mapEpisode(showStr))
+	if !ok || expected == show.Number {
+		return nil
+	}
+	mismatch := &EpisodeMismatchError{Expected: expected, Found: show.Number}
+	if c.warnOnMismatch {
+		warning := c.warn(WarningEpisodeMismatch, "Search %v found episode %v instead of %v", showStr, show.Number, expected)
+		// The warnings of the show may be shared with the call or the cache, so they are copied
+		show.Warnings = append(slices.Clip(show.Warnings), warning)
+		return nil
+	}
+	return newError(CodeEpisodeMismatch, mismatch, "Search %v found %v", showStr, show.Name)
+}
+
+// checkFoundEpisode checks the episode found by a search like checkEpisode, including episodes without subtitles yet,
+// so that the wrong page is not polled until it gets subtitles. It returns the error of the search otherwise.
+func (c *call) checkFoundEpisode(showStr string, show *Show, err error) error {
+	if err != nil && !errors.Is(err, ErrNoSubtitlesYet) {
+		return err
+	}
+	if mismatch := c.checkEpisode(showStr, show); mismatch != nil {
+		return mismatch
+	}
+	return err
+}
@@ -0,0 +1,110 @@
+package addic7ed_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+func TestEpisodeMismatch(t *testing.T) {
+	transport := handlerTransport{episodeHandler(t, nil)}
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}))
+
+	// The fixture serves the page of S08E11 for every search
+	show, err := c.SearchAll("Shameless.US.S08E12.720p.HDTV.x264-BATV[ettv].mkv")
+	assert.True(t, errors.Is(err, addic7ed.ErrEpisodeMismatch), "unexpected error %v", err)
+	var mismatch *addic7ed.EpisodeMismatchError
+	if assert.True(t, errors.As(err, &mismatch)) {
+		assert.Equal(t, addic7ed.EpisodeNumber{Season: 8, Episode: 12}, mismatch.Expected)
+		assert.Equal(t, addic7ed.EpisodeNumber{Season: 8, Episode: 11}, mismatch.Found)
+	}
+	assert.Equal(t, "A Gallagher Pedicure", show.Title)
+
+	_, _, err = c.SearchBest("Shameless.US.S08E12.720p.HDTV.x264-BATV[ettv].mkv", "English")
+	assert.True(t, errors.Is(err, addic7ed.ErrEpisodeMismatch), "unexpected error %v", err)
+
+	_, err = c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv].mkv")
+	assert.NoError(t, err)
+}
+
+func TestEpisodeMismatchWithoutSubtitles(t *testing.T) {
+	server := httptest.NewServer(withoutSubtitlesFirst(episodeHandler(t, nil), 2))
+	defer server.Close()
+	c := addic7ed.New(addic7ed.WithBaseURL(server.URL), addic7ed.WithLadder(addic7ed.Ladder{time.Millisecond}))
+
+	// The wrong episode is reported even without subtitles yet, so that it is not polled
+	_, err := c.SearchAll("Shameless.US.S08E12.720p.HDTV.x264-BATV[ettv].mkv")
+	assert.True(t, errors.Is(err, addic7ed.ErrEpisodeMismatch), "unexpected error %v", err)
+	_, _, err = c.SearchBestWhenAvailable(context.Background(), "Shameless.US.S08E12.720p.HDTV.x264-BATV[ettv].mkv", "English")
+	assert.True(t, errors.Is(err, addic7ed.ErrEpisodeMismatch), "unexpected error %v", err)
+}
+
+func TestEpisodeMismatchOfSeq(t *testing.T) {
+	transport := handlerTransport{episodeHandler(t, nil)}
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}))
+	_, _, err := c.SearchAllSeq("Shameless.US.S08E12.720p.HDTV.x264-BATV[ettv].mkv")
+	assert.True(t, errors.Is(err, addic7ed.ErrEpisodeMismatch), "unexpected error %v", err)
+
+	c = addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}), addic7ed.WithEpisodeMismatchWarnings())
+	var warnings []addic7ed.Warning
+	_, subtitles, err := c.SearchAllSeq("Shameless.US.S08E12.720p.HDTV.x264-BATV[ettv].mkv", addic7ed.WithWarnings(func(w addic7ed.Warning) {
+		warnings = append(warnings, w)
+	}))
+	assert.NoError(t, err)
+	assert.NotNil(t, subtitles)
+	if assert.Len(t, warnings, 1) {
+		assert.Equal(t, addic7ed.WarningEpisodeMismatch, warnings[0].Code)
+	}
+}
+
+func TestEpisodeMismatchWithEpisodeMapping(t *testing.T) {
+	transport := handlerTransport{episodeHandler(t, nil)}
+	c := addic7ed.New(
+		addic7ed.WithHTTPClient(&http.Client{Transport: transport}),
+		addic7ed.WithEpisodeMapping("Shameless US", addic7ed.EpisodeMapping{{Season: 0, Episode: 3}: {Season: 8, Episode: 11}}),
+	)
+	_, err := c.SearchAll("Shameless.US.S00E03.720p.HDTV.x264-BATV[ettv].mkv")
+	assert.NoError(t, err)
+}
+
+func TestWithEpisodeMismatchWarnings(t *testing.T) {
+	transport := handlerTransport{episodeHandler(t, nil)}
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}), addic7ed.WithEpisodeMismatchWarnings())
+
+	var warnings []addic7ed.Warning
+	show, err := c.SearchAll("Shameless.US.S08E12.720p.HDTV.x264-BATV[ettv].mkv", addic7ed.WithWarnings(func(w addic7ed.Warning) {
+		warnings = append(warnings, w)
+	}))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, show.Subtitles)
+	if assert.Len(t, warnings, 1) {
+		assert.Equal(t, addic7ed.WarningEpisodeMismatch, warnings[0].Code)
+	}
+	assert.Contains(t, show.Warnings, warnings[0])
+}
+
+func TestEpisodeMismatchWarningsOfCachedEpisodes(t *testing.T) {
+	transport := handlerTransport{episodeHandler(t, nil)}
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}), addic7ed.WithCache(time.Hour, 0), addic7ed.WithEpisodeMismatchWarnings())
+
+	// Warnings of a search are not kept by the cached episode
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			show, err := c.SearchAll("Shameless.US.S08E12.720p.HDTV.x264-BATV[ettv].mkv")
+			assert.NoError(t, err)
+			assert.Len(t, show.Warnings, 1)
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,64 @@
+package addic7ed
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"time"
+)
+
+// SearchBestMultiPart searches the best subtitle of each episode covered by a file, like "Show.S01E01-E02.720p.HDTV.x264-GROUP.mkv"
+// Each episode is searched like SearchBest, with the search restricted to the episode. A search covering only one episode is searched as is.
+// It returns the episode names and the found subtitles, in the order of the episodes.
+func (c *Client) SearchBestMultiPart(showStr, lang string, opts ...CallOption) ([]string, Subtitles, error) {
+	return c.SearchBestMultiPartContext(context.Background(), showStr, lang, opts...)
+}
+
+// SearchBestMultiPartContext is like SearchBestMultiPart, with a context to cancel the searches
+func (c *Client) SearchBestMultiPartContext(ctx context.Context, showStr, lang string, opts ...CallOption) ([]string, Subtitles, error) {
+	call := c.newCall(ctx, opts)
+	searches := []string{showStr}
+	if numbers, loc, ok := findMultiPartEpisodes(showStr); ok {
+		call.infof("Search %v covers %v episodes", showStr, len(numbers))
+		searches = searches[:0]
+		for _, number := range numbers {
+			searches = append(searches, showStr[:loc[0]]+number.String()+showStr[loc[1]:])
+		}
+	}
+
+	names := []string{}
+	subtitles := Subtitles{}
+	for _, search := range searches {
+		name, subtitle, err := call.searchBest(search, lang)
+		if err != nil {
+			return nil, nil, err
+		}
+		names = append(names, name)
+		subtitles = append(subtitles, subtitle)
+	}
+	return names, subtitles, nil
+}
+
+// DownloadConcatTo downloads all subtitles and concatenates them in one SRT subtitle written to a given path.
+// offsets are the start times of each episode in the video file, used to retime the subtitles. See ConcatSRT
+func (ss Subtitles) DownloadConcatTo(path string, offsets []time.Duration) error {
+	return ss.DownloadConcatToContext(context.Background(), path, offsets)
+}
+
+// DownloadConcatToContext is like DownloadConcatTo, with a context to cancel the downloads
+func (ss Subtitles) DownloadConcatToContext(ctx context.Context, path string, offsets []time.Duration) error {
+	parts := []io.Reader{}
+	for _, s := range ss {
+		data, err := s.download(ctx)
+		if err != nil {
+			return err
+		}
+		parts = append(parts, bytes.NewReader(data))
+	}
+	var concat bytes.Buffer
+	if err := ConcatSRT(&concat, parts, offsets); err != nil {
+		return err
+	}
+	return os.WriteFile(path, concat.Bytes(), 0644)
+}
@@ -0,0 +1,55 @@
+package addic7ed
+
+import (
+	"net/http"
+	"strings"
+)
+
+// Option configures a client at creation, see New
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used to send all requests to Addic7ed, searches and downloads.
+// Use it to set timeouts, proxies, custom transports or connection pooling policies.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithHeaders sets headers sent with every request to Addic7ed, searches, downloads and login,
+// for example to mimic a browser with Accept-Language or DNT headers, or to send custom cookies.
+// They replace the headers set by the client, like the User-Agent.
+func WithHeaders(headers map[string]string) Option {
+	return func(c *Client) {
+		c.headers = make(map[string]string, len(headers))
+		for name, value := range headers {
+			c.headers[name] = value
+		}
+	}
+}
+
+// setHeaders sets the custom headers of the client on a request
+func (c *Client) setHeaders(req *http.Request) {
+	for name, value := range c.headers {
+		req.Header.Set(name, value)
+	}
+}
+
+// DefaultBaseURL is the URL of Addic7ed website, used by new clients
+const DefaultBaseURL = "https://www.addic7ed.com"
+
+// WithBaseURL sets the URL of Addic7ed website, used for all requests and the links of found subtitles.
+// Use it to switch back to http, use a mirror or a proxy domain, or point at a local test server.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// url returns the absolute URL of a path of Addic7ed website. Absolute URLs are returned as is
+func (c *Client) url(path string) string {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		return path
+	}
+	return c.baseURL + "/" + strings.TrimPrefix(path, "/")
+}
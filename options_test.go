@@ -0,0 +1,55 @@
+package addic7ed_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+// recordingTransport records the requests, and fails them without reaching the network
+type recordingTransport struct {
+	requests []*http.Request
+}
+
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests = append(t.requests, req)
+	return nil, errors.New("network is down")
+}
+
+func TestWithHeaders(t *testing.T) {
+	transport := &recordingTransport{}
+	headers := map[string]string{"Accept-Language": "fr-FR", "User-Agent": "my-browser"}
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}), addic7ed.WithHeaders(headers), addic7ed.WithoutRetry())
+	headers["DNT"] = "1" // Changing the map after creating the client has no effect
+
+	_, _ = c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	_ = c.Login("user", "password")
+	assert.Len(t, transport.requests, 2)
+	for _, req := range transport.requests {
+		assert.Equal(t, "fr-FR", req.Header.Get("Accept-Language"))
+		assert.Equal(t, "my-browser", req.Header.Get("User-Agent"))
+		assert.Empty(t, req.Header.Get("DNT"))
+	}
+}
+
+func TestWithBaseURL(t *testing.T) {
+	const srt = "1\n00:00:01,000 --> 00:00:02,000\nHello\n"
+	server := httptest.NewServer(episodeHandler(t, map[string]string{"/original/131967/0": srt}))
+	defer server.Close()
+	c := addic7ed.New(addic7ed.WithBaseURL(server.URL + "/"))
+
+	show, err := c.SearchEpisode("Shameless (US)", 8, 11, "English")
+	assert.NoError(t, err)
+	assert.Equal(t, "Shameless (US) - 08x11 - A Gallagher Pedicure", show.Name)
+	assert.Equal(t, server.URL+"/original/131967/0", show.Subtitles[0].Link)
+	content, err := show.Subtitles[0].Download()
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(content)
+	assert.Equal(t, srt, string(data))
+}
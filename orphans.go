@@ -0,0 +1,136 @@
+package addic7ed
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// subtitleFolders are the names of the folders keeping subtitles apart from their videos, skipped when looking for orphans
+var subtitleFolders = []string{"subs", "subtitles"}
+
+// OrphanSubtitles finds the subtitles of a directory and its subdirectories whose video no longer exists, in order.
+// A subtitle like "Show.S01E01.en.srt" belongs to a video next to it like "Show.S01E01.mkv", so that subtitles left
+// behind by deleted or upgraded videos are found. Hidden folders and folders named "Subs" or "Subtitles" are skipped.
+func OrphanSubtitles(dir string) ([]string, error) {
+	return orphanSubtitles(dir, "")
+}
+
+// orphanSubtitles finds the orphans of a directory, skipping the archive of the orphans if set
+func orphanSubtitles(dir, archive string) ([]string, error) {
+	filesByDir := map[string][]string{}
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			name := entry.Name()
+			if path != dir && (path == archive || strings.HasPrefix(name, ".") || slices.Contains(subtitleFolders, strings.ToLower(name))) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		filesByDir[filepath.Dir(path)] = append(filesByDir[filepath.Dir(path)], entry.Name())
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	orphans := []string{}
+	for dir, files := range filesByDir {
+		for _, file := range files {
+			if !IsSubtitle(file) {
+				continue
+			}
+			hasVideo := slices.ContainsFunc(files, func(other string) bool {
+				base := strings.TrimSuffix(other, filepath.Ext(other))
+				return IsVideo(other) && strings.HasPrefix(file, base+".")
+			})
+			if !hasVideo {
+				orphans = append(orphans, filepath.Join(dir, file))
+			}
+		}
+	}
+	slices.Sort(orphans)
+	return orphans, nil
+}
+
+// CleanOrphanSubtitles removes the subtitles of a directory whose video no longer exists, see OrphanSubtitles.
+// When archive is set, they are moved to the archive directory instead, keeping their path relative to dir, so that they
+// can be restored. An archive inside dir is skipped when looking for orphans.
+// It returns the orphans that were removed or archived, and stops at the first error.
+func CleanOrphanSubtitles(dir, archive string) ([]string, error) {
+	if archive != "" {
+		var err error
+		if archive, err = filepath.Abs(archive); err != nil {
+			return nil, err
+		}
+		if dir, err = filepath.Abs(dir); err != nil {
+			return nil, err
+		}
+	}
+	orphans, err := orphanSubtitles(dir, archive)
+	if err != nil {
+		return nil, err
+	}
+	cleaned := []string{}
+	for _, orphan := range orphans {
+		if archive == "" {
+			err = os.Remove(orphan)
+		} else {
+			err = archiveFile(dir, orphan, archive)
+		}
+		if err != nil {
+			return cleaned, err
+		}
+		cleaned = append(cleaned, orphan)
+	}
+	return cleaned, nil
+}
+
+// archiveFile moves a file of a directory to an archive directory, keeping its path relative to the directory.
+// Files are copied then removed when the archive is on another file system.
+func archiveFile(dir, path, archive string) error {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return err
+	}
+	target := filepath.Join(archive, rel)
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	if _, err := os.Stat(target); err == nil {
+		return &fs.PathError{Op: "archive", Path: target, Err: fs.ErrExist}
+	}
+	if err := os.Rename(path, target); err == nil {
+		return nil
+	}
+	if err := copyFile(path, target); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// copyFile copies a file, removing the copy if it fails
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(dst)
+	}
+	return err
+}
@@ -0,0 +1,74 @@
+package addic7ed_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+// writeFiles creates empty files in a directory, with their parent directories
+func writeFiles(t *testing.T, dir string, files ...string) {
+	for _, file := range files {
+		path := filepath.Join(dir, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestOrphanSubtitles(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir,
+		"Show.S01E01.mkv",
+		"Show.S01E01.en.srt",
+		"Show.S01E01.srt",
+		"Show.S01E02.en.srt",
+		"notes.txt",
+		"Season 2/Show.S02E01.720p.WEB.fr.ass",
+		"Subs/Show.S01E03.srt",
+	)
+
+	orphans, err := addic7ed.OrphanSubtitles(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		filepath.Join(dir, "Season 2", "Show.S02E01.720p.WEB.fr.ass"),
+		filepath.Join(dir, "Show.S01E02.en.srt"),
+	}, orphans)
+
+	_, err = addic7ed.OrphanSubtitles(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestCleanOrphanSubtitles(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, "Show.S01E01.mkv", "Show.S01E01.en.srt", "Show.S01E02.en.srt", "Season 2/Show.S02E01.fr.srt")
+
+	// Archived orphans keep their relative path, and the archive is not cleaned itself
+	archive := filepath.Join(dir, ".orphans")
+	cleaned, err := addic7ed.CleanOrphanSubtitles(dir, archive)
+	assert.NoError(t, err)
+	assert.Len(t, cleaned, 2)
+	for _, file := range []string{"Show.S01E02.en.srt", "Season 2/Show.S02E01.fr.srt"} {
+		_, err := os.Stat(filepath.Join(archive, file))
+		assert.NoError(t, err, file)
+		_, err = os.Stat(filepath.Join(dir, file))
+		assert.True(t, os.IsNotExist(err), file)
+	}
+	cleaned, err = addic7ed.CleanOrphanSubtitles(dir, archive)
+	assert.NoError(t, err)
+	assert.Empty(t, cleaned)
+
+	writeFiles(t, dir, "Show.S01E03.srt", ".trash/Show.S01E04.srt")
+	cleaned, err = addic7ed.CleanOrphanSubtitles(dir, "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "Show.S01E03.srt")}, cleaned)
+	_, err = os.Stat(filepath.Join(dir, "Show.S01E01.en.srt"))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,196 @@
+package addic7ed
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// seasonPackRegexp matches the links of the season packs of the season pages, like "/season/5427/8/1" or "/packs/Shameless_S08.zip"
+var seasonPackRegexp = regexp.MustCompile(`(?i)^(?:https?://[^/]+)?/(?:season/\d+/\d+/\d+|\S+\.zip)$`)
+
+// SeasonPack is an archive of the subtitles of all episodes of a season in a language, see SearchSeasonPack
+type SeasonPack struct {
+	// Show is the Addic7ed name of the show, like "Shameless (US)"
+	Show   string
+	Season int
+	// Language is the language of the subtitles, like "English"
+	Language string
+	// Link is the link to download the archive
+	Link string
+
+	client *Client
+}
+
+// SearchSeasonPack searches the season pack of the subtitles of a season of a show in a language, by name or code, to
+// back-fill whole seasons with one download, see DownloadEpisodes. The show is given like in GetShow.
+// It returns ErrNoSubtitlesForLanguage if the season has no pack in the language.
+func (c *Client) SearchSeasonPack(show string, season int, lang string, opts ...CallOption) (SeasonPack, error) {
+	return c.SearchSeasonPackContext(context.Background(), show, season, lang, opts...)
+}
+
+// SearchSeasonPackContext is like SearchSeasonPack, with a context to cancel the search
+func (c *Client) SearchSeasonPackContext(ctx context.Context, show string, season int, lang string, opts ...CallOption) (SeasonPack, error) {
+	tvShow, err := c.GetShowContext(ctx, show, opts...)
+	if err != nil {
+		return SeasonPack{}, err
+	}
+	call := c.newCall(ctx, opts)
+	doc, err := call.createDocFromURL(c.seasonURL(tvShow.ID, season))
+	if err != nil {
+		return SeasonPack{}, err
+	}
+	for _, pack := range call.parseSeasonPacks(doc) {
+		if sameLanguage(pack.Language, lang) {
+			pack.Show, pack.Season = tvShow.Name, season
+			return pack, nil
+		}
+	}
+	return SeasonPack{}, newError(CodeNoSubtitlesForLanguage, nil, "season %v of show %v has no pack in %q", season, tvShow.Name, lang)
+}
+
+// parseSeasonPacks parses the season packs of a season page.
+// The language of a pack is the one of its row, or the one named by its link, like "Download season 8 (English)".
+func (c *call) parseSeasonPacks(doc *goquery.Document) []SeasonPack {
+	packs := []SeasonPack{}
+	doc.Find("a[href]").Each(func(i int, link *goquery.Selection) {
+		href, _ := link.Attr("href")
+		if !seasonPackRegexp.MatchString(strings.TrimSpace(href)) {
+			return
+		}
+		language := strings.TrimSpace(link.Closest("tr").Find(".language").First().Text())
+		if language == "" {
+			title, _ := link.Attr("title")
+			language = findLanguageName(link.Text() + " " + title)
+		}
+		if language == "" {
+			c.warnf("Unable to find the language of season pack %v", href)
+			return
+		}
+		packs = append(packs, SeasonPack{Language: language, Link: c.url(strings.TrimSpace(href)), client: c.Client})
+	})
+	return packs
+}
+
+// findLanguageName finds the name of the longest Addic7ed language in a text, like "French (Canadian)" rather than "French",
+// or returns an empty string if the text names none
+func findLanguageName(text string) string {
+	text = strings.ToLower(text)
+	found := ""
+	for name := range languageCodes {
+		if len(name) > len(found) && strings.Contains(text, name) {
+			found = name
+		}
+	}
+	if found == "" {
+		return ""
+	}
+	return languageTitle(found)
+}
+
+// DownloadEpisodes downloads the season pack and extracts its subtitles to a directory, and returns the paths of the
+// extracted files by episode. Files are named like in the archive, and files whose episode can't be found, like
+// "Shameless.S08.NFO.srt", are skipped. A subtitle is kept by episode, the first one by order of the archive.
+func (p SeasonPack) DownloadEpisodes(ctx context.Context, dir string) (map[EpisodeNumber]string, error) {
+	client := p.client
+	if client == nil {
+		client = DefaultClient()
+	}
+	if client.readOnly {
+		return nil, ErrReadOnly
+	}
+	data, err := client.downloadPack(ctx, p.Link)
+	if err != nil {
+		return nil, err
+	}
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, newError(CodeParseFailure, err, "Unable to read season pack %v", p.Link)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	var decode func([]byte) []byte
+	if client.toUTF8 {
+		decode = func(data []byte) []byte { return client.decode(data, p.Language) }
+	}
+	files := map[EpisodeNumber]string{}
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || subtitlePreference(f.Name) < 0 {
+			continue
+		}
+		number, _, ok := findEpisodeNumber(path.Base(f.Name))
+		if !ok || files[number] != "" {
+			continue
+		}
+		// Only the base names are kept, so that archives can't write outside of the directory
+		target := filepath.Join(dir, path.Base(f.Name))
+		if err := extractPackFile(f, target, decode); err != nil {
+			return files, err
+		}
+		files[number] = target
+	}
+	if len(files) == 0 {
+		return nil, newError(CodeParseFailure, nil, "season pack %v does not contain any episode subtitle", p.Link)
+	}
+	return files, nil
+}
+
+// maxPackSize is the maximum size of a downloaded season pack, holding the subtitles of a whole season
+const maxPackSize = 64 << 20
+
+// downloadPack downloads the archive of a season pack
+func (c *Client) downloadPack(ctx context.Context, link string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", link, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("User-Agent", userAgent)
+	req.Header.Add("Referer", link)
+	c.setHeaders(req)
+	resp, err := c.do(req, "", nil)
+	if err != nil {
+		return nil, newError(CodeServerUnreachable, err, "Unable to reach addic7ed server")
+	}
+	defer resp.Body.Close()
+	if err := checkStatus(resp.StatusCode); err != nil {
+		return nil, err
+	}
+	data, err := readDownload(resp, maxPackSize, "season pack "+link)
+	if err != nil {
+		return nil, err
+	}
+	// When the quota is exceeded, Addic7ed serves a web page instead of the archive
+	if err := checkDownloadLimit(resp.Header.Get("Content-Type"), data, time.Now()); err != nil {
+		return nil, err
+	}
+	atomic.AddInt64(&c.downloads, 1)
+	return data, nil
+}
+
+// extractPackFile extracts a file of a season pack, converting it to UTF-8 with decode if not nil
+func extractPackFile(f *zip.File, target string, decode func([]byte) []byte) error {
+	rc, err := f.Open()
+	if err != nil {
+		return newError(CodeParseFailure, err, "Unable to read %v in season pack", f.Name)
+	}
+	defer rc.Close()
+	data, err := readArchivedFile(rc, f.Name, "season pack")
+	if err != nil {
+		return err
+	}
+	if decode != nil {
+		data = decode(data)
+	}
+	return os.WriteFile(target, data, 0644)
+}
@@ -0,0 +1,108 @@
+package addic7ed_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+// packs are the links of the season packs added to the season fixture
+const packs = `<div id="packs">
+  <a href="/season/5427/8/1">Download season 8 (English)</a>
+  <table><tr><td class="language">French</td><td><a href="/season/5427/8/8">Download</a></td></tr></table>
+</div>
+</body>`
+
+// packHandler serves the season fixture with season packs, and an English pack of the season
+func packHandler(t *testing.T, files map[string]string) http.Handler {
+	season, err := os.ReadFile("testdata/season.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var archive bytes.Buffer
+	w := zip.NewWriter(&archive)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	handler := showHandler(t)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/show/5427" && r.URL.Query().Get("season") == "8":
+			w.Write([]byte(strings.Replace(string(season), "</body>", packs, 1)))
+		case r.URL.Path == "/season/5427/8/1":
+			w.Header().Set("Content-Type", "application/zip")
+			w.Write(archive.Bytes())
+		default:
+			handler.ServeHTTP(w, r)
+		}
+	})
+}
+
+func TestSearchSeasonPack(t *testing.T) {
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{packHandler(t, nil)}}))
+
+	pack, err := c.SearchSeasonPack("Shameless (US)", 8, "en")
+	assert.NoError(t, err)
+	assert.Equal(t, "Shameless (US)", pack.Show)
+	assert.Equal(t, 8, pack.Season)
+	assert.Equal(t, "English", pack.Language)
+	assert.Equal(t, "https://www.addic7ed.com/season/5427/8/1", pack.Link)
+
+	// Languages are also found in the rows of the packs
+	pack, err = c.SearchSeasonPack("5427", 8, "French")
+	assert.NoError(t, err)
+	assert.Equal(t, "https://www.addic7ed.com/season/5427/8/8", pack.Link)
+
+	_, err = c.SearchSeasonPack("5427", 8, "German")
+	assert.True(t, errors.Is(err, addic7ed.ErrNoSubtitlesForLanguage), "unexpected error %v", err)
+}
+
+func TestSeasonPackDownloadEpisodes(t *testing.T) {
+	const srt = "1\n00:00:01,000 --> 00:00:02,000\nHello\n"
+	files := map[string]string{
+		"Shameless.S08E11.BATV.en.srt":   srt,
+		"Season 8/Shameless.8x12.en.srt": srt,
+		"Shameless.S08.nfo":              "nothing",
+		"../Shameless.S08E13.en.srt":     srt,
+	}
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{packHandler(t, files)}}))
+	pack, err := c.SearchSeasonPack("5427", 8, "English")
+	assert.NoError(t, err)
+
+	dir := t.TempDir()
+	episodes, err := pack.DownloadEpisodes(context.Background(), dir)
+	assert.NoError(t, err)
+	assert.Equal(t, map[addic7ed.EpisodeNumber]string{
+		{Season: 8, Episode: 11}: filepath.Join(dir, "Shameless.S08E11.BATV.en.srt"),
+		{Season: 8, Episode: 12}: filepath.Join(dir, "Shameless.8x12.en.srt"),
+		{Season: 8, Episode: 13}: filepath.Join(dir, "Shameless.S08E13.en.srt"),
+	}, episodes)
+	content, err := os.ReadFile(episodes[addic7ed.EpisodeNumber{Season: 8, Episode: 12}])
+	assert.NoError(t, err)
+	assert.Equal(t, srt, string(content))
+	assert.Equal(t, 1, c.Usage().Downloads)
+
+	readOnly := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{packHandler(t, files)}}))
+	readOnly.ReadOnly(true)
+	pack, err = readOnly.SearchSeasonPack("5427", 8, "English")
+	assert.NoError(t, err)
+	_, err = pack.DownloadEpisodes(context.Background(), dir)
+	assert.True(t, errors.Is(err, addic7ed.ErrReadOnly), "unexpected error %v", err)
+}
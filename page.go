@@ -0,0 +1,33 @@
+package addic7ed
+
+import (
+	"context"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// EpisodePage is the page of an episode on Addic7ed website, as found by a search
+type EpisodePage struct {
+	// Name is the name of the episode, like "Shameless (US) - 08x11 - A Gallagher Pedicure"
+	Name string
+	// Document is the parsed HTML page, to extract data the package does not model
+	Document *goquery.Document
+}
+
+// FetchEpisodePage searches in the Addic7ed website for a given episode of a show, like SearchAll, and returns its page as is.
+// It is an escape hatch for data the package does not model: the structure of the page may change at any time.
+func (c *Client) FetchEpisodePage(showStr string, opts ...CallOption) (EpisodePage, error) {
+	return c.FetchEpisodePageContext(context.Background(), showStr, opts...)
+}
+
+// FetchEpisodePageContext is like FetchEpisodePage, with a context to cancel the search
+func (c *Client) FetchEpisodePageContext(ctx context.Context, showStr string, opts ...CallOption) (EpisodePage, error) {
+	name, doc, err := c.newCall(ctx, opts).fetchShowPage(showStr)
+	if err != nil {
+		return EpisodePage{}, err
+	}
+	return EpisodePage{
+		Name:     name,
+		Document: doc,
+	}, nil
+}
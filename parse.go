@@ -0,0 +1,36 @@
+package addic7ed
+
+import (
+	"context"
+	"io"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ParseEpisodePage parses a page of an episode, as served by Addic7ed website, to find the name of the episode and its subtitles.
+// It never panics, whatever the content of the page: an error is returned when the page is not an episode page.
+// Download links of the found subtitles are absolute links to Addic7ed website.
+func ParseEpisodePage(r io.Reader) (Show, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return Show{}, newError(CodeParseFailure, err, "Unable to construct document from page")
+	}
+	c := New().newCall(context.Background(), nil)
+	name, err := c.findShowName(doc)
+	if err != nil {
+		return Show{}, err
+	}
+	subtitles := Subtitles{}
+	c.parseSubtitles(doc, func(subtitle Subtitle) bool {
+		subtitles = append(subtitles, subtitle)
+		return true
+	})
+	show := Show{
+		Name:         name,
+		Subtitles:    subtitles,
+		Translations: c.parseTranslations(doc),
+		Warnings:     c.warnings,
+	}
+	show.Number, show.Title = parseEpisodeName(name, "")
+	return show, nil
+}
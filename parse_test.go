@@ -0,0 +1,270 @@
+package addic7ed_test
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+func TestParseEpisodePage(t *testing.T) {
+	f, err := os.Open("testdata/episode.html")
+	assert.NoError(t, err)
+	defer f.Close()
+
+	show, err := addic7ed.ParseEpisodePage(f)
+	assert.NoError(t, err)
+	assert.Equal(t, "Shameless (US) - 08x11 - A Gallagher Pedicure", show.Name)
+	assert.Equal(t, addic7ed.EpisodeNumber{Season: 8, Episode: 11}, show.Number)
+	assert.Equal(t, "A Gallagher Pedicure", show.Title)
+	assert.Len(t, show.Subtitles, 4)
+	assert.Len(t, show.Subtitles.Filter(addic7ed.WithVersion("BATV")), 3)
+	assert.Len(t, show.Subtitles.Filter(addic7ed.WithLanguage("French")), 2)
+	assert.Equal(t, "https://www.addic7ed.com/original/131967/0", show.Subtitles[0].Link)
+}
+
+func TestSearchAllEpisodeFromPageURL(t *testing.T) {
+	page, err := os.ReadFile("testdata/episode.html")
+	assert.NoError(t, err)
+	// The name of the episode doesn't give its number, so it is parsed from the URL of the page found from the results
+	page = []byte(strings.Replace(string(page), `titulo">Shameless (US) - 08x11 - A Gallagher Pedicure`, `titulo">Shameless (US)`, 1))
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/srch.php" {
+			w.Write([]byte(`<table class="tabel"><tr><td><a href="/serie/Shameless_(US)/8/11/A_Gallagher_Pedicure">Shameless (US) - 08x11 - A Gallagher Pedicure</a></td></tr></table>`))
+			return
+		}
+		w.Write(page)
+	})
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{handler}}))
+
+	show, err := c.SearchAll("Shameless US 8x11")
+	assert.NoError(t, err)
+	assert.Equal(t, "Shameless (US)", show.Name)
+	assert.Equal(t, addic7ed.EpisodeNumber{Season: 8, Episode: 11}, show.Number)
+	assert.Equal(t, "A Gallagher Pedicure", show.Title)
+}
+
+func TestSearchEpisodeFromPageURL(t *testing.T) {
+	page, err := os.ReadFile("testdata/episode.html")
+	assert.NoError(t, err)
+	page = []byte(strings.Replace(string(page), `titulo">Shameless (US) - 08x11 - A Gallagher Pedicure`, `titulo">Shameless (US)`, 1))
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/srch.php" {
+			w.Write([]byte(`<table class="tabel"><tr><td><a href="/serie/Shameless_(US)/8/12/Church_of_Gay_J%C3%A9sus">Shameless (US) - 08x12</a></td></tr></table>`))
+			return
+		}
+		w.Write(page)
+	})
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{handler}}))
+
+	// The URLs of SearchEpisode end with the ID of the language, which is not a title
+	show, err := c.SearchEpisode("Shameless (US)", 8, 11, "English")
+	assert.NoError(t, err)
+	assert.Equal(t, addic7ed.EpisodeNumber{Season: 8, Episode: 11}, show.Number)
+	assert.Empty(t, show.Title)
+
+	// Titles are unescaped
+	show, err = c.SearchAll("Shameless US 8x12")
+	assert.NoError(t, err)
+	assert.Equal(t, addic7ed.EpisodeNumber{Season: 8, Episode: 12}, show.Number)
+	assert.Equal(t, "Church of Gay Jésus", show.Title)
+}
+
+func TestSearchAllWithNewVersionsPage(t *testing.T) {
+	page, err := os.ReadFile("testdata/episode.html")
+	assert.NoError(t, err)
+	// The versions of the episode are split in two pages, the second one having other links
+	first := strings.Replace(string(page), "</body>", `<a href="/new-versions/131967">New versions available</a></body>`, 1)
+	second := strings.ReplaceAll(string(page), "131967", "131968")
+	var splitFails bool
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/new-versions/131967" {
+			if splitFails {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte(second))
+			return
+		}
+		w.Write([]byte(first))
+	})
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{handler}}))
+
+	single, err := addic7ed.ParseEpisodePage(strings.NewReader(string(page)))
+	assert.NoError(t, err)
+	show, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+	assert.Len(t, show.Subtitles, 2*len(single.Subtitles))
+	assert.NotEmpty(t, show.Subtitles.Filter(func(s addic7ed.Subtitle) bool { return strings.Contains(s.Link, "/original/131968/") }))
+	assert.Empty(t, show.Warnings)
+
+	// The subtitles of the first page are kept when the other page can't be fetched
+	splitFails = true
+	show, err = c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+	assert.Len(t, show.Subtitles, len(single.Subtitles))
+	if assert.Len(t, show.Warnings, 1) {
+		assert.Equal(t, addic7ed.WarningMissingVersions, show.Warnings[0].Code)
+	}
+
+	// Episodes with missing versions are not cached
+	c = addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{handler}}), addic7ed.WithCache(time.Hour, 0))
+	_, err = c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+	splitFails = false
+	show, err = c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+	assert.Len(t, show.Subtitles, 2*len(single.Subtitles))
+}
+
+func TestParseEpisodePageMetadata(t *testing.T) {
+	f, err := os.Open("testdata/episode.html")
+	assert.NoError(t, err)
+	defer f.Close()
+
+	show, err := addic7ed.ParseEpisodePage(f)
+	assert.NoError(t, err)
+	english, french, tbs := show.Subtitles[0], show.Subtitles[1], show.Subtitles[3]
+	assert.True(t, english.IsCompleted())
+	assert.False(t, english.HearingImpaired)
+	assert.Equal(t, 5328, english.Downloads)
+	assert.Equal(t, "elderman", english.Uploader)
+	assert.Equal(t, time.Date(2018, 3, 26, 0, 0, 0, 0, time.UTC), english.UploadedAt)
+
+	assert.True(t, french.HearingImpaired)
+	assert.Equal(t, 1204, french.Downloads)
+
+	assert.False(t, tbs.IsCompleted())
+	assert.Equal(t, 67.19, tbs.Completion)
+	assert.Equal(t, "honeybunny", tbs.Uploader)
+	assert.Equal(t, time.Date(2018, 3, 27, 0, 0, 0, 0, time.UTC), tbs.UploadedAt)
+}
+
+func TestParseEpisodePageWithUnparsedFields(t *testing.T) {
+	page, err := os.ReadFile("testdata/episode.html")
+	assert.NoError(t, err)
+	// The website stopped marking the French subtitle with its language, and the title of the second version
+	changed := strings.Replace(string(page), `<td class="language">French</td>`, `<td>French</td>`, 1)
+	changed = strings.Replace(changed, `<td class="NewsTitle" colspan="3">Version WEB.x264-TBS, 0.00 MBs</td>`, `<td colspan="3">Version WEB.x264-TBS, 0.00 MBs</td>`, 1)
+
+	show, err := addic7ed.ParseEpisodePage(strings.NewReader(changed))
+	assert.NoError(t, err)
+	assert.Len(t, show.Subtitles, 4)
+	for _, s := range show.Subtitles {
+		switch s.Link {
+		case "https://www.addic7ed.com/original/131967/0":
+			assert.Equal(t, "English", s.Language)
+			assert.Empty(t, s.Warnings)
+		case "https://www.addic7ed.com/original/131967/1", "https://www.addic7ed.com/updated/8/131967/1":
+			assert.Empty(t, s.Language)
+			assert.Equal(t, "BATV", s.Version)
+			if assert.Len(t, s.Warnings, 1) {
+				assert.Equal(t, addic7ed.WarningUnparsedField, s.Warnings[0].Code)
+			}
+		default:
+			assert.Equal(t, "English", s.Language)
+			assert.Empty(t, s.Version)
+			assert.Len(t, s.Warnings, 1)
+		}
+	}
+	assert.Len(t, show.Warnings, 2)
+}
+
+func TestParseEpisodePageWithEmptyPage(t *testing.T) {
+	_, err := addic7ed.ParseEpisodePage(strings.NewReader(""))
+	assert.Error(t, err)
+}
+
+func TestCleanVersion(t *testing.T) {
+	assert.Equal(t, "BATV", addic7ed.CleanVersion("Version BATV, 0.00 MBs"))
+	assert.Equal(t, "BATV", addic7ed.CleanVersion("BATV"))
+	assert.Equal(t, "", addic7ed.CleanVersion("  , 0.00 MBs"))
+	assert.Equal(t, "", addic7ed.CleanVersion(""))
+}
+
+func TestWords(t *testing.T) {
+	assert.Equal(t, []string{"Shameless", "US", "S08E11", "720p"}, addic7ed.Words("Shameless.US.S08E11.720p"))
+	assert.Empty(t, addic7ed.Words("...-+"))
+	assert.Equal(t, []string{"Shameless", "第八季第", "11", "集", "720p"}, addic7ed.Words("Shameless第八季第11集.720p"))
+}
+
+func FuzzWords(f *testing.F) {
+	f.Add("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	f.Add("")
+	f.Add("\xff\xfe")
+	f.Add("无耻之徒第八季第11集BATV版")
+	f.Fuzz(func(t *testing.T, s string) {
+		for _, word := range addic7ed.Words(s) {
+			if word == "" || !utf8.ValidString(word) {
+				t.Fatalf("invalid word %q in %q", word, s)
+			}
+		}
+	})
+}
+
+func FuzzCleanVersion(f *testing.F) {
+	f.Add("Version BATV, 0.00 MBs")
+	f.Add(",")
+	f.Add("\xff, \xfe")
+	f.Fuzz(func(t *testing.T, s string) {
+		if clean := addic7ed.CleanVersion(s); strings.TrimSpace(clean) != clean {
+			t.Fatalf("version %q of %q is not clean", clean, s)
+		}
+	})
+}
+
+func FuzzParseVersion(f *testing.F) {
+	f.Add("720p.WEB-DL.DD5.1.H264-NTb")
+	f.Add("web-")
+	f.Add("\xff-\xfe")
+	f.Fuzz(func(t *testing.T, s string) {
+		v := addic7ed.ParseVersion(s)
+		if v.Raw != s {
+			t.Fatalf("raw version %q is not %q", v.Raw, s)
+		}
+	})
+}
+
+func FuzzCanonicalVersion(f *testing.F) {
+	f.Add("720p.WEB-DL.DD5.1.H.264-NTb")
+	f.Add("h.")
+	f.Add("\xff.web\xfe.dl")
+	f.Fuzz(func(t *testing.T, s string) {
+		for _, tag := range strings.Split(addic7ed.CanonicalVersion(s), ".") {
+			if tag != "" && strings.ToLower(tag) != tag {
+				t.Fatalf("tag %q of %q is not canonical", tag, s)
+			}
+		}
+	})
+}
+
+func FuzzParseRelease(f *testing.F) {
+	f.Add("Show.Name.S02E05.720p.WEB.x264-GROUP.mkv")
+	f.Add("Show 2018 - 1x01 [ettv][rartv]")
+	f.Add("\xff.S01E01-")
+	f.Fuzz(func(t *testing.T, s string) {
+		addic7ed.ParseRelease(s)
+	})
+}
+
+func FuzzParseEpisodePage(f *testing.F) {
+	page, err := os.ReadFile("testdata/episode.html")
+	if err != nil {
+		f.Fatal(err)
+	}
+	f.Add(string(page))
+	f.Add("")
+	f.Add(`<span class="titulo"><small></small></span><table class="tabel95" align="center"><td class="language">`)
+	f.Fuzz(func(t *testing.T, s string) {
+		show, err := addic7ed.ParseEpisodePage(strings.NewReader(s))
+		if err == nil && show.Name == "" {
+			t.Fatalf("parsed an episode without name from %q", s)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package addic7ed
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// WithPrefetch checks the subtitles of the next episode in the background after each search of an episode, and caches
+// them, so that binge-watchers searching episodes in order get them right away. Nothing is downloaded.
+// Prefetches are throttled: only one runs at a time, in turn with the other requests, and the next episode is skipped
+// when a prefetch is already running or when it is already cached. It has no effect without WithCache.
+func WithPrefetch() Option {
+	return func(c *Client) {
+		c.prefetching = make(chan struct{}, 1)
+	}
+}
+
+// nextEpisode returns the release of the episode following the episode of a search, or false if the search isn't an episode
+func nextEpisode(showStr string) (Release, bool) {
+	release := ParseRelease(showStr)
+	if !release.HasEpisode() || release.Episode == 0 {
+		return Release{}, false
+	}
+	release.Episode++
+	return release, true
+}
+
+// episodeKey is the key of the episode of a release in the cache, as searched by prefetches
+func episodeKey(showStr string) (string, bool) {
+	release := ParseRelease(showStr)
+	if !release.HasEpisode() {
+		return "", false
+	}
+	return searchKey(release.Query()), true
+}
+
+// prefetched returns the episode of a search prefetched by an earlier search, if it is fresh, see WithPrefetch
+func (c *call) prefetched(showStr string) (Show, bool) {
+	key, ok := episodeKey(showStr)
+	if c.cache == nil || c.prefetching == nil || !ok || key == searchKey(showStr) {
+		return Show{}, false
+	}
+	c.cache.mu.Lock()
+	defer c.cache.mu.Unlock()
+	entry, ok := c.cache.entries[key]
+	if !ok || entry.page != nil || time.Since(entry.fetchedAt) > c.cache.maxAge {
+		return Show{}, false
+	}
+	c.cache.recent.MoveToFront(entry.element)
+	show := entry.show
+	show.Subtitles = slices.Clone(show.Subtitles)
+	show.Translations = slices.Clone(show.Translations)
+	show.Warnings = slices.Clone(show.Warnings)
+	return show, true
+}
+
+// prefetchNext prefetches the episode following the episode of a search in the background, see WithPrefetch
+func (c *call) prefetchNext(showStr string) {
+	if c.cache == nil || c.prefetching == nil {
+		return
+	}
+	next, ok := nextEpisode(showStr)
+	if !ok {
+		return
+	}
+	key := searchKey(next.Query())
+	c.cache.mu.Lock()
+	_, cached := c.cache.entries[key]
+	c.cache.mu.Unlock()
+	if cached {
+		return
+	}
+	select {
+	case c.prefetching <- struct{}{}:
+	default:
+		c.tracef("Episode %v not prefetched, another prefetch is running", next.Query())
+		return
+	}
+	prefetch := c.Client.newCall(context.Background(), nil)
+	prefetch.level = c.level
+	go func() {
+		defer func() { <-c.prefetching }()
+		prefetch.tracef("Prefetching next episode %v in the background", next.Query())
+		show, err := fetchSearch(next.Query())(prefetch)
+		if err != nil {
+			prefetch.infof("Unable to prefetch next episode %v: %v", next.Query(), err)
+			return
+		}
+		if !show.Partial {
+			c.cache.store(key, show)
+		}
+	}()
+}
@@ -0,0 +1,83 @@
+package addic7ed_test
+
+import (
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+// searchLog records the searches sent to a handler
+type searchLog struct {
+	mu       sync.Mutex
+	searches []string
+}
+
+func (l *searchLog) handler(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/srch.php" {
+			l.mu.Lock()
+			l.searches = append(l.searches, r.URL.Query().Get("search"))
+			l.mu.Unlock()
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func (l *searchLog) get() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]string{}, l.searches...)
+}
+
+// waitSearches waits until the handler got n searches
+func (l *searchLog) waitSearches(t *testing.T, n int) {
+	for deadline := time.Now().Add(5 * time.Second); len(l.get()) < n; time.Sleep(time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("got searches %v, expected %v", l.get(), n)
+		}
+	}
+}
+
+func TestWithPrefetch(t *testing.T) {
+	log := &searchLog{}
+	c := addic7ed.New(
+		addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{log.handler(episodeHandler(t, nil))}}),
+		addic7ed.WithCache(time.Hour, 0),
+		addic7ed.WithPrefetch(),
+		// The fixture serves the page of S08E11 for every episode
+		addic7ed.WithEpisodeMismatchWarnings(),
+	)
+
+	_, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv].mkv")
+	assert.NoError(t, err)
+	log.waitSearches(t, 2)
+	// Leave time to the prefetch to parse and cache the episode
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, []string{"Shameless US S08E11", "Shameless US S08E12"}, log.get())
+	assert.Equal(t, 0, c.Usage().Downloads)
+
+	// The next episode is served from the prefetched one, whatever its release, and the one after is prefetched
+	show, err := c.SearchAll("Shameless.US.S08E12.1080p.WEB.x264-TBS.mkv")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, show.Subtitles)
+	log.waitSearches(t, 3)
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, []string{"Shameless US S08E11", "Shameless US S08E12", "Shameless US S08E13"}, log.get())
+}
+
+func TestWithPrefetchWithoutCache(t *testing.T) {
+	log := &searchLog{}
+	c := addic7ed.New(
+		addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{log.handler(episodeHandler(t, nil))}}),
+		addic7ed.WithPrefetch(),
+	)
+	_, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv].mkv")
+	assert.NoError(t, err)
+	time.Sleep(10 * time.Millisecond)
+	assert.Equal(t, []string{"Shameless US S08E11"}, log.get())
+}
@@ -0,0 +1,107 @@
+package addic7ed
+
+import (
+	"context"
+	"errors"
+)
+
+// Provider is a source of subtitles, like Addic7ed with a Client, so that other sources can be chained behind it, see MultiProvider
+type Provider interface {
+	// Search searches the subtitles of an episode in a language, by name or code, like SearchAll.
+	// It returns the name of the episode with its subtitles in the language, or an error like ErrShowNotFound,
+	// ErrNoSubtitlesYet or ErrNoSubtitlesForLanguage when it doesn't have any.
+	Search(ctx context.Context, showStr, lang string) (string, Subtitles, error)
+	// Download downloads a subtitle found by Search
+	Download(ctx context.Context, s Subtitle) (DownloadResult, error)
+}
+
+// Search searches the subtitles of an episode in a language on Addic7ed, see SearchAll and Provider
+func (c *Client) Search(ctx context.Context, showStr, lang string) (string, Subtitles, error) {
+	show, err := c.SearchAllContext(ctx, showStr)
+	if err != nil {
+		return show.Name, nil, err
+	}
+	subtitles := show.Subtitles.Filter(WithLanguage(lang))
+	if len(subtitles) == 0 {
+		return show.Name, nil, newError(CodeNoSubtitlesForLanguage, nil, "Unable to find any subtitles for show %q in %q", show.Name, lang)
+	}
+	return show.Name, subtitles, nil
+}
+
+// Download downloads a subtitle found on Addic7ed, see FetchContext and Provider
+func (c *Client) Download(ctx context.Context, s Subtitle) (DownloadResult, error) {
+	return s.fetch(ctx)
+}
+
+// MultiProvider chains providers: episodes are searched on each provider in order, until one of them has subtitles.
+// A MultiProvider is a Provider itself, and the subtitles it finds are downloaded by the provider that found them,
+// also when downloaded with their own methods like Download or DownloadTo.
+type MultiProvider []Provider
+
+// Search searches the subtitles of an episode on each provider in order, and returns those of the first one that has any.
+// When no provider has subtitles, their errors are joined.
+func (m MultiProvider) Search(ctx context.Context, showStr, lang string) (string, Subtitles, error) {
+	var errs []error
+	for _, provider := range m {
+		name, subtitles, err := provider.Search(ctx, showStr, lang)
+		if err == nil && len(subtitles) > 0 {
+			for i := range subtitles {
+				if subtitles[i].provider == nil {
+					subtitles[i].provider = provider
+				}
+			}
+			return name, subtitles, nil
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", nil, ctxErr
+		}
+		if err == nil {
+			err = newError(CodeNoSubtitlesForLanguage, nil, "Unable to find any subtitles for %q in %q", showStr, lang)
+		}
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		return "", nil, newError(CodeNoSubtitlesForLanguage, nil, "Unable to find any subtitles for %q in %q, no provider is configured", showStr, lang)
+	}
+	return "", nil, errors.Join(errs...)
+}
+
+// Download downloads a subtitle with the provider that found it
+func (m MultiProvider) Download(ctx context.Context, s Subtitle) (DownloadResult, error) {
+	return s.FetchContext(ctx)
+}
+
+// SearchBestFrom searches the subtitles of an episode with a provider, like a MultiProvider falling back on other sources,
+// and picks the best one like SearchBest, with the scorer of the client.
+// When showStr is a video file and the provider supports hashes, see HashSearcher, the subtitles matching the hash of the
+// video are searched first: they are synchronized with the video whatever its name, so they are preferred to the others,
+// flagged MatchedByHash.
+func (c *Client) SearchBestFrom(provider Provider, showStr, lang string, opts ...CallOption) (string, Subtitle, error) {
+	return c.SearchBestFromContext(context.Background(), provider, showStr, lang, opts...)
+}
+
+// SearchBestFromContext is like SearchBestFrom, with a context to cancel the search
+func (c *Client) SearchBestFromContext(ctx context.Context, provider Provider, showStr, lang string, opts ...CallOption) (string, Subtitle, error) {
+	call := c.newCall(ctx, opts)
+	if searcher, ok := provider.(HashSearcher); ok {
+		if hash, err := FileHash(showStr); err == nil {
+			name, subtitles, err := searcher.SearchHash(ctx, hash, lang)
+			if err == nil && len(subtitles) > 0 {
+				call.infof("Found %v subtitles matching the hash %v of %v", len(subtitles), hash, showStr)
+				for i := range subtitles {
+					subtitles[i].MatchedByHash = true
+				}
+				return call.bestOfShow(showStr, lang, Show{Name: name, Subtitles: subtitles})
+			}
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return "", Subtitle{}, ctxErr
+			}
+			call.infof("No subtitle matches the hash %v of %v, searching by name: %v", hash, showStr, err)
+		}
+	}
+	name, subtitles, err := provider.Search(ctx, showStr, lang)
+	if err != nil {
+		return "", Subtitle{}, err
+	}
+	return call.bestOfShow(showStr, lang, Show{Name: name, Subtitles: subtitles})
+}
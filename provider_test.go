@@ -0,0 +1,81 @@
+package addic7ed_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+// fakeProvider is a provider of subtitles for its own shows, downloading them from its content
+type fakeProvider struct {
+	shows     map[string]addic7ed.Subtitles
+	content   string
+	downloads int
+}
+
+func (p *fakeProvider) Search(ctx context.Context, showStr, lang string) (string, addic7ed.Subtitles, error) {
+	for show, subtitles := range p.shows {
+		if strings.Contains(showStr, show) {
+			return show, subtitles.Filter(addic7ed.WithLanguage(lang)), nil
+		}
+	}
+	return "", nil, addic7ed.ErrShowNotFound
+}
+
+func (p *fakeProvider) Download(ctx context.Context, s addic7ed.Subtitle) (addic7ed.DownloadResult, error) {
+	p.downloads++
+	return addic7ed.DownloadResult{Data: []byte(p.content), Format: addic7ed.FormatSRT}, nil
+}
+
+func TestMultiProvider(t *testing.T) {
+	const srt = "1\n00:00:01,000 --> 00:00:02,000\nHello\n"
+	handler := episodeHandler(t, map[string]string{"/original/131967/0": srt, "/updated/131967/0": srt})
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Query().Get("search"), "Other") {
+			w.Write([]byte("<html><body>Nothing found</body></html>"))
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})}}))
+	other := &fakeProvider{content: "other", shows: map[string]addic7ed.Subtitles{
+		"Other.Show.S01E01": {
+			{Language: "English", Version: "LOL", Link: "other://1"},
+			{Language: "English", Version: "KILLERS", Link: "other://2"},
+			{Language: "French", Version: "LOL", Link: "other://3"},
+		},
+	}}
+	providers := addic7ed.MultiProvider{c, other}
+
+	// Addic7ed is searched first
+	name, best, err := c.SearchBestFrom(providers, "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", "English")
+	assert.NoError(t, err)
+	assert.Equal(t, "Shameless (US) - 08x11 - A Gallagher Pedicure", name)
+	assert.Equal(t, "BATV", best.Version)
+	result, err := best.Fetch()
+	assert.NoError(t, err)
+	assert.Equal(t, srt, string(result.Data))
+	assert.Equal(t, 0, other.downloads)
+
+	// Other providers are searched when Addic7ed has nothing, and download their subtitles
+	name, best, err = c.SearchBestFrom(providers, "Other.Show.S01E01.720p.HDTV.x264-KILLERS", "en")
+	assert.NoError(t, err)
+	assert.Equal(t, "Other.Show.S01E01", name)
+	assert.Equal(t, "KILLERS", best.Version)
+	result, err = best.Fetch()
+	assert.NoError(t, err)
+	assert.Equal(t, "other", string(result.Data))
+	_, err = providers.Download(context.Background(), best)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, other.downloads)
+
+	_, _, err = providers.Search(context.Background(), "Other.Unknown.S01E01", "English")
+	assert.True(t, errors.Is(err, addic7ed.ErrShowNotFound), "unexpected error %v", err)
+	_, _, err = providers.Search(context.Background(), "Other.Show.S01E01", "German")
+	assert.True(t, errors.Is(err, addic7ed.ErrNoSubtitlesForLanguage), "unexpected error %v", err)
+}
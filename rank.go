@@ -0,0 +1,75 @@
+package addic7ed
+
+import (
+	"context"
+	"sort"
+)
+
+// ScoredSubtitle is a subtitle with the score of its version for a search, see SearchBestN
+type ScoredSubtitle struct {
+	Subtitle
+	// Score is the score of the version of the subtitle, higher is better, on the scale of the scorer, see WithScorer
+	Score float64
+	// Normalized is the score of the version from 0 to 100, see NormalizedScorer.
+	// With scorers that aren't NormalizedScorers, it is relative to the best score of the search, which gets 100.
+	Normalized float64
+}
+
+// SearchBestN searches in the Addic7ed website for the n best subtitles of a given episode of a show, in a given language.
+// Versions are scored like SearchBest, and the best subtitle of each version is returned, from the best score to the worst one,
+// so that alternatives can be presented when the best subtitle turns out to be out of sync.
+// n of 0 or less returns all versions. It returns the episode name and the scored subtitles.
+func (c *Client) SearchBestN(showStr, lang string, n int, opts ...CallOption) (string, []ScoredSubtitle, error) {
+	return c.SearchBestNContext(context.Background(), showStr, lang, n, opts...)
+}
+
+// SearchBestNContext is like SearchBestN, with a context to cancel the search
+func (c *Client) SearchBestNContext(ctx context.Context, showStr, lang string, n int, opts ...CallOption) (string, []ScoredSubtitle, error) {
+	call := c.newCall(ctx, opts)
+	show, err := call.searchAll(showStr)
+	if err != nil {
+		return "", nil, err
+	}
+	subsWithLang := show.Subtitles.Filter(WithLanguage(lang))
+	if len(subsWithLang) == 0 {
+		return "", nil, newError(CodeNoSubtitlesForLanguage, nil, "Unable to find any subtitles for show %q in %q. Check available languages on Addic7ed website and retry", show.Name, lang)
+	}
+
+	subsByVersion := subsWithLang.GroupByVersion()
+	scores := call.scoreBestSubVersions(showStr, subsByVersion)
+	ranked := make([]ScoredSubtitle, 0, len(subsByVersion))
+	for version, subtitles := range subsByVersion {
+		ranked = append(ranked, ScoredSubtitle{
+			Subtitle:   bestOfVersion(subtitles),
+			Score:      scores[version],
+			Normalized: call.normalizedScore(showStr, version, scores),
+		})
+	}
+	// Versions with the same score are sorted by name, so that results don't change between two runs
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Version < ranked[j].Version
+	})
+	if n > 0 && n < len(ranked) {
+		ranked = ranked[:n]
+	}
+	return show.Name, ranked, nil
+}
+
+// normalizedScore returns the score of a version from 0 to 100, computed by the scorer if it is a NormalizedScorer,
+// or relative to the best of the scores otherwise
+func (c *call) normalizedScore(fileName, version string, scores map[string]float64) float64 {
+	if scorer, ok := c.scorer.(NormalizedScorer); ok {
+		return scorer.NormalizedScore(fileName, version)
+	}
+	var best float64
+	for _, score := range scores {
+		best = max(best, score)
+	}
+	if best <= 0 {
+		return 0
+	}
+	return normalized(scores[version] / best)
+}
@@ -0,0 +1,42 @@
+package addic7ed_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+func TestSearchBestN(t *testing.T) {
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{episodeHandler(t, nil)}}))
+
+	name, ranked, err := c.SearchBestN("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", "English", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "Shameless (US) - 08x11 - A Gallagher Pedicure", name)
+	if assert.Len(t, ranked, 2) {
+		assert.Equal(t, "BATV", ranked[0].Version)
+		assert.Equal(t, "WEB.x264-TBS", ranked[1].Version)
+		assert.True(t, ranked[0].Score > ranked[1].Score)
+		assert.True(t, ranked[0].Normalized <= 100 && ranked[0].Normalized > ranked[1].Normalized)
+	}
+
+	_, ranked, err = c.SearchBestN("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", "French", 1)
+	assert.NoError(t, err)
+	if assert.Len(t, ranked, 1) {
+		assert.True(t, ranked[0].IsUpdated(), "the most updated subtitle of the version is expected")
+	}
+}
+
+func TestSearchBestNNormalizesCustomScorers(t *testing.T) {
+	transport := handlerTransport{episodeHandler(t, nil)}
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}), addic7ed.WithScorer(groupScorer{group: "TBS"}))
+
+	_, ranked, err := c.SearchBestN("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", "English", 0)
+	assert.NoError(t, err)
+	if assert.Len(t, ranked, 2) {
+		assert.Equal(t, 100.0, ranked[0].Normalized)
+		assert.Equal(t, 0.0, ranked[1].Normalized)
+	}
+}
@@ -0,0 +1,152 @@
+package addic7ed
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultRateLimit is the number of requests per minute new clients send at most to Addic7ed, see WithRateLimit
+const DefaultRateLimit = 60
+
+// WithRateLimit limits the number of requests per minute sent to Addic7ed by the client, searches, downloads and login included.
+// Requests are throttled through a token bucket shared by all calls of the client: bursts of up to a sixth of the limit
+// (the requests of 10 seconds) are sent right away, then requests wait for their turn.
+// Aggressive scraping gets IPs banned by Addic7ed, so new clients use DefaultRateLimit. 0 or less disables the limit.
+func WithRateLimit(requestsPerMinute int) Option {
+	return func(c *Client) {
+		c.limiter = newRateLimiter(requestsPerMinute)
+	}
+}
+
+// PeakHours are hours of the day when Addic7ed is the slowest, like right after US prime-time
+type PeakHours struct {
+	// From is the hour the peak starts, from 0 to 23
+	From int
+	// To is the hour the peak ends, excluded, from 1 to 24. A peak ending before it starts spans midnight
+	To int
+	// Location is the time zone of the hours, UTC if nil
+	Location *time.Location
+}
+
+// Contains checks whether a time is in the peak hours
+func (p PeakHours) Contains(t time.Time) bool {
+	loc := p.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+	hour := t.In(loc).Hour()
+	if p.From <= p.To {
+		return hour >= p.From && hour < p.To
+	}
+	return hour >= p.From || hour < p.To
+}
+
+// WithPeakRateLimit applies a stricter limit of requests per minute during peak hours, on top of the limit of WithRateLimit,
+// so that scheduled jobs don't fail when Addic7ed is the slowest. It can be given several times for several peaks.
+// 0 or less is ignored.
+func WithPeakRateLimit(hours PeakHours, requestsPerMinute int) Option {
+	return func(c *Client) {
+		if limiter := newRateLimiter(requestsPerMinute); limiter != nil {
+			c.peakLimiters = append(c.peakLimiters, peakLimiter{hours: hours, limiter: limiter})
+		}
+	}
+}
+
+// WithShowPeakRateLimit is like WithPeakRateLimit for the requests about one show only, like "Shameless (US)",
+// on top of the limits of the client: the searches of its episodes and the pages of its seasons.
+// Shows are matched whatever the case and separators, so "Shameless US" is the same show. Each show has its own limit,
+// so that a show whose episodes are searched right when they air doesn't slow down the others.
+// Any number of shows can have their own limits, the limits being set at creation. A limit of 0 or less is ignored.
+func WithShowPeakRateLimit(show string, hours PeakHours, requestsPerMinute int) Option {
+	return func(c *Client) {
+		key := normalizeShowName(show)
+		limiter := newRateLimiter(requestsPerMinute)
+		if limiter == nil || key == "" {
+			return
+		}
+		if c.showPeakLimiters == nil {
+			c.showPeakLimiters = map[string][]peakLimiter{}
+		}
+		c.showPeakLimiters[key] = append(c.showPeakLimiters[key], peakLimiter{hours: hours, limiter: limiter})
+	}
+}
+
+// peakLimiter limits requests during peak hours
+type peakLimiter struct {
+	hours   PeakHours
+	limiter *rateLimiter
+}
+
+// waitTurn waits until a request can be sent to Addic7ed according to the rate limits of the client, or the context is done.
+// show is the normalized name of the show the request is about, to apply its own limits too, or "" if none.
+func (c *Client) waitTurn(ctx context.Context, show string) error {
+	if err := c.limiter.wait(ctx); err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, peaks := range [][]peakLimiter{c.peakLimiters, c.showPeakLimiters[show]} {
+		for _, peak := range peaks {
+			if !peak.hours.Contains(now) {
+				continue
+			}
+			if err := peak.limiter.wait(ctx); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// rateLimiter is a token bucket, safe for concurrent use. A nil rateLimiter does not limit anything
+type rateLimiter struct {
+	interval time.Duration // interval is the time to get a new token
+	burst    float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+func newRateLimiter(requestsPerMinute int) *rateLimiter {
+	if requestsPerMinute <= 0 {
+		return nil
+	}
+	burst := float64(max(requestsPerMinute/6, 1))
+	return &rateLimiter{
+		interval: time.Minute / time.Duration(requestsPerMinute),
+		burst:    burst,
+		tokens:   burst,
+		last:     time.Now(),
+	}
+}
+
+// wait waits until a request can be sent, or the context is done
+func (l *rateLimiter) wait(ctx context.Context) error {
+	if l == nil {
+		return ctx.Err()
+	}
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = min(l.tokens+float64(now.Sub(l.last))/float64(l.interval), l.burst)
+	l.last = now
+	// The token is taken right away, so that concurrent requests wait for the next ones
+	l.tokens--
+	delay := time.Duration(-l.tokens * float64(l.interval))
+	l.mu.Unlock()
+	if delay <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++ // Give back the token of the cancelled request
+		l.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,109 @@
+package addic7ed_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	var searches int64
+	transport := handlerTransport{countSearches(episodeHandler(t, nil), &searches)}
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}), addic7ed.WithRateLimit(1))
+
+	_, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+
+	// The next request is allowed in a minute
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = c.SearchAllContext(ctx, "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "unexpected error %v", err)
+	assert.Less(t, int64(time.Since(start)), int64(time.Second))
+	assert.Equal(t, int64(1), atomic.LoadInt64(&searches))
+}
+
+func TestWithoutRateLimit(t *testing.T) {
+	var searches int64
+	transport := handlerTransport{countSearches(episodeHandler(t, nil), &searches)}
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}), addic7ed.WithRateLimit(0))
+	for i := 0; i < 2*addic7ed.DefaultRateLimit; i++ {
+		_, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int64(2*addic7ed.DefaultRateLimit), atomic.LoadInt64(&searches))
+}
+
+func TestPeakHours(t *testing.T) {
+	evening := addic7ed.PeakHours{From: 20, To: 23}
+	assert.True(t, evening.Contains(time.Date(2018, 3, 26, 21, 30, 0, 0, time.UTC)))
+	assert.False(t, evening.Contains(time.Date(2018, 3, 26, 23, 0, 0, 0, time.UTC)))
+
+	night := addic7ed.PeakHours{From: 22, To: 2, Location: time.FixedZone("EST", -5*3600)}
+	assert.True(t, night.Contains(time.Date(2018, 3, 26, 4, 0, 0, 0, time.UTC)))  // 23:00 EST
+	assert.False(t, night.Contains(time.Date(2018, 3, 26, 8, 0, 0, 0, time.UTC))) // 03:00 EST
+}
+
+func TestWithPeakRateLimit(t *testing.T) {
+	var searches int64
+	transport := handlerTransport{countSearches(episodeHandler(t, nil), &searches)}
+	offPeak := addic7ed.PeakHours{From: (time.Now().UTC().Hour() + 2) % 24, To: (time.Now().UTC().Hour() + 3) % 24}
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}),
+		addic7ed.WithPeakRateLimit(addic7ed.PeakHours{From: 0, To: 24}, 1),
+		addic7ed.WithPeakRateLimit(offPeak, 1))
+
+	_, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+
+	// During the peak, the next request is allowed in a minute
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = c.SearchAllContext(ctx, "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "unexpected error %v", err)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&searches))
+
+	// Off-peak, only the limit of the client applies
+	c = addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}), addic7ed.WithPeakRateLimit(offPeak, 1))
+	for i := 0; i < 3; i++ {
+		_, err = c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int64(4), atomic.LoadInt64(&searches))
+}
+
+func TestWithShowPeakRateLimit(t *testing.T) {
+	var searches int64
+	transport := handlerTransport{countSearches(episodeHandler(t, nil), &searches)}
+	opts := []addic7ed.Option{addic7ed.WithHTTPClient(&http.Client{Transport: transport})}
+	// The limits of a show are kept whatever the number of shows with their own limits
+	for i := 0; i < 200; i++ {
+		opts = append(opts, addic7ed.WithShowPeakRateLimit(fmt.Sprintf("Show %v", i), addic7ed.PeakHours{From: 0, To: 24}, 1))
+	}
+	c := addic7ed.New(append(opts, addic7ed.WithShowPeakRateLimit("Shameless (US)", addic7ed.PeakHours{From: 0, To: 24}, 1))...)
+
+	_, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+
+	// During the peak, the next request about the show is allowed in a minute
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = c.SearchAllContext(ctx, "Shameless.US.S08E12.720p.HDTV.x264-BATV[ettv]")
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "unexpected error %v", err)
+	assert.Equal(t, int64(1), atomic.LoadInt64(&searches))
+
+	// Other shows only have the limits of the client
+	for i := 0; i < 3; i++ {
+		_, err = c.SearchAll("Other.Show.S08E11.720p.HDTV.x264-BATV[ettv]")
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int64(4), atomic.LoadInt64(&searches))
+}
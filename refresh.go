@@ -0,0 +1,55 @@
+package addic7ed
+
+import "context"
+
+// Refresh re-resolves the download link of the subtitle from the page it was found on, see Page, to download it afterwards.
+// Links of old subtitles, like subtitles stored by applications, may expire or change when the subtitle is updated: the
+// subtitle of the same language and version is looked for on the page, preferring the same link, then the same variant
+// (original or updated). The page is fetched with the client, the client that found the subtitle when nil.
+// It returns ErrNoSubtitlesForLanguage if the subtitle is no longer on the page.
+func (s Subtitle) Refresh(ctx context.Context, c *Client) (Subtitle, error) {
+	if c == nil {
+		c = s.client
+	}
+	if c == nil {
+		c = DefaultClient()
+	}
+	if s.Page == "" {
+		return Subtitle{}, newError(CodeParseFailure, nil, "subtitle %v can't be refreshed, the page it was found on is unknown", s.Link)
+	}
+	call := c.newCall(ctx, nil)
+	doc, err := call.createDocFromURL(s.Page)
+	if err != nil {
+		return Subtitle{}, err
+	}
+	subtitles := Subtitles{}
+	err = call.parseAllSubtitles(doc, func(subtitle Subtitle) bool {
+		subtitles = append(subtitles, subtitle)
+		return true
+	})
+	if err != nil {
+		call.warnf("Unable to fetch the other versions of page %v: %v", s.Page, err)
+	}
+
+	wanted := s.parsedVersion()
+	var best *Subtitle
+	for i, candidate := range subtitles {
+		if candidate.Language != s.Language || !candidate.parsedVersion().Equal(wanted) {
+			continue
+		}
+		if candidate.Link == s.Link {
+			best = &subtitles[i]
+			break
+		}
+		if best == nil || (best.IsUpdated() != s.IsUpdated() && candidate.IsUpdated() == s.IsUpdated()) {
+			best = &subtitles[i]
+		}
+	}
+	if best == nil {
+		return Subtitle{}, newError(CodeNoSubtitlesForLanguage, nil, "version %v of subtitle %v in %v is no longer on page %v", s.Version, s.Link, s.Language, s.Page)
+	}
+	if best.Link != s.Link {
+		call.infof("Link of subtitle %v refreshed to %v", s.Link, best.Link)
+	}
+	return *best, nil
+}
@@ -0,0 +1,62 @@
+package addic7ed_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+func TestSubtitleRefresh(t *testing.T) {
+	page, err := os.ReadFile("testdata/episode.html")
+	assert.NoError(t, err)
+	var current atomic.Value
+	current.Store(string(page))
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(current.Load().(string)))
+	})}}))
+
+	show, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+	french := show.Subtitles.Filter(func(s addic7ed.Subtitle) bool { return s.Language == "French" && s.IsUpdated() })
+	if !assert.Len(t, french, 1) {
+		return
+	}
+	assert.Contains(t, french[0].Page, "/srch.php?search=")
+
+	// The updated French subtitle was updated again, with another link
+	current.Store(strings.Replace(string(page), `href="/updated/8/131967/1"`, `href="/updated/8/131967/5"`, 1))
+	refreshed, err := french[0].Refresh(context.Background(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "https://www.addic7ed.com/updated/8/131967/5", refreshed.Link)
+	assert.Equal(t, "BATV", refreshed.Version)
+
+	// Unchanged links are kept
+	english := show.Subtitles.Filter(addic7ed.WithLanguage("English")).Filter(addic7ed.WithVersion("BATV"))
+	refreshed, err = english[0].Refresh(context.Background(), c)
+	assert.NoError(t, err)
+	assert.Equal(t, english[0].Link, refreshed.Link)
+
+	current.Store(strings.Replace(string(page), `<td class="language">French</td>`, `<td class="language">Italian</td>`, 1))
+	_, err = french[0].Refresh(context.Background(), c)
+	assert.True(t, errors.Is(err, addic7ed.ErrNoSubtitlesForLanguage), "unexpected error %v", err)
+
+	_, err = addic7ed.Subtitle{Link: "https://www.addic7ed.com/original/1/0"}.Refresh(context.Background(), c)
+	assert.Error(t, err)
+}
+
+func TestSeasonSubtitlesPage(t *testing.T) {
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{showHandler(t)}}))
+	episodes, err := c.GetEpisodes(addic7ed.TVShow{ID: "5427", Name: "Shameless (US)"}, 8)
+	assert.NoError(t, err)
+	if assert.NotEmpty(t, episodes) {
+		assert.Equal(t, "https://www.addic7ed.com/serie/Shameless_(US)/8/11/A_Gallagher_Pedicure", episodes[0].Subtitles[0].Page)
+	}
+}
@@ -0,0 +1,105 @@
+package addic7ed
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Release is a TV show release, as described by the name of its video file
+type Release struct {
+	// Title is the title of the show, like "Shameless US"
+	Title string
+	// Season and Episode are the numbers of the episode, or 0 if not found
+	Season  int
+	Episode int
+	// Year is the year of the show, or 0 if not found. It is usually given to distinguish reboots, like "Magnum.P.I.2018"
+	Year int
+	// Resolution is the resolution of the release, like "720p"
+	Resolution string
+	// Source is the source of the release, like "WEB-DL", "HDTV" or "BluRay"
+	Source string
+	// Group is the team who ripped the release, like "BATV"
+	Group string
+}
+
+// videoExtensions are the extensions of video files, removed from filenames before parsing them
+var videoExtensions = map[string]bool{
+	".mkv": true, ".mp4": true, ".avi": true, ".m4v": true, ".mov": true, ".wmv": true, ".mpg": true, ".mpeg": true, ".ts": true, ".webm": true,
+}
+
+// IsVideo checks whether a file is a video, from its extension, like the files watched by Watcher
+func IsVideo(file string) bool {
+	return videoExtensions[strings.ToLower(filepath.Ext(file))]
+}
+
+// trailingTagRegexp matches the tags appended by release sites, like "[ettv]" or "[rartv]"
+var trailingTagRegexp = regexp.MustCompile(`\[[^\]]*\]\s*$`)
+
+// ParseRelease parses the name of the video file of a TV show release, like "Show.Name.S02E05.720p.WEB.x264-GROUP.mkv"
+// Fields that can't be found are left empty. It never panics, whatever the input.
+func ParseRelease(filename string) Release {
+	name := filepath.Base(filename)
+	if IsVideo(name) {
+		name = strings.TrimSuffix(name, filepath.Ext(name))
+	}
+	for trailingTagRegexp.MatchString(name) {
+		name = trailingTagRegexp.ReplaceAllString(name, "")
+	}
+
+	var release Release
+	titlePart, tagsPart := name, ""
+	if number, loc, ok := findEpisodeNumber(name); ok {
+		release.Season, release.Episode = number.Season, number.Episode
+		titlePart, tagsPart = name[:loc[0]], name[loc[1]:]
+	}
+
+	titleWords := Words(titlePart)
+	if tagsPart == "" {
+		// Without episode number, the title stops at the first known tag
+		for i, word := range titleWords {
+			if i > 0 && isReleaseTag(word) {
+				titleWords, tagsPart = titleWords[:i], strings.Join(titleWords[i:], ".")
+				break
+			}
+		}
+	}
+	// The year is given after the title, the first word being part of the title like in "1883"
+	if n := len(titleWords); n > 1 && yearRegexp.MatchString(titleWords[n-1]) {
+		release.Year, _ = strconv.Atoi(titleWords[n-1])
+		titleWords = titleWords[:n-1]
+	}
+	release.Title = strings.Join(titleWords, " ")
+
+	version := ParseVersion(tagsPart)
+	release.Resolution = version.Resolution
+	release.Source = version.Source
+	release.Group = version.Group
+	return release
+}
+
+// isReleaseTag checks whether a word is a known tag of release names, like a resolution or a source
+func isReleaseTag(word string) bool {
+	lowered := strings.ToLower(word)
+	return sources[lowered] != "" || flags[lowered] || resolutionRegexp.MatchString(word)
+}
+
+// HasEpisode checks whether the release is a known episode of a show
+func (r Release) HasEpisode() bool {
+	return r.Title != "" && (r.Season > 0 || r.Episode > 0)
+}
+
+// EpisodeNumber returns the season and episode numbers of the release
+func (r Release) EpisodeNumber() EpisodeNumber {
+	return EpisodeNumber{Season: r.Season, Episode: r.Episode}
+}
+
+// Query returns the search to send to Addic7ed to find the episode, like "Shameless US S08E11"
+func (r Release) Query() string {
+	if r.Year > 0 {
+		return fmt.Sprintf("%v %v %v", r.Title, r.Year, r.EpisodeNumber())
+	}
+	return fmt.Sprintf("%v %v", r.Title, r.EpisodeNumber())
+}
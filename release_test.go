@@ -0,0 +1,49 @@
+package addic7ed_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+func TestParseRelease(t *testing.T) {
+	var releasetests = []struct {
+		in       string
+		expected addic7ed.Release
+	}{
+		{"Show.Name.S02E05.720p.WEB.x264-GROUP.mkv", addic7ed.Release{Title: "Show Name", Season: 2, Episode: 5, Resolution: "720p", Source: "WEB", Group: "GROUP"}},
+		{"Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", addic7ed.Release{Title: "Shameless US", Season: 8, Episode: 11, Resolution: "720p", Source: "HDTV", Group: "BATV"}},
+		{"The Big Bang Theory - 06x12 - Web-dl 480p", addic7ed.Release{Title: "The Big Bang Theory", Season: 6, Episode: 12, Resolution: "480p", Source: "WEB-DL"}},
+		{"/videos/Magnum.P.I.2018.S01E01.1080p.BluRay.x264-DEMAND.mp4", addic7ed.Release{Title: "Magnum P I", Year: 2018, Season: 1, Episode: 1, Resolution: "1080p", Source: "BluRay", Group: "DEMAND"}},
+		{"Show.Name.S02E05.720p.HDTV-GROUP.mpg", addic7ed.Release{Title: "Show Name", Season: 2, Episode: 5, Resolution: "720p", Source: "HDTV", Group: "GROUP"}},
+		{"1883.S01E01.720p.WEB-DL", addic7ed.Release{Title: "1883", Season: 1, Episode: 1, Resolution: "720p", Source: "WEB-DL"}},
+		{"Some.Movie.1080p.BluRay", addic7ed.Release{Title: "Some Movie", Resolution: "1080p", Source: "BluRay"}},
+		{"", addic7ed.Release{}},
+	}
+
+	for _, test := range releasetests {
+		assert.Equal(t, test.expected, addic7ed.ParseRelease(test.in), test.in)
+	}
+}
+
+func TestIsVideoAndIsSubtitle(t *testing.T) {
+	for _, file := range []string{"Show.S01E01.mkv", "Show.S01E01.MPEG", "/videos/Show.S01E01.mpg"} {
+		assert.True(t, addic7ed.IsVideo(file), file)
+		assert.False(t, addic7ed.IsSubtitle(file), file)
+	}
+	for _, file := range []string{"Show.S01E01.srt", "Show.S01E01.en.VTT", "/videos/Show.S01E01.ass"} {
+		assert.True(t, addic7ed.IsSubtitle(file), file)
+		assert.False(t, addic7ed.IsVideo(file), file)
+	}
+	assert.False(t, addic7ed.IsVideo("Show.S01E01.nfo"))
+	assert.False(t, addic7ed.IsSubtitle("Show.S01E01.nfo"))
+}
+
+func TestReleaseQuery(t *testing.T) {
+	release := addic7ed.ParseRelease("Magnum.P.I.2018.S01E01.1080p.BluRay.x264-DEMAND")
+	assert.True(t, release.HasEpisode())
+	assert.Equal(t, "Magnum P I 2018 S01E01", release.Query())
+	assert.False(t, addic7ed.ParseRelease("Some.Movie.1080p.BluRay").HasEpisode())
+}
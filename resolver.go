@@ -0,0 +1,181 @@
+package addic7ed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ShowResolver resolves the title of a show as found in a release name, like "Brooklyn Nine Nine", to its canonical name
+// and year, like "Brooklyn Nine-Nine" and 2013, from a database of shows like TMDB, see WithShowResolver
+type ShowResolver interface {
+	// ResolveShow resolves the title of a show, with its year when known from the release, or 0.
+	// It returns an error when the show is unknown.
+	ResolveShow(ctx context.Context, title string, year int) (name string, resolvedYear int, err error)
+}
+
+// ShowResolverFunc is a function resolving the titles of shows, see ShowResolver
+type ShowResolverFunc func(ctx context.Context, title string, year int) (string, int, error)
+
+// ResolveShow calls the function
+func (f ShowResolverFunc) ResolveShow(ctx context.Context, title string, year int) (string, int, error) {
+	return f(ctx, title, year)
+}
+
+// WithShowResolver resolves the titles of the shows of release names to their canonical names before searching them,
+// so that titles spelled differently than on Addic7ed, like "Brooklyn Nine Nine" for "Brooklyn Nine-Nine", find the right show.
+// The release is searched as is when the resolver fails or when its resolved episode is not found.
+//
+//	c := addic7ed.New(addic7ed.WithShowResolver(addic7ed.NewTMDBResolver(apiKey)))
+func WithShowResolver(resolver ShowResolver) Option {
+	return func(c *Client) {
+		c.resolver = resolver
+	}
+}
+
+// resolveRelease resolves the title of the show of a release with the resolver of the client.
+// It returns false when the client has no resolver, when the resolution fails or when it doesn't change the title.
+// The resolved year is only kept for releases giving a year, as Addic7ed only adds years to the names of reboots.
+func (c *call) resolveRelease(release Release) (Release, bool) {
+	if c.resolver == nil {
+		return release, false
+	}
+	name, year, err := c.resolver.ResolveShow(c.ctx, release.Title, release.Year)
+	if err != nil {
+		c.warnf("Unable to resolve show %v: %v", release.Title, err)
+		return release, false
+	}
+	resolved := release
+	resolved.Title = name
+	if release.Year > 0 && year > 0 {
+		resolved.Year = year
+	}
+	if resolved.Query() == release.Query() {
+		return release, false
+	}
+	c.infof("Show %v resolved to %v (%v)", release.Title, name, year)
+	return resolved, true
+}
+
+// DefaultTMDBURL is the URL of the API of TMDB, used by new TMDB resolvers
+const DefaultTMDBURL = "https://api.themoviedb.org/3"
+
+// maxTMDBResolutions is the number of resolutions kept by TMDB resolvers, forgotten all at once when it is reached
+const maxTMDBResolutions = 1000
+
+// TMDBResolver resolves the titles of shows with the search of TV shows of TMDB (The Movie Database), see ShowResolver.
+// Resolutions, including the titles not found on TMDB, are kept for the life of the resolver, so that searching the
+// episodes of a show only asks TMDB once.
+type TMDBResolver struct {
+	// APIKey is the API key of TMDB, or its API read access token
+	APIKey string
+	// URL is the URL of the API of TMDB
+	URL string
+	// HTTPClient sends the requests to TMDB, http.DefaultClient when nil
+	HTTPClient *http.Client
+
+	mu          sync.Mutex
+	resolutions map[string]tmdbResolution
+}
+
+// tmdbResolution is a resolution of a title by TMDB, kept by the resolver
+type tmdbResolution struct {
+	name string
+	year int
+	err  error
+}
+
+// NewTMDBResolver creates a resolver of the titles of shows with TMDB, with an API key or an API read access token
+func NewTMDBResolver(apiKey string) *TMDBResolver {
+	return &TMDBResolver{APIKey: apiKey, URL: DefaultTMDBURL}
+}
+
+// tmdbSearch is the part of the results of a search of TV shows of TMDB used to resolve titles
+type tmdbSearch struct {
+	Results []struct {
+		Name         string `json:"name"`
+		FirstAirDate string `json:"first_air_date"`
+	} `json:"results"`
+	StatusMessage string `json:"status_message"`
+}
+
+// ResolveShow resolves the title of a show to the name and year of the first air date of the best match of TMDB.
+// The year of the release, when known, restricts the search to the shows first aired that year.
+func (r *TMDBResolver) ResolveShow(ctx context.Context, title string, year int) (string, int, error) {
+	key := fmt.Sprintf("%v|%v", normalizeShowName(title), year)
+	r.mu.Lock()
+	resolution, ok := r.resolutions[key]
+	r.mu.Unlock()
+	if ok {
+		return resolution.name, resolution.year, resolution.err
+	}
+	name, resolvedYear, err := r.search(ctx, title, year)
+	// Failures other than unknown shows are usually transient, so they aren't kept
+	if err == nil || errors.Is(err, ErrShowNotFound) {
+		r.mu.Lock()
+		if r.resolutions == nil || len(r.resolutions) >= maxTMDBResolutions {
+			r.resolutions = map[string]tmdbResolution{}
+		}
+		r.resolutions[key] = tmdbResolution{name: name, year: resolvedYear, err: err}
+		r.mu.Unlock()
+	}
+	return name, resolvedYear, err
+}
+
+// search searches the title of a show on TMDB
+func (r *TMDBResolver) search(ctx context.Context, title string, year int) (string, int, error) {
+	query := url.Values{"query": {title}}
+	if year > 0 {
+		query.Set("first_air_date_year", strconv.Itoa(year))
+	}
+	// API read access tokens are JWTs, sent as bearer tokens, while API keys are sent in the query
+	isToken := strings.Count(r.APIKey, ".") == 2
+	if !isToken {
+		query.Set("api_key", r.APIKey)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(r.URL, "/")+"/search/tv?"+query.Encode(), nil)
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if isToken {
+		req.Header.Set("Authorization", "Bearer "+r.APIKey)
+	}
+	httpClient := r.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		// The URL of the request is left out of the error, as it holds the API key
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return "", 0, fmt.Errorf("unable to reach TMDB: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var search tmdbSearch
+	if err := json.NewDecoder(resp.Body).Decode(&search); err != nil {
+		return "", 0, fmt.Errorf("invalid answer of TMDB with status %v: %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("TMDB answered with status %v: %v", resp.StatusCode, search.StatusMessage)
+	}
+	if len(search.Results) == 0 || search.Results[0].Name == "" {
+		return "", 0, newError(CodeShowNotFound, nil, "show %v not found on TMDB", title)
+	}
+	best := search.Results[0]
+	resolvedYear := 0
+	if len(best.FirstAirDate) >= 4 {
+		resolvedYear, _ = strconv.Atoi(best.FirstAirDate[:4])
+	}
+	return best.Name, resolvedYear, nil
+}
@@ -0,0 +1,132 @@
+package addic7ed_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/matcornic/addic7ed"
+	"github.com/stretchr/testify/assert"
+)
+
+// onlySearch serves the episode only to a search, and an empty page to the others
+func onlySearch(t *testing.T, search string, searches *[]string) http.Handler {
+	handler := episodeHandler(t, nil)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/srch.php" {
+			*searches = append(*searches, r.URL.Query().Get("search"))
+			if r.URL.Query().Get("search") != search {
+				return
+			}
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func TestWithShowResolver(t *testing.T) {
+	var searches []string
+	var resolved []string
+	resolver := addic7ed.ShowResolverFunc(func(ctx context.Context, title string, year int) (string, int, error) {
+		resolved = append(resolved, title)
+		return "Shameless (US)", 2011, nil
+	})
+	c := addic7ed.New(
+		addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{onlySearch(t, "Shameless (US) S08E11", &searches)}}),
+		addic7ed.WithShowResolver(resolver),
+	)
+
+	show, err := c.SearchAll("Shameless.S08E11.720p.HDTV.x264-BATV[ettv].mkv")
+	assert.NoError(t, err)
+	assert.Equal(t, "Shameless (US) - 08x11 - A Gallagher Pedicure", show.Name)
+	assert.Equal(t, []string{"Shameless"}, resolved)
+	// The year isn't added to releases without year
+	assert.Equal(t, []string{"Shameless (US) S08E11"}, searches)
+}
+
+func TestWithShowResolverFallback(t *testing.T) {
+	var searches []string
+	resolver := addic7ed.ShowResolverFunc(func(ctx context.Context, title string, year int) (string, int, error) {
+		return "", 0, errors.New("unavailable")
+	})
+	c := addic7ed.New(
+		addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{onlySearch(t, "Shameless US S08E11", &searches)}}),
+		addic7ed.WithShowResolver(resolver),
+	)
+	_, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv].mkv")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Shameless US S08E11"}, searches)
+
+	// Resolved episodes that are not found are searched as is
+	searches = nil
+	resolver = func(ctx context.Context, title string, year int) (string, int, error) {
+		return "Shameless", 2004, nil
+	}
+	c = addic7ed.New(
+		addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{onlySearch(t, "Shameless US S08E11", &searches)}}),
+		addic7ed.WithShowResolver(resolver),
+	)
+	_, err = c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv].mkv")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Shameless S08E11", "Shameless US S08E11"}, searches)
+}
+
+func TestTMDBResolver(t *testing.T) {
+	var requests []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r)
+		switch r.URL.Query().Get("query") {
+		case "Brooklyn Nine Nine":
+			w.Write([]byte(`{"page":1,"results":[{"id":48891,"name":"Brooklyn Nine-Nine","first_air_date":"2013-09-17"},{"name":"Other"}]}`))
+		case "Invalid":
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"status_code":7,"status_message":"Invalid API key: You must be granted a valid key.","success":false}`))
+		default:
+			w.Write([]byte(`{"page":1,"results":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	resolver := addic7ed.NewTMDBResolver("key")
+	resolver.URL = server.URL
+	name, year, err := resolver.ResolveShow(context.Background(), "Brooklyn Nine Nine", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "Brooklyn Nine-Nine", name)
+	assert.Equal(t, 2013, year)
+	assert.Equal(t, "/search/tv", requests[0].URL.Path)
+	assert.Equal(t, "key", requests[0].URL.Query().Get("api_key"))
+	assert.Empty(t, requests[0].URL.Query().Get("first_air_date_year"))
+
+	// Years restrict the search, and read access tokens are sent as bearer tokens
+	resolver.APIKey = "header.payload.signature"
+	_, _, err = resolver.ResolveShow(context.Background(), "Brooklyn Nine Nine", 2013)
+	assert.NoError(t, err)
+	assert.Equal(t, "2013", requests[1].URL.Query().Get("first_air_date_year"))
+	assert.Empty(t, requests[1].URL.Query().Get("api_key"))
+	assert.Equal(t, "Bearer header.payload.signature", requests[1].Header.Get("Authorization"))
+
+	_, _, err = resolver.ResolveShow(context.Background(), "Unknown", 0)
+	assert.True(t, errors.Is(err, addic7ed.ErrShowNotFound), "unexpected error %v", err)
+
+	_, _, err = resolver.ResolveShow(context.Background(), "Invalid", 0)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid API key")
+
+	// Resolutions are kept, including unknown shows
+	count := len(requests)
+	name, _, err = resolver.ResolveShow(context.Background(), "Brooklyn.Nine.Nine", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, "Brooklyn Nine-Nine", name)
+	_, _, err = resolver.ResolveShow(context.Background(), "Unknown", 0)
+	assert.True(t, errors.Is(err, addic7ed.ErrShowNotFound), "unexpected error %v", err)
+	assert.Len(t, requests, count)
+}
+
+func TestTMDBResolverHidesAPIKey(t *testing.T) {
+	resolver := addic7ed.NewTMDBResolver("secret")
+	resolver.URL = "http://127.0.0.1:1"
+	_, _, err := resolver.ResolveShow(context.Background(), "Brooklyn Nine Nine", 0)
+	assert.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret")
+}
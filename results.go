@@ -0,0 +1,94 @@
+package addic7ed
+
+import (
+	"regexp"
+	"strings"
+)
+
+// searchResult is a result of the search page of Addic7ed, when the search matches multiple shows
+type searchResult struct {
+	link  string
+	title string
+}
+
+// countries are the country codes Addic7ed adds to show names to distinguish shows of the same name, like "The Office (US)"
+var countries = map[string]bool{
+	"us": true, "uk": true, "au": true, "nz": true, "ca": true, "ie": true,
+}
+
+// countryHint finds the country code given in a search, like "US" in "Shameless.US.S08E11"
+// Only the words before the episode number are considered, as release names often contain other uppercase tags.
+func countryHint(search string) string {
+	if _, loc, ok := findEpisodeNumber(search); ok {
+		search = search[:loc[0]]
+	}
+	words := Words(search)
+	// The first word is part of the name of the show
+	for i := len(words) - 1; i > 0; i-- {
+		if countries[strings.ToLower(words[i])] {
+			return strings.ToUpper(words[i])
+		}
+	}
+	return ""
+}
+
+var (
+	yearRegexp      = regexp.MustCompile(`^(19|20)\d{2}$`)
+	titleYearRegexp = regexp.MustCompile(`\((19|20)\d{2}\)`)
+)
+
+// yearHint finds the year given in a search, like "2018" in "Magnum.P.I.2018.S01E01"
+// Only the words before the episode number are considered.
+func yearHint(search string) string {
+	if _, loc, ok := findEpisodeNumber(search); ok {
+		search = search[:loc[0]]
+	}
+	words := Words(search)
+	// The first word is part of the name of the show, like in "1883"
+	for i := len(words) - 1; i > 0; i-- {
+		if yearRegexp.MatchString(words[i]) {
+			return words[i]
+		}
+	}
+	return ""
+}
+
+// filterResults keeps results matching the given function, or all results if none matches
+func filterResults(results []searchResult, keep func(r searchResult) bool) []searchResult {
+	kept := []searchResult{}
+	for _, result := range results {
+		if keep(result) {
+			kept = append(kept, result)
+		}
+	}
+	if len(kept) == 0 {
+		return results
+	}
+	return kept
+}
+
+// pickResult picks the result of a search page matching the hints found in the search, like the country or the year of the show
+// Without year in the search, original shows are preferred to their reboots, named with their year like "Magnum P.I. (2018)".
+// The first result is picked when no result matches the hints.
+func (c *call) pickResult(results []searchResult, search string) searchResult {
+	if country := countryHint(search); country != "" {
+		c.infof("Search %v is about a show from %v", search, country)
+		results = filterResults(results, func(r searchResult) bool {
+			return strings.Contains(strings.ToUpper(r.title), "("+country+")")
+		})
+	}
+	if year := yearHint(search); year != "" {
+		c.infof("Search %v is about a show from %v", search, year)
+		results = filterResults(results, func(r searchResult) bool {
+			return strings.Contains(r.title, year)
+		})
+	} else {
+		results = filterResults(results, func(r searchResult) bool {
+			return !titleYearRegexp.MatchString(r.title)
+		})
+	}
+	if len(results) > 1 {
+		c.warn(WarningAmbiguousShow, "search %v matches multiple shows, picked %v", search, results[0].title)
+	}
+	return results[0]
+}
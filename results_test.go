@@ -0,0 +1,82 @@
+package addic7ed_test
+
+import (
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+// resultsHandler serves a search results page for every search, and the episode fixture for the pages of the results.
+// The paths of the pages of the results are recorded in picked.
+func resultsHandler(t *testing.T, results []byte, picked *[]string) http.Handler {
+	page, err := os.ReadFile("testdata/episode.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/srch.php" {
+			w.Write(results)
+			return
+		}
+		mu.Lock()
+		*picked = append(*picked, r.URL.Path)
+		mu.Unlock()
+		w.Write(page)
+	})
+}
+
+func TestSearchResultsOfCountries(t *testing.T) {
+	results, err := os.ReadFile("testdata/search.html")
+	assert.NoError(t, err)
+	var picked []string
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{resultsHandler(t, results, &picked)}}))
+
+	// The country of the filename picks the show
+	show, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/show/5427"}, picked)
+	assert.Empty(t, show.Warnings)
+
+	// Without country, the first result is picked with a warning
+	picked = nil
+	show, err = c.SearchAll("Shameless.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/show/1029"}, picked)
+	if assert.Len(t, show.Warnings, 1) {
+		assert.Equal(t, addic7ed.WarningAmbiguousShow, show.Warnings[0].Code)
+		assert.Contains(t, show.Warnings[0].Message, "Shameless (UK)")
+	}
+}
+
+func TestSearchResultsOfReboots(t *testing.T) {
+	results := []byte(`<table class="tabel">
+	<tr><td><a href="/show/5938">Magnum P.I. (2018)</a></td></tr>
+	<tr><td><a href="/show/1375">Magnum, P.I.</a></td></tr>
+	</table>`)
+	var picked []string
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{resultsHandler(t, results, &picked)}}),
+		addic7ed.WithEpisodeMismatchWarnings())
+
+	// The year of the filename picks the reboot
+	show, err := c.SearchAll("Magnum.P.I.2018.S01E01.1080p.BluRay.x264-DEMAND")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/show/5938"}, picked)
+	for _, warning := range show.Warnings {
+		assert.NotEqual(t, addic7ed.WarningAmbiguousShow, warning.Code)
+	}
+
+	// Without year, the original show is picked
+	picked = nil
+	show, err = c.SearchAll("Magnum.P.I.S01E01.1080p.BluRay.x264-DEMAND")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"/show/1375"}, picked)
+	for _, warning := range show.Warnings {
+		assert.NotEqual(t, addic7ed.WarningAmbiguousShow, warning.Code)
+	}
+}
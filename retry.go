@@ -0,0 +1,135 @@
+package addic7ed
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RetryPolicy configures how requests failing with a transient error are retried.
+// Requests are retried when Addic7ed can't be reached, times out, or answers with a 429 or 5xx status.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of attempts of a request, including the first one. 1 or less disables retries
+	MaxAttempts int
+	// InitialBackoff is the delay before the first retry. It doubles after each retry, up to MaxBackoff
+	InitialBackoff time.Duration
+	// MaxBackoff is the maximum delay between two attempts, also capping the Retry-After header of 429 answers
+	MaxBackoff time.Duration
+	// Jitter is the fraction of the delay randomly removed, from 0 to 1, so that concurrent clients don't retry at the same time
+	Jitter float64
+}
+
+// DefaultRetryPolicy is the retry policy of new clients
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 500 * time.Millisecond,
+	MaxBackoff:     5 * time.Second,
+	Jitter:         0.5,
+}
+
+// WithRetry sets the policy used to retry searches and downloads failing with a transient error, see RetryPolicy.
+// New clients use DefaultRetryPolicy.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithoutRetry disables retries: requests failing with a transient error fail right away
+func WithoutRetry() Option {
+	return WithRetry(RetryPolicy{MaxAttempts: 1})
+}
+
+// do sends a request without body, retrying it according to the retry policy of the client.
+// show is the normalized name of the show the request is about, for its rate limits, or "" if none.
+// onRetry is called, if not nil, before each retry with the reason of the retry.
+func (c *Client) do(req *http.Request, show string, onRetry func(reason string, delay time.Duration)) (*http.Response, error) {
+	backoff := c.retry.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		if err := c.waitTurn(req.Context(), show); err != nil {
+			return nil, err
+		}
+		resp, err := c.httpClient.Do(req)
+		retryable := (err != nil && req.Context().Err() == nil) || (err == nil && isTransientStatus(resp.StatusCode))
+		if !retryable || attempt >= c.retry.MaxAttempts {
+			return resp, err
+		}
+
+		delay := c.retry.delay(backoff, c.random())
+		reason := ""
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = resp.Status
+			if after, ok := retryAfter(resp); ok {
+				delay = min(after, c.retry.MaxBackoff)
+			}
+			resp.Body.Close()
+		}
+		if onRetry != nil {
+			onRetry(reason, delay)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		backoff = min(2*backoff, c.retry.MaxBackoff)
+	}
+}
+
+// delay returns the delay before a retry, removing the jitter from the backoff. random is a random number in [0, 1)
+func (p RetryPolicy) delay(backoff time.Duration, random float64) time.Duration {
+	jitter := min(max(p.Jitter, 0), 1)
+	return backoff - time.Duration(jitter*random*float64(backoff))
+}
+
+// WithSeed makes the client deterministic, for reproducible test runs and bug reports:
+// the jitter of retries is drawn from a random generator seeded with seed, instead of a randomly seeded one.
+// Other choices of the client, like picking the best subtitle between versions of the same score, are always deterministic.
+func WithSeed(seed uint64) Option {
+	return func(c *Client) {
+		c.rand = &lockedRand{rand: rand.New(rand.NewPCG(seed, seed))}
+	}
+}
+
+// lockedRand is a random generator safe for concurrent use
+type lockedRand struct {
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// random returns a random number in [0, 1), from the seeded generator of the client if any
+func (c *Client) random() float64 {
+	if c.rand == nil {
+		return rand.Float64()
+	}
+	c.rand.mu.Lock()
+	defer c.rand.mu.Unlock()
+	return c.rand.rand.Float64()
+}
+
+// isTransientStatus checks whether an HTTP status is worth retrying
+func isTransientStatus(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+// retryAfter returns the delay asked by the Retry-After header of a response, in seconds or as a date
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,85 @@
+package addic7ed_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+// failFirst answers the first requests of a handler with the given status
+func failFirst(handler http.Handler, failures int64, status int) http.Handler {
+	var requests int64
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt64(&requests, 1) <= failures {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(status)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+var fastRetries = addic7ed.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond, Jitter: 0.5}
+
+func TestRetryTransientErrors(t *testing.T) {
+	const srt = "1\n00:00:01,000 --> 00:00:02,000\nHello\n"
+	handler := episodeHandler(t, map[string]string{"/original/131967/0": srt})
+	c := addic7ed.New(
+		addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{failFirst(handler, 2, http.StatusServiceUnavailable)}}),
+		addic7ed.WithRetry(fastRetries),
+	)
+	show, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+	assert.Len(t, show.Subtitles, 4)
+
+	c = addic7ed.New(
+		addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{failFirst(handler, 3, http.StatusTooManyRequests)}}),
+		addic7ed.WithRetry(fastRetries),
+	)
+	_, err = c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	var statusErr *addic7ed.StatusError
+	if assert.True(t, errors.As(err, &statusErr), "unexpected error %v", err) {
+		assert.Equal(t, http.StatusTooManyRequests, statusErr.StatusCode)
+	}
+}
+
+func TestWithoutRetry(t *testing.T) {
+	handler := episodeHandler(t, nil)
+	c := addic7ed.New(
+		addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{failFirst(handler, 1, http.StatusBadGateway)}}),
+		addic7ed.WithoutRetry(),
+	)
+	_, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.True(t, errors.Is(err, addic7ed.ErrServerUnreachable), "unexpected error %v", err)
+}
+
+// retryDelays returns the delays of the retries of a search with a seeded client, as logged
+func retryDelays(t *testing.T, seed uint64) []string {
+	var logs bytes.Buffer
+	c := addic7ed.New(
+		addic7ed.WithHTTPClient(&http.Client{Transport: failingTransport{}}),
+		addic7ed.WithRetry(addic7ed.RetryPolicy{MaxAttempts: 4, InitialBackoff: time.Millisecond, MaxBackoff: 10 * time.Millisecond, Jitter: 1}),
+		addic7ed.WithSeed(seed),
+		addic7ed.WithLogger(slog.New(slog.NewTextHandler(&logs, nil))),
+		addic7ed.WithLogLevel(addic7ed.LevelWarn),
+	)
+	_, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.Error(t, err)
+	return regexp.MustCompile(`retrying in [^ "]+`).FindAllString(logs.String(), -1)
+}
+
+func TestWithSeed(t *testing.T) {
+	delays := retryDelays(t, 42)
+	assert.NotEmpty(t, delays)
+	assert.Equal(t, delays, retryDelays(t, 42))
+	assert.NotEqual(t, delays, retryDelays(t, 7))
+}
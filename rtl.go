@@ -0,0 +1,59 @@
+package addic7ed
+
+import (
+	"strings"
+	"unicode"
+)
+
+// rlm is the RIGHT-TO-LEFT MARK, an invisible character setting the direction of the punctuation next to it.
+// Unlike the embedding characters, it exists in the legacy Hebrew and Arabic encodings, see FromUTF8
+const rlm = "\u200f"
+
+// trimSpaceAndBidi trims the spaces and the bidirectional control characters, like RLM, around a line.
+// Subtitles in right-to-left languages often have them around the numbers and the timings of their cues
+func trimSpaceAndBidi(s string) string {
+	return strings.TrimFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.Is(unicode.Bidi_Control, r)
+	})
+}
+
+// isRTL checks whether a text is written right-to-left, from its first letter
+func isRTL(text string) bool {
+	text = srtOverrideRegexp.ReplaceAllString(srtTagRegexp.ReplaceAllString(text, ""), "")
+	for _, r := range text {
+		if unicode.In(r, unicode.Hebrew, unicode.Arabic, unicode.Syriac, unicode.Thaana, unicode.Nko) {
+			return true
+		}
+		if unicode.IsLetter(r) {
+			return false
+		}
+	}
+	return false
+}
+
+// MarkRTL surrounds the right-to-left lines of a UTF-8 subtitle with RIGHT-TO-LEFT MARKs, for players without proper bidirectional support.
+// These players show the punctuation at the start and the end of Arabic or Hebrew lines on the wrong side, like ".שלום" instead of "שלום.".
+// Left-to-right lines, like timings or English text, and lines already starting with a bidirectional control character are kept as is.
+// The text itself is never reordered.
+func MarkRTL(data []byte) []byte {
+	lines := strings.SplitAfter(string(data), "\n")
+	for i, line := range lines {
+		content := strings.TrimRight(line, "\r\n")
+		if content == "" || !isRTL(content) {
+			continue
+		}
+		if first := []rune(content)[0]; unicode.Is(unicode.Bidi_Control, first) {
+			continue
+		}
+		lines[i] = rlm + content + rlm + line[len(content):]
+	}
+	return []byte(strings.Join(lines, ""))
+}
+
+// WithRTLMarks converts downloaded subtitles to UTF-8 and marks their right-to-left lines, see MarkRTL.
+// Subtitles in legacy encodings are read in the encoding of their language, like Windows-1255 for Hebrew.
+func WithRTLMarks() Option {
+	return func(c *Client) {
+		c.rtlMarks = true
+	}
+}
@@ -0,0 +1,86 @@
+package addic7ed_test
+
+import (
+	"bytes"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+// hebrewSRT has right-to-left marks around the numbers and the timings of its cues, and between its cues, like many Hebrew and Arabic subtitles
+const hebrewSRT = "\u200f1\n\u200f00:00:01,000 --> 00:00:02,000\u200f\n\u200f<i>שלום, עולם!</i>\n\u200f\n" +
+	"\u200f2\n\u200f00:00:03,000 --> 00:00:04,000\u200f\n- مرحبا 2024.\n- Hello\n"
+
+func TestConvertSRTKeepsRTLText(t *testing.T) {
+	assert.Equal(t, addic7ed.FormatSRT, addic7ed.DetectFormat([]byte(hebrewSRT)))
+
+	var vtt bytes.Buffer
+	assert.NoError(t, addic7ed.ConvertSRT(&vtt, strings.NewReader(hebrewSRT), addic7ed.FormatVTT))
+	expected := "WEBVTT\n\n" +
+		"00:00:01.000 --> 00:00:02.000\n\u200f<i>שלום, עולם!</i>\n\n" +
+		"00:00:03.000 --> 00:00:04.000\n- مرحبا 2024.\n- Hello\n\n"
+	assert.Equal(t, expected, vtt.String())
+
+	var ass bytes.Buffer
+	assert.NoError(t, addic7ed.ConvertSRT(&ass, strings.NewReader(hebrewSRT), addic7ed.FormatASS))
+	assert.Contains(t, ass.String(), ",,\u200f{\\i1}שלום, עולם!{\\i0}\n")
+	assert.Contains(t, ass.String(), ",,- مرحبا 2024.\\N- Hello\n")
+}
+
+func TestConcatSRTKeepsRTLText(t *testing.T) {
+	var out bytes.Buffer
+	err := addic7ed.ConcatSRT(&out, []io.Reader{strings.NewReader(hebrewSRT), strings.NewReader(hebrewSRT)}, []time.Duration{0, time.Minute})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, strings.Count(out.String(), " --> "))
+	assert.Contains(t, out.String(), "4\n00:01:03,000 --> 00:01:04,000\n- مرحبا 2024.\n- Hello\n")
+}
+
+func TestDiffSRTIgnoresBidiMarks(t *testing.T) {
+	marked := strings.ReplaceAll(hebrewSRT, "\u200f", "")
+	diff, err := addic7ed.DiffSRT(strings.NewReader(hebrewSRT), strings.NewReader(string(addic7ed.MarkRTL([]byte(marked)))))
+	assert.NoError(t, err)
+	assert.True(t, diff.Equal(), "unexpected changes %v", diff.Changes)
+}
+
+func TestMarkRTL(t *testing.T) {
+	in := "1\r\n00:00:01,000 --> 00:00:02,000\r\n<i>שלום.</i>\r\n- مرحبا\r\nHello, שלום\r\n\u200fכבר מסומן.\r\n"
+	expected := "1\r\n00:00:01,000 --> 00:00:02,000\r\n\u200f<i>שלום.</i>\u200f\r\n\u200f- مرحبا\u200f\r\nHello, שלום\r\n\u200fכבר מסומן.\r\n"
+	assert.Equal(t, expected, string(addic7ed.MarkRTL([]byte(in))))
+	// Marking is idempotent
+	assert.Equal(t, expected, string(addic7ed.MarkRTL([]byte(expected))))
+}
+
+func TestDownloadWithRTLMarks(t *testing.T) {
+	const srt = "1\n00:00:01,000 --> 00:00:02,000\nשלום.\n"
+	server := httptest.NewServer(episodeHandler(t, map[string]string{"/original/131967/0": srt}))
+	defer server.Close()
+
+	c := addic7ed.New(addic7ed.WithBaseURL(server.URL), addic7ed.WithRTLMarks(), addic7ed.WithEncodings(map[string]addic7ed.Charset{"English": addic7ed.CharsetWindows1255}))
+	show, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+	content, err := show.Subtitles[0].Download()
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(content)
+	// The marks exist in the legacy Hebrew encoding
+	assert.Equal(t, "1\n00:00:01,000 --> 00:00:02,000\n\xfe\xf9\xec\xe5\xed.\xfe\n", string(data))
+}
+
+func TestDownloadLegacySubtitlesWithRTLMarks(t *testing.T) {
+	// "שלום." in Windows-1255
+	server := httptest.NewServer(languageHandler(t, "Hebrew", "1\n00:00:01,000 --> 00:00:02,000\n\xf9\xec\xe5\xed.\n"))
+	defer server.Close()
+
+	c := addic7ed.New(addic7ed.WithBaseURL(server.URL), addic7ed.WithRTLMarks())
+	show, err := c.SearchAll("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]")
+	assert.NoError(t, err)
+	content, err := show.Subtitles.Filter(addic7ed.WithLanguage("he"))[0].Download()
+	assert.NoError(t, err)
+	data, _ := io.ReadAll(content)
+	assert.Equal(t, "1\n00:00:01,000 --> 00:00:02,000\n\u200fשלום.\u200f\n", string(data))
+}
@@ -0,0 +1,142 @@
+package addic7ed
+
+import "math"
+
+// Scorer scores how well a version of subtitles matches a filename, to pick the best subtitle. Higher scores are better.
+// Scores are only compared between the versions of a search, so their scale is free, see NormalizedScorer for a bounded scale.
+type Scorer interface {
+	Score(fileName, version string) float64
+}
+
+// NormalizedScorer is a scorer that also scores versions on a scale from 0 to 100, whatever the filename and the version,
+// so that scores can be compared to thresholds or between scorers, see ScoredSubtitle.Normalized.
+// 100 means that the version fully matches the filename. All the scorers of the package are NormalizedScorers.
+type NormalizedScorer interface {
+	Scorer
+	NormalizedScore(fileName, version string) float64
+}
+
+// weightWhenExactMatch is the weight of the exact matches of words in the scores of word scorers
+const weightWhenExactMatch = 10
+
+// WithScorer sets the scorer used to pick the best version of subtitles, see SearchBest. New clients use JaroWinklerScorer
+func WithScorer(scorer Scorer) Option {
+	return func(c *Client) {
+		c.scorer = scorer
+	}
+}
+
+// JaroWinklerScorer is the default scorer.
+// It searches for similarities in both the filename and the version
+// filename and versions are indexed by word, and the more there are common words, the more the version gets a good score
+// Similarity is computed from a scoring between word exact matching and word distance (with Jaro/Winkler distance algorithm)
+// It is a WordScorer comparing words with JaroWinkler.
+type JaroWinklerScorer struct {
+	// CJKNGram is the size of the n-grams that the words in Chinese, Japanese or Korean are split into, as these scripts don't separate words.
+	// 0 means DefaultCJKNGram, a negative size keeps these words whole
+	CJKNGram int
+}
+
+// Score scores a version for a filename
+func (s JaroWinklerScorer) Score(fileName, version string) float64 {
+	return s.wordScorer().Score(fileName, version)
+}
+
+// NormalizedScore scores a version for a filename from 0 to 100, see WordScorer.NormalizedScore
+func (s JaroWinklerScorer) NormalizedScore(fileName, version string) float64 {
+	return s.wordScorer().NormalizedScore(fileName, version)
+}
+
+func (s JaroWinklerScorer) wordScorer() WordScorer {
+	return WordScorer{Similarity: JaroWinkler, CJKNGram: s.CJKNGram}
+}
+
+// WordScorer scores versions like JaroWinklerScorer, comparing the words of the version and of the filename with any
+// similarity, like LevenshteinRatio for naming styles with abbreviated tags. Words are exact matches above a similarity of 0.9.
+type WordScorer struct {
+	// Similarity compares two words, JaroWinkler when nil
+	Similarity Similarity
+	// CJKNGram is the size of the n-grams that the words in Chinese, Japanese or Korean are split into, like in JaroWinklerScorer
+	CJKNGram int
+}
+
+// Score scores a version for a filename
+func (s WordScorer) Score(fileName, version string) float64 {
+	return s.score(fileName, version, func(string, ...interface{}) {})
+}
+
+// NormalizedScore scores a version for a filename from 0 to 100: the score relative to the score of a version whose words
+// are all exact matches in the filename, so that 100 means that all words of the version are in the filename.
+func (s WordScorer) NormalizedScore(fileName, version string) float64 {
+	words := len(cjkNGrams(canonicalTags(version), s.cjkNGram()))
+	if words == 0 {
+		return 0
+	}
+	return normalized(s.Score(fileName, version) / (1 + float64(words*weightWhenExactMatch)))
+}
+
+// normalized bounds a ratio to the normalized scale, from 0 to 100
+func normalized(ratio float64) float64 {
+	if math.IsNaN(ratio) {
+		return 0
+	}
+	return 100 * min(max(ratio, 0), 1)
+}
+
+func (s WordScorer) cjkNGram() int {
+	if s.CJKNGram == 0 {
+		return DefaultCJKNGram
+	}
+	return s.CJKNGram
+}
+
+// score computes the score of the scorer, tracing the computation
+func (s WordScorer) score(fileName, version string, tracef func(message string, params ...interface{})) float64 {
+	similarity := s.Similarity
+	if similarity == nil {
+		similarity = JaroWinkler
+	}
+	cjkNGram := s.cjkNGram()
+	// Tags are compared in their canonical form, so that versions and filenames only differing by formatting match
+	wordsFromTitle := cjkNGrams(canonicalTags(fileName), cjkNGram)
+	versionWords := cjkNGrams(canonicalTags(version), cjkNGram)
+	exactMatchs := 0.0
+	var similarityScore float64
+	for _, subWordFromTitle := range wordsFromTitle {
+		for _, subWordFromVersion := range versionWords {
+			// Similarity is a float computed from the similarity of the scorer, Jaro/Winkler distance by default
+			// 0 = no similarity at all, 1 = exact same string
+			distanceScore := similarity(subWordFromVersion, subWordFromTitle)
+			if distanceScore > 0.9 {
+				exactMatchs += distanceScore
+			}
+			similarityScore += distanceScore
+
+			tracef("--- Comparison: %v (version '%v' compared to '%v') - exact-matchs=%v => distance=%v",
+				version, subWordFromVersion, subWordFromTitle, exactMatchs, distanceScore)
+		}
+	}
+	searchCardinality := float64(len(versionWords) * len(wordsFromTitle)) // Number of comparisons
+	tracef("== Search cardinality = (words in Version=%v)x(words in Filename=%v) = %v",
+		len(versionWords), len(wordsFromTitle), searchCardinality)
+	// Will lower the similarity score if there were a lot of word to compare
+	computedSimilarityScore := similarityScore / searchCardinality
+	tracef("== Computed similarity = (similarity=%v)/(searchCardinality=%v) = %v",
+		similarityScore, searchCardinality, computedSimilarityScore,
+	)
+
+	// By multiplying by the number of matches, we ensure that a version with 3 exact matches is better than a version with 2 exact matches.
+	proportionExactMatchs := (exactMatchs) / float64(len(versionWords)) // Will tend to 1 (1 = all words in version are contained in filename)
+	exactMatchScore := float64(proportionExactMatchs * (exactMatchs * weightWhenExactMatch))
+	tracef("== Exact match score =  (proportionOfExactMatchs=%v)x(exactMatch=%v)x(weigth=%v) = %v",
+		proportionExactMatchs, exactMatchs, weightWhenExactMatch, exactMatchScore,
+	)
+
+	score := computedSimilarityScore + exactMatchScore
+	tracef("=============================================================================")
+	tracef("===> TOTAL SCORE FILE=%v VERSION=%v = (Computed similarity=%v)+(Exact match score=%v)=%v <===",
+		fileName, version, computedSimilarityScore, exactMatchScore, score,
+	)
+	tracef("=============================================================================")
+	return score
+}
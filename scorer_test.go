@@ -0,0 +1,141 @@
+package addic7ed_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+// groupScorer only trusts the release group
+type groupScorer struct {
+	group string
+}
+
+func (s groupScorer) Score(fileName, version string) float64 {
+	if strings.EqualFold(addic7ed.ParseVersion(version).Group, s.group) {
+		return 1
+	}
+	return 0
+}
+
+func TestWithScorer(t *testing.T) {
+	transport := handlerTransport{episodeHandler(t, nil)}
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}), addic7ed.WithScorer(groupScorer{group: "TBS"}))
+	_, subtitle, err := c.SearchBest("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", "English")
+	assert.NoError(t, err)
+	assert.Equal(t, "WEB.x264-TBS", subtitle.Version)
+}
+
+func TestJaroWinklerScorer(t *testing.T) {
+	scorer := addic7ed.JaroWinklerScorer{}
+	fileName := "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]"
+	assert.True(t, scorer.Score(fileName, "BATV") > scorer.Score(fileName, "WEB.x264-TBS"))
+}
+
+func TestJaroWinklerScorerWithCJKFileNames(t *testing.T) {
+	scorer := addic7ed.JaroWinklerScorer{}
+	// Words in different scripts are split, so the group is found in the filename
+	fileName := "无耻之徒美版第八季第11集BATV版.mkv"
+	assert.True(t, scorer.Score(fileName, "720p.HDTV.x264-BATV") > scorer.Score(fileName, "WEB.x264-TBS"))
+
+	// Words in CJK scripts are compared by n-grams
+	fileName = "无耻之徒第八季人人影视.mkv"
+	assert.True(t, scorer.Score(fileName, "人人影视") > scorer.Score(fileName, "衣柜字幕"))
+	assert.True(t, scorer.Score(fileName, "人人影视") > addic7ed.JaroWinklerScorer{CJKNGram: -1}.Score(fileName, "人人影视"))
+}
+
+func TestNormalizedScores(t *testing.T) {
+	fileName := "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]"
+	for name, scorer := range map[string]addic7ed.NormalizedScorer{
+		"JaroWinklerScorer": addic7ed.JaroWinklerScorer{},
+		"WordScorer":        addic7ed.WordScorer{Similarity: addic7ed.LevenshteinRatio},
+		"SimilarityScorer":  addic7ed.SimilarityScorer{},
+	} {
+		for _, version := range []string{"BATV", "720p.HDTV.x264-BATV", "WEB.x264-TBS", ""} {
+			score := scorer.NormalizedScore(fileName, version)
+			assert.True(t, score >= 0 && score <= 100, "%v scored %q %v", name, version, score)
+		}
+		assert.True(t, scorer.NormalizedScore(fileName, "720p.HDTV.x264-BATV") > scorer.NormalizedScore(fileName, "WEB.x264-TBS"), name)
+		assert.True(t, scorer.NormalizedScore(fileName, "720p.HDTV.x264-BATV") > 90, name)
+	}
+}
+
+func TestSearchBestBreaksTiesByVersion(t *testing.T) {
+	transport := handlerTransport{episodeHandler(t, nil)}
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}), addic7ed.WithScorer(groupScorer{group: "unknown"}))
+	for i := 0; i < 10; i++ {
+		_, subtitle, err := c.SearchBest("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", "English")
+		assert.NoError(t, err)
+		assert.Equal(t, "BATV", subtitle.Version)
+	}
+}
+
+func TestSimilarities(t *testing.T) {
+	for name, similarity := range map[string]addic7ed.Similarity{
+		"JaroWinkler":      addic7ed.JaroWinkler,
+		"LevenshteinRatio": addic7ed.LevenshteinRatio,
+		"TokenSetRatio":    addic7ed.TokenSetRatio,
+	} {
+		assert.Equal(t, 1.0, similarity("batv", "batv"), name)
+		assert.True(t, similarity("batv", "batv2") > similarity("batv", "tbs"), name)
+	}
+	assert.Equal(t, 0.75, addic7ed.LevenshteinRatio("hdtv", "hdtc"))
+	assert.Equal(t, 1.0, addic7ed.LevenshteinRatio("", ""))
+	// Token sets don't depend on the order, case and separators of the words, nor on the words only found in one of them
+	assert.Equal(t, 1.0, addic7ed.TokenSetRatio("720p.HDTV.x264-BATV", "batv x264 hdtv 720p"))
+	assert.Equal(t, 1.0, addic7ed.TokenSetRatio("BATV", "Shameless.US.S08E11.720p.HDTV.x264-BATV"))
+	assert.Equal(t, 0.0, addic7ed.TokenSetRatio("", "BATV"))
+}
+
+func TestWordScorer(t *testing.T) {
+	fileName := "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]"
+	assert.Equal(t, addic7ed.JaroWinklerScorer{}.Score(fileName, "BATV"), addic7ed.WordScorer{}.Score(fileName, "BATV"))
+	scorer := addic7ed.WordScorer{Similarity: addic7ed.LevenshteinRatio}
+	assert.True(t, scorer.Score(fileName, "BATV") > scorer.Score(fileName, "WEB.x264-TBS"))
+
+	transport := handlerTransport{episodeHandler(t, nil)}
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}), addic7ed.WithScorer(scorer))
+	_, subtitle, err := c.SearchBest(fileName, "English", addic7ed.WithTrace())
+	assert.NoError(t, err)
+	assert.Equal(t, "BATV", subtitle.Version)
+}
+
+func TestSimilarityScorer(t *testing.T) {
+	fileName := "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]"
+	scorer := addic7ed.SimilarityScorer{}
+	assert.True(t, scorer.Score(fileName, "BATV") > scorer.Score(fileName, "WEB.x264-TBS"))
+	assert.True(t, scorer.Score(fileName, "720p.HDTV.x264-BATV") > scorer.Score(fileName, "WEB.x264-TBS"))
+
+	transport := handlerTransport{episodeHandler(t, nil)}
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: transport}), addic7ed.WithScorer(scorer))
+	_, subtitle, err := c.SearchBest(fileName, "English")
+	assert.NoError(t, err)
+	assert.Equal(t, "BATV", subtitle.Version)
+}
+
+// benchmarkVersions are versions of Addic7ed, scored against a filename by the benchmarks
+var benchmarkVersions = []string{"BATV", "WEB.x264-TBS", "720p.HDTV.x264-AVS", "AMZN.WEBRip.DDP5.1.x264-NTb", "1080p.BluRay.x264-ROVERS"}
+
+func BenchmarkScorers(b *testing.B) {
+	fileName := "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv].mkv"
+	for _, scorer := range []struct {
+		name   string
+		scorer addic7ed.Scorer
+	}{
+		{"JaroWinkler", addic7ed.JaroWinklerScorer{}},
+		{"LevenshteinRatio", addic7ed.WordScorer{Similarity: addic7ed.LevenshteinRatio}},
+		{"TokenSetRatio", addic7ed.SimilarityScorer{Similarity: addic7ed.TokenSetRatio}},
+	} {
+		b.Run(scorer.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				for _, version := range benchmarkVersions {
+					scorer.scorer.Score(fileName, version)
+				}
+			}
+		})
+	}
+}
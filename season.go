@@ -0,0 +1,298 @@
+package addic7ed
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// findShowLink finds the link to the show on the page of an episode
+// It returns the Addic7ed id of the show and its name, or false if the page has no link to the show
+func findShowLink(doc *goquery.Document) (string, string, bool) {
+	link := doc.Find(`a[href^="/show/"]`).First()
+	href, ok := link.Attr("href")
+	if !ok {
+		return "", "", false
+	}
+	id := strings.Trim(strings.TrimPrefix(href, "/show/"), "/")
+	if id == "" {
+		return "", "", false
+	}
+	return id, strings.TrimSpace(link.Text()), true
+}
+
+// TVShow is a show of Addic7ed, with all its seasons
+type TVShow struct {
+	// ID is the Addic7ed id of the show, like "5427"
+	ID string
+	// Name is the Addic7ed name of the show, like "Shameless (US)"
+	Name string
+	// Seasons are the numbers of the seasons of the show, in order
+	Seasons []int
+}
+
+// GetShow gets a show from Addic7ed website, given its Addic7ed id like "5427" or its name like "Shameless (US)".
+// Names are searched with the search feature of the website, so the name of any episode of the show works too.
+// Use GetSeason to browse the episodes of the show.
+func (c *Client) GetShow(nameOrID string, opts ...CallOption) (TVShow, error) {
+	return c.GetShowContext(context.Background(), nameOrID, opts...)
+}
+
+// GetShowContext is like GetShow, with a context to cancel the search
+func (c *Client) GetShowContext(ctx context.Context, nameOrID string, opts ...CallOption) (TVShow, error) {
+	call := c.newCall(ctx, opts)
+	id := strings.TrimSpace(nameOrID)
+	if _, err := strconv.Atoi(id); err != nil {
+		doc, err := call.createDocFromURL(c.url(fmt.Sprintf("srch.php?search=%v&Submit=Search", url.QueryEscape(id))))
+		if err != nil {
+			return TVShow{}, err
+		}
+		found, _, ok := findShowLink(doc)
+		if !ok {
+			return TVShow{}, newError(CodeShowNotFound, nil, "show %v not found", nameOrID)
+		}
+		call.infof("Found show %v for %v", found, nameOrID)
+		id = found
+	}
+
+	doc, err := call.createDocFromURL(c.url("show/" + id))
+	if err != nil {
+		if isNotFound(err) {
+			return TVShow{}, newError(CodeShowNotFound, err, "show %v not found", nameOrID)
+		}
+		return TVShow{}, err
+	}
+	name, err := call.findShowName(doc)
+	if err != nil {
+		return TVShow{}, newError(CodeShowNotFound, err, "show %v not found", nameOrID)
+	}
+	return TVShow{ID: id, Name: name, Seasons: parseSeasons(doc)}, nil
+}
+
+// parseSeasons finds the numbers of the seasons listed on the page of a show
+func parseSeasons(doc *goquery.Document) []int {
+	seasons := []int{}
+	doc.Find("#sl button").Each(func(i int, s *goquery.Selection) {
+		if season, err := strconv.Atoi(strings.TrimSpace(s.Text())); err == nil && !slices.Contains(seasons, season) {
+			seasons = append(seasons, season)
+		}
+	})
+	slices.Sort(seasons)
+	return seasons
+}
+
+// Episode is an episode of a season of a show, with its subtitles
+type Episode struct {
+	Number EpisodeNumber
+	// Title is the title of the episode, like "A Gallagher Pedicure"
+	Title string
+	// AirDate is the date the episode was first aired, or the zero time when the season page doesn't give it
+	AirDate time.Time
+	// Subtitles are the subtitles of the episode in all languages
+	Subtitles Subtitles
+	// Partial is set when the episode was parsed from a page cut short by the deadline of a best-effort call, see WithBestEffort
+	Partial bool
+}
+
+// Name returns the name of the episode like on its own page, like "Shameless (US) - 08x11 - A Gallagher Pedicure"
+func (e Episode) Name(showName string) string {
+	return fmt.Sprintf("%v - %02dx%02d - %v", showName, e.Number.Season, e.Number.Episode, e.Title)
+}
+
+// show returns the episode as a Show named like on its own page
+func (e Episode) show(showName string) Show {
+	return Show{Name: e.Name(showName), Number: e.Number, Title: e.Title, Subtitles: e.Subtitles, Partial: e.Partial}
+}
+
+// GetEpisodes gets all episodes of a season of a show from Addic7ed website, in order, with their subtitles in all languages.
+// It returns ErrNoSubtitlesYet if the season has no subtitle yet.
+// With WithBestEffort, the episodes parsed before the deadline are returned, flagged Partial.
+func (c *Client) GetEpisodes(show TVShow, season int, opts ...CallOption) ([]Episode, error) {
+	return c.GetEpisodesContext(context.Background(), show, season, opts...)
+}
+
+// GetEpisodesContext is like GetEpisodes, with a context to cancel the search
+func (c *Client) GetEpisodesContext(ctx context.Context, show TVShow, season int, opts ...CallOption) ([]Episode, error) {
+	call := c.newCall(ctx, opts)
+	if episodes, ok := c.cache.episodes(seasonKey(show.ID, season)); ok {
+		call.tracef("Season %v of show %v served from cache", season, show.Name)
+		return episodes, nil
+	}
+	episodes, err := call.fetchEpisodes(show, season)
+	if err == nil && !call.partial {
+		c.cache.storeEpisodes(seasonKey(show.ID, season), episodes, show.Name)
+	}
+	return episodes, err
+}
+
+// fetchEpisodes fetches the episodes of a season from Addic7ed website, see GetEpisodes
+func (c *call) fetchEpisodes(show TVShow, season int) ([]Episode, error) {
+	c.show = normalizeShowName(show.Name)
+	doc, err := c.createDocFromURL(c.seasonURL(show.ID, season))
+	if err != nil {
+		return nil, err
+	}
+	episodes := c.parseSeasonPage(doc)
+	if len(episodes) == 0 && c.partial {
+		return nil, newError(CodeServerUnreachable, c.ctx.Err(), "No episode of season %v of show %v was parsed in time", season, show.Name)
+	}
+	for i := range episodes {
+		episodes[i].Partial = c.partial
+	}
+	if len(episodes) == 0 {
+		return episodes, newError(CodeNoSubtitlesYet, nil, "season %v of show %v does not have any subtitle yet", season, show.Name)
+	}
+	return episodes, nil
+}
+
+// GetSeason gets all episodes of a season of a show from Addic7ed website, with their subtitles in all languages, by episode.
+// Episodes are named like on their own page, like "Shameless (US) - 08x11 - A Gallagher Pedicure".
+// It returns ErrNoSubtitlesYet if the season has no subtitle yet.
+// With WithBestEffort, the episodes parsed before the deadline are returned, flagged Partial.
+// Use GetEpisodes to get the titles and air dates of the episodes too.
+func (c *Client) GetSeason(show TVShow, season int, opts ...CallOption) (map[EpisodeNumber]Show, error) {
+	return c.GetSeasonContext(context.Background(), show, season, opts...)
+}
+
+// GetSeasonContext is like GetSeason, with a context to cancel the search
+func (c *Client) GetSeasonContext(ctx context.Context, show TVShow, season int, opts ...CallOption) (map[EpisodeNumber]Show, error) {
+	episodes, err := c.GetEpisodesContext(ctx, show, season, opts...)
+	if episodes == nil {
+		return nil, err
+	}
+	return seasonShows(episodes, show.Name), err
+}
+
+// seasonShows returns the episodes of a season as shows, by episode
+func seasonShows(episodes []Episode, showName string) map[EpisodeNumber]Show {
+	shows := make(map[EpisodeNumber]Show, len(episodes))
+	for _, episode := range episodes {
+		shows[episode.Number] = episode.show(showName)
+	}
+	return shows
+}
+
+// seasonURL returns the URL of the page of a season, listing the subtitles of all its episodes
+func (c *Client) seasonURL(showID string, season int) string {
+	return c.url(fmt.Sprintf("show/%v?season=%v", showID, season))
+}
+
+// airDateLayouts are the layouts of the air dates of the episodes on season pages
+var airDateLayouts = []string{"2006-01-02", "January 2, 2006", "Jan 2, 2006", "2 January 2006", "2 Jan 2006", "01/02/2006"}
+
+// parseAirDate parses the air date of an episode, returning the zero time when it's missing or unknown
+func parseAirDate(date string) time.Time {
+	for _, layout := range airDateLayouts {
+		if t, err := time.Parse(layout, date); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// airDateColumn returns the index of the column of the air dates on the page of a season, or -1 if the page doesn't have one.
+// Only the header row of the table of the episodes is looked at, not the other tables of the page.
+func airDateColumn(doc *goquery.Document) int {
+	column := -1
+	header := doc.Find("tr.epeng").First().Closest("table").Find("tr:has(th)").First()
+	header.Find("th").EachWithBreak(func(i int, th *goquery.Selection) bool {
+		switch strings.ToLower(strings.TrimSpace(th.Text())) {
+		case "aired", "air date", "airdate":
+			column = i
+			return false
+		}
+		return true
+	})
+	return column
+}
+
+// parseSeasonPage parses the page of a season to find all its episodes with their subtitles, in order.
+// The air dates are only parsed when the page has a column for them, found by its header.
+func (c *call) parseSeasonPage(doc *goquery.Document) []Episode {
+	episodes := []Episode{}
+	indexes := map[EpisodeNumber]int{}
+	c.parseSeasonRows(doc, func(row seasonRow) bool {
+		index, ok := indexes[row.number]
+		if !ok {
+			index = len(episodes)
+			indexes[row.number] = index
+			episodes = append(episodes, Episode{Number: row.number, Title: row.title})
+		}
+		if episodes[index].AirDate.IsZero() {
+			episodes[index].AirDate = row.airDate
+		}
+		episodes[index].Subtitles = append(episodes[index].Subtitles, row.subtitle)
+		return true
+	})
+	slices.SortStableFunc(episodes, func(a, b Episode) int {
+		if a.Number.Season != b.Number.Season {
+			return a.Number.Season - b.Number.Season
+		}
+		return a.Number.Episode - b.Number.Episode
+	})
+	return episodes
+}
+
+// seasonRow is a row of the page of a season, with a subtitle of an episode
+type seasonRow struct {
+	number   EpisodeNumber
+	title    string
+	airDate  time.Time
+	subtitle Subtitle
+}
+
+// parseSeasonRows parses the rows of the page of a season in order, until yield returns false
+func (c *call) parseSeasonRows(doc *goquery.Document, yield func(seasonRow) bool) {
+	airDate := airDateColumn(doc)
+	doc.Find("tr.epeng").EachWithBreak(func(i int, row *goquery.Selection) bool {
+		cells := row.Find("td")
+		if cells.Length() < 10 {
+			return true
+		}
+		cell := func(i int) string {
+			return strings.TrimSpace(cells.Eq(i).Text())
+		}
+		season, err := strconv.Atoi(cell(0))
+		if err != nil {
+			return true
+		}
+		episode, err := strconv.Atoi(cell(1))
+		if err != nil {
+			return true
+		}
+		href, ok := cells.Eq(9).Find("a").Attr("href")
+		if !ok {
+			return true
+		}
+
+		parsed := seasonRow{number: EpisodeNumber{Season: season, Episode: episode}, title: cell(2)}
+		if airDate >= 0 {
+			parsed.airDate = parseAirDate(cell(airDate))
+		}
+		// The version cell has the version alone, like "AMZN WEB-DL", unlike the titles of the episode pages
+		version, link := cell(4), c.url(strings.TrimSpace(href))
+		// Subtitles are refreshed from the page of their episode, linked by its title
+		page := ""
+		if episodeHref, ok := cells.Eq(2).Find("a").Attr("href"); ok && strings.TrimSpace(episodeHref) != "" {
+			page = c.url(strings.TrimSpace(episodeHref))
+		}
+		parsed.subtitle = Subtitle{
+			Language:        cell(3),
+			Version:         version,
+			VersionInfo:     ParseVersion(version),
+			Link:            link,
+			Page:            page,
+			Warnings:        c.unparsedFieldWarnings(link, cell(3), version),
+			Completion:      parseCompletion(cell(5)),
+			HearingImpaired: cell(6) != "",
+			client:          c.Client,
+		}
+		return yield(parsed)
+	})
+}
@@ -0,0 +1,180 @@
+package addic7ed_test
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+// showHandler serves the show and season fixtures for show 5427, and the episode fixture otherwise
+func showHandler(t *testing.T) http.Handler {
+	show, err := os.ReadFile("testdata/show.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	season, err := os.ReadFile("testdata/season.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := episodeHandler(t, nil)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/show/5427" && r.URL.Query().Get("season") == "8":
+			w.Write(season)
+		case r.URL.Path == "/show/5427" && r.URL.Query().Get("season") == "":
+			w.Write(show)
+		case r.URL.Path == "/show/5427":
+			w.Write([]byte("<html><body>No subtitles</body></html>"))
+		default:
+			handler.ServeHTTP(w, r)
+		}
+	})
+}
+
+func TestGetShow(t *testing.T) {
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{showHandler(t)}}))
+	expected := addic7ed.TVShow{ID: "5427", Name: "Shameless (US)", Seasons: []int{6, 7, 8}}
+
+	show, err := c.GetShow("5427")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, show)
+
+	show, err = c.GetShow("Shameless (US)")
+	assert.NoError(t, err)
+	assert.Equal(t, expected, show)
+
+	_, err = c.GetShow("404")
+	assert.True(t, errors.Is(err, addic7ed.ErrShowNotFound), "unexpected error %v", err)
+}
+
+func TestGetSeason(t *testing.T) {
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{showHandler(t)}}))
+	show := addic7ed.TVShow{ID: "5427", Name: "Shameless (US)", Seasons: []int{6, 7, 8}}
+
+	episodes, err := c.GetSeason(show, 8)
+	assert.NoError(t, err)
+	assert.Len(t, episodes, 2)
+	episode := episodes[addic7ed.EpisodeNumber{Season: 8, Episode: 12}]
+	assert.Equal(t, "Shameless (US) - 08x12 - Church of Gay Jesus", episode.Name)
+	assert.Equal(t, addic7ed.EpisodeNumber{Season: 8, Episode: 12}, episode.Number)
+	assert.Equal(t, "Church of Gay Jesus", episode.Title)
+	assert.Len(t, episode.Subtitles.Filter(addic7ed.WithLanguage("English")), 2)
+
+	_, err = c.GetSeason(show, 7)
+	assert.True(t, errors.Is(err, addic7ed.ErrNoSubtitlesYet), "unexpected error %v", err)
+}
+
+func TestGetEpisodes(t *testing.T) {
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{showHandler(t)}}))
+	show := addic7ed.TVShow{ID: "5427", Name: "Shameless (US)", Seasons: []int{6, 7, 8}}
+
+	episodes, err := c.GetEpisodes(show, 8)
+	assert.NoError(t, err)
+	if assert.Len(t, episodes, 2) {
+		assert.Equal(t, addic7ed.EpisodeNumber{Season: 8, Episode: 11}, episodes[0].Number)
+		assert.Equal(t, "A Gallagher Pedicure", episodes[0].Title)
+		assert.Equal(t, "Shameless (US) - 08x11 - A Gallagher Pedicure", episodes[0].Name(show.Name))
+		assert.True(t, episodes[0].AirDate.IsZero())
+		assert.Equal(t, addic7ed.EpisodeNumber{Season: 8, Episode: 12}, episodes[1].Number)
+		assert.Len(t, episodes[1].Subtitles.Filter(addic7ed.WithLanguage("English")), 2)
+	}
+
+	_, err = c.GetEpisodes(show, 7)
+	assert.True(t, errors.Is(err, addic7ed.ErrNoSubtitlesYet), "unexpected error %v", err)
+}
+
+func TestGetSeasonSeq(t *testing.T) {
+	show := addic7ed.TVShow{ID: "5427", Name: "Shameless (US)", Seasons: []int{6, 7, 8}}
+	for _, cached := range []bool{false, true} {
+		opts := []addic7ed.Option{addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{showHandler(t)}})}
+		if cached {
+			opts = append(opts, addic7ed.WithCache(time.Hour, 0))
+		}
+		c := addic7ed.New(opts...)
+		season, err := c.GetSeason(show, 8)
+		assert.NoError(t, err)
+		count := 0
+		for _, episode := range season {
+			count += len(episode.Subtitles)
+		}
+
+		subtitles, err := c.GetSeasonSeq(show, 8)
+		assert.NoError(t, err)
+		all := map[addic7ed.EpisodeNumber]int{}
+		for number, s := range subtitles {
+			assert.Contains(t, season[number].Subtitles, s)
+			all[number]++
+		}
+		assert.Len(t, all, 2)
+		assert.Equal(t, count, all[addic7ed.EpisodeNumber{Season: 8, Episode: 11}]+all[addic7ed.EpisodeNumber{Season: 8, Episode: 12}])
+
+		// Breaking out of the loop stops the parsing
+		n := 0
+		for range subtitles {
+			n++
+			if n == 2 {
+				break
+			}
+		}
+		assert.Equal(t, 2, n)
+	}
+
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{showHandler(t)}}))
+	subtitles, err := c.GetSeasonSeq(show, 7)
+	assert.NoError(t, err)
+	for range subtitles {
+		t.Error("season 7 has no subtitle")
+	}
+	_, err = c.GetSeasonSeq(addic7ed.TVShow{ID: "404", Name: "Unknown"}, 1)
+	assert.Error(t, err)
+}
+
+func TestGetEpisodesWithAirDates(t *testing.T) {
+	page := `<html><body><table>
+	<thead><tr><th>S</th><th>E</th><th>Episode</th><th>Language</th><th>Version</th><th>Completed</th><th>HI</th><th>Corrected</th><th>HD</th><th>Download</th><th>Aired</th></tr></thead>
+	<tbody>
+	<tr class="epeng"><td>1</td><td>2</td><td>Second</td><td>English</td><td>LOL</td><td>Completed</td><td></td><td></td><td></td><td><a href="/original/1/0">Download</a></td><td>2011-01-16</td></tr>
+	<tr class="epeng"><td>1</td><td>1</td><td>Pilot</td><td>English</td><td>LOL</td><td>Completed</td><td></td><td></td><td></td><td><a href="/original/2/0">Download</a></td><td>Jan 9, 2011</td></tr>
+	<tr class="epeng"><td>1</td><td>1</td><td>Pilot</td><td>French</td><td>LOL</td><td>Completed</td><td></td><td></td><td></td><td><a href="/original/2/8">Download</a></td><td>Jan 9, 2011</td></tr>
+	</tbody></table></body></html>`
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(page))
+	})}}))
+
+	episodes, err := c.GetEpisodes(addic7ed.TVShow{ID: "1", Name: "Show"}, 1)
+	assert.NoError(t, err)
+	if assert.Len(t, episodes, 2) {
+		assert.Equal(t, "Pilot", episodes[0].Title)
+		assert.Equal(t, time.Date(2011, 1, 9, 0, 0, 0, 0, time.UTC), episodes[0].AirDate)
+		assert.Len(t, episodes[0].Subtitles, 2)
+		assert.Equal(t, time.Date(2011, 1, 16, 0, 0, 0, 0, time.UTC), episodes[1].AirDate)
+	}
+}
+
+func TestGetEpisodesWithOtherTables(t *testing.T) {
+	page := `<html><body>
+	<table><tr><th>Aired</th><th>Sort by</th></tr></table>
+	<table>
+	<thead><tr><th>S</th><th>E</th><th>Episode</th><th>Language</th><th>Version</th><th>Completed</th><th>HI</th><th>Corrected</th><th>HD</th><th>Download</th></tr></thead>
+	<tbody>
+	<tr class="epeng"><td>1</td><td>1</td><td>Pilot</td><td>English</td><td>AMZN WEB-DL</td><td>Completed</td><td></td><td></td><td></td><td><a href="/original/2/0">Download</a></td></tr>
+	</tbody></table></body></html>`
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(page))
+	})}}))
+
+	episodes, err := c.GetEpisodes(addic7ed.TVShow{ID: "1", Name: "Show"}, 1)
+	assert.NoError(t, err)
+	if assert.Len(t, episodes, 1) {
+		assert.True(t, episodes[0].AirDate.IsZero())
+		if assert.Len(t, episodes[0].Subtitles, 1) {
+			assert.Equal(t, "AMZN WEB-DL", episodes[0].Subtitles[0].Version)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package addic7ed
+
+import (
+	"context"
+	"iter"
+)
+
+// SearchAllSeq searches in the Addic7ed website for a given episode of a show, like SearchAll
+// The page of the show is fetched right away, but subtitles are parsed lazily while ranging over the returned sequence,
+// so breaking out of the loop stops the parsing.
+// If the versions of the episode are split in other pages, these pages are fetched while ranging,
+// and the sequence stops at the first page that can't be fetched, raising a WarningMissingVersions (see WithWarnings).
+// Like SearchAll, it returns ErrEpisodeMismatch when the page is not the page of the searched episode.
+// It returns the episode name and the sequence of found subtitles.
+func (c *Client) SearchAllSeq(showStr string, opts ...CallOption) (string, iter.Seq[Subtitle], error) {
+	return c.SearchAllSeqContext(context.Background(), showStr, opts...)
+}
+
+// SearchAllSeqContext is like SearchAllSeq, with a context to cancel the search and the fetching of other pages while ranging
+func (c *Client) SearchAllSeqContext(ctx context.Context, showStr string, opts ...CallOption) (string, iter.Seq[Subtitle], error) {
+	call := c.newCall(ctx, opts)
+	showName, doc, err := call.fetchShowPage(showStr)
+	if err != nil {
+		return "", nil, err
+	}
+	show := Show{Name: showName}
+	show.Number, _ = parseEpisodeName(showName, documentURL(doc))
+	if err := call.checkEpisode(showStr, &show); err != nil {
+		return "", nil, err
+	}
+	return showName, func(yield func(Subtitle) bool) {
+		if err := call.parseAllSubtitles(doc, yield); err != nil {
+			call.warn(WarningMissingVersions, "unable to fetch all versions of %v: %v", showName, err)
+		}
+	}, nil
+}
+
+// All returns an iterator over the subtitles
+func (ss Subtitles) All() iter.Seq[Subtitle] {
+	return func(yield func(Subtitle) bool) {
+		for _, s := range ss {
+			if !yield(s) {
+				return
+			}
+		}
+	}
+}
+
+// GetSeasonSeq gets the subtitles of all episodes of a season of a show from Addic7ed website, like GetSeason, as a sequence
+// of the subtitles with the number of their episode.
+// The page of the season is fetched right away, but its rows are parsed lazily while ranging, in the order of the page,
+// so breaking out of the loop stops the parsing. A season served from the cache (see WithCache) is ranged over by episode.
+// Unlike GetSeason, a season without subtitle is an empty sequence rather than ErrNoSubtitlesYet.
+func (c *Client) GetSeasonSeq(show TVShow, season int, opts ...CallOption) (iter.Seq2[EpisodeNumber, Subtitle], error) {
+	return c.GetSeasonSeqContext(context.Background(), show, season, opts...)
+}
+
+// GetSeasonSeqContext is like GetSeasonSeq, with a context to cancel the search
+func (c *Client) GetSeasonSeqContext(ctx context.Context, show TVShow, season int, opts ...CallOption) (iter.Seq2[EpisodeNumber, Subtitle], error) {
+	call := c.newCall(ctx, opts)
+	if episodes, ok := c.cache.episodes(seasonKey(show.ID, season)); ok {
+		call.tracef("Season %v of show %v served from cache", season, show.Name)
+		return func(yield func(EpisodeNumber, Subtitle) bool) {
+			for _, episode := range episodes {
+				for _, s := range episode.Subtitles {
+					if !yield(episode.Number, s) {
+						return
+					}
+				}
+			}
+		}, nil
+	}
+	call.show = normalizeShowName(show.Name)
+	doc, err := call.createDocFromURL(c.seasonURL(show.ID, season))
+	if err != nil {
+		return nil, err
+	}
+	return func(yield func(EpisodeNumber, Subtitle) bool) {
+		call.parseSeasonRows(doc, func(row seasonRow) bool {
+			return yield(row.number, row.subtitle)
+		})
+	}, nil
+}
+
+// RecentSubtitlesSeq gets the subtitles recently added on Addic7ed in a given language, like RecentSubtitles, as a sequence.
+// The feed is fetched right away, but its items are parsed lazily while ranging, so breaking out of the loop, for example at
+// the first subtitle already seen by a poller, stops the parsing.
+func (c *Client) RecentSubtitlesSeq(lang string, opts ...CallOption) (iter.Seq[RecentSubtitle], error) {
+	return c.RecentSubtitlesSeqContext(context.Background(), lang, opts...)
+}
+
+// RecentSubtitlesSeqContext is like RecentSubtitlesSeq, with a context to cancel the request
+func (c *Client) RecentSubtitlesSeqContext(ctx context.Context, lang string, opts ...CallOption) (iter.Seq[RecentSubtitle], error) {
+	call := c.newCall(ctx, opts)
+	feed, err := call.fetchFeed()
+	if err != nil {
+		return nil, err
+	}
+	return func(yield func(RecentSubtitle) bool) {
+		call.parseFeed(feed, lang, yield)
+	}, nil
+}
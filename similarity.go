@@ -0,0 +1,90 @@
+package addic7ed
+
+import (
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	textdistance "github.com/masatana/go-textdistance"
+)
+
+// Similarity measures how similar two strings are, from 0 for no similarity at all to 1 for the same strings.
+// Different naming styles favor different similarities, see WordScorer and SimilarityScorer.
+type Similarity func(a, b string) float64
+
+// JaroWinkler is the Jaro-Winkler similarity, favoring strings with a common prefix, used by the default scorer
+func JaroWinkler(a, b string) float64 {
+	return textdistance.JaroWinklerDistance(a, b)
+}
+
+// LevenshteinRatio is the similarity of the Levenshtein distance between two strings, normalized by the length of the
+// longest one, so that a single different character costs less in long strings than in short ones
+func LevenshteinRatio(a, b string) float64 {
+	longest := max(utf8.RuneCountInString(a), utf8.RuneCountInString(b))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(textdistance.LevenshteinDistance(a, b))/float64(longest)
+}
+
+// TokenSetRatio is the similarity of the sets of words of two strings, regardless of their order, case and separators.
+// Strings are similar when the words of one are all in the other, like "BATV" and "720p.HDTV.x264-BATV": the common words
+// are compared with the common words followed by the other words of each string with LevenshteinRatio, taking the best ratio.
+func TokenSetRatio(a, b string) float64 {
+	wordsA, wordsB := wordSet(a), wordSet(b)
+	if len(wordsA) == 0 || len(wordsB) == 0 {
+		if len(wordsA) == len(wordsB) {
+			return 1
+		}
+		return 0
+	}
+	var common, onlyA, onlyB []string
+	for _, word := range wordsA {
+		if _, found := slices.BinarySearch(wordsB, word); found {
+			common = append(common, word)
+		} else {
+			onlyA = append(onlyA, word)
+		}
+	}
+	for _, word := range wordsB {
+		if _, found := slices.BinarySearch(wordsA, word); !found {
+			onlyB = append(onlyB, word)
+		}
+	}
+	intersection := strings.Join(common, " ")
+	withA := strings.TrimSpace(intersection + " " + strings.Join(onlyA, " "))
+	withB := strings.TrimSpace(intersection + " " + strings.Join(onlyB, " "))
+	ratio := LevenshteinRatio(withA, withB)
+	if intersection != "" {
+		ratio = max(ratio, LevenshteinRatio(intersection, withA), LevenshteinRatio(intersection, withB))
+	}
+	return ratio
+}
+
+// wordSet returns the sorted and deduplicated lowered words of a string
+func wordSet(s string) []string {
+	words := Words(strings.ToLower(s))
+	slices.Sort(words)
+	return slices.Compact(words)
+}
+
+// SimilarityScorer scores versions by the similarity of the whole version and filename, like TokenSetRatio, rather than
+// word by word like WordScorer. Tags are compared in their canonical form, like in the other scorers.
+type SimilarityScorer struct {
+	// Similarity compares the version and the filename, TokenSetRatio when nil
+	Similarity Similarity
+}
+
+// Score scores a version for a filename
+func (s SimilarityScorer) Score(fileName, version string) float64 {
+	similarity := s.Similarity
+	if similarity == nil {
+		similarity = TokenSetRatio
+	}
+	return similarity(strings.Join(canonicalTags(fileName), " "), strings.Join(canonicalTags(version), " "))
+}
+
+// NormalizedScore scores a version for a filename from 0 to 100, the similarity as a percentage
+func (s SimilarityScorer) NormalizedScore(fileName, version string) float64 {
+	return normalized(s.Score(fileName, version))
+}
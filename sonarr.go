@@ -0,0 +1,135 @@
+package addic7ed
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Sonarr is a Sonarr instance whose library is read through its API, see SonarrEpisodeFiles
+type Sonarr struct {
+	// URL is the base URL of Sonarr, like "http://localhost:8989"
+	URL string
+	// APIKey is the API key of Sonarr, found in its settings under General
+	APIKey string
+}
+
+// SonarrEpisodeFile is an episode file of a series monitored by Sonarr
+type SonarrEpisodeFile struct {
+	// Series is the title of the series in Sonarr
+	Series string
+	// Path is the absolute path of the file, as seen by Sonarr
+	Path string
+	// SceneName is the original release name of the file, if Sonarr knows it
+	SceneName string
+	// HasSubtitle is true when the file has a subtitle next to it, like "Show.S01E01.en.srt" for "Show.S01E01.mkv"
+	HasSubtitle bool
+}
+
+// Search returns the search of the subtitles of the file: its scene name when Sonarr knows it, as renamed files like
+// "Show - S01E01 - Title.mkv" lose their release, or its path otherwise
+func (f SonarrEpisodeFile) Search() string {
+	if f.SceneName != "" {
+		return f.SceneName
+	}
+	return f.Path
+}
+
+// sonarrSeries is the part of the series of the Sonarr API used to list their files
+type sonarrSeries struct {
+	ID        int    `json:"id"`
+	Title     string `json:"title"`
+	Monitored bool   `json:"monitored"`
+}
+
+// sonarrFile is the part of the episode files of the Sonarr API used to search their subtitles
+type sonarrFile struct {
+	Path      string `json:"path"`
+	SceneName string `json:"sceneName"`
+}
+
+// SonarrEpisodeFiles lists the episode files of the series monitored by a Sonarr instance with its API, so that the files
+// needing subtitles are known without scanning the file system. Files are checked for subtitles on the local file system,
+// at the paths given by Sonarr. Requests to Sonarr are sent with the HTTP client of the client, without its rate limits.
+func (c *Client) SonarrEpisodeFiles(ctx context.Context, sonarr Sonarr) ([]SonarrEpisodeFile, error) {
+	var series []sonarrSeries
+	if err := c.getSonarr(ctx, sonarr, "/api/v3/series", nil, &series); err != nil {
+		return nil, err
+	}
+	files := []SonarrEpisodeFile{}
+	for _, s := range series {
+		if !s.Monitored {
+			continue
+		}
+		var episodeFiles []sonarrFile
+		if err := c.getSonarr(ctx, sonarr, "/api/v3/episodefile", url.Values{"seriesId": {strconv.Itoa(s.ID)}}, &episodeFiles); err != nil {
+			return nil, err
+		}
+		for _, f := range episodeFiles {
+			files = append(files, SonarrEpisodeFile{Series: s.Title, Path: f.Path, SceneName: f.SceneName, HasSubtitle: hasSubtitle(f.Path)})
+		}
+	}
+	return files, nil
+}
+
+// getSonarr gets a resource of the Sonarr API and decodes it in v
+func (c *Client) getSonarr(ctx context.Context, sonarr Sonarr, path string, query url.Values, v interface{}) error {
+	link := strings.TrimSuffix(sonarr.URL, "/") + path
+	if len(query) > 0 {
+		link += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, "GET", link, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Api-Key", sonarr.APIKey)
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("unable to reach Sonarr: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unable to get %v from Sonarr: %w", path, &StatusError{StatusCode: resp.StatusCode})
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("unable to read %v from Sonarr: %w", path, err)
+	}
+	return nil
+}
+
+// Import downloads the best subtitle of the episode files of the series monitored by a Sonarr instance that don't have one
+// yet, see SonarrEpisodeFiles, so that the episodes imported before the webhook was declared get their subtitle too.
+// Only files inside Dirs are handled when set. Files are handled one at a time, like webhooks, calling OnEvent with each
+// result. It returns the results of the handled files, and an error only if the files can't be listed or the context is done.
+func (h *SonarrWebhook) Import(ctx context.Context, sonarr Sonarr) ([]WatchEvent, error) {
+	files, err := h.client.SonarrEpisodeFiles(ctx, sonarr)
+	if err != nil {
+		return nil, err
+	}
+	call := h.client.newCall(ctx, nil)
+	events := []WatchEvent{}
+	for _, f := range files {
+		if f.HasSubtitle || !filepath.IsAbs(f.Path) || !h.allowed(filepath.Clean(f.Path)) {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return events, err
+		}
+		call.infof("Searching %v subtitle of %v monitored by Sonarr, as %v", h.Language, f.Path, f.Search())
+		event := h.client.saveBest(ctx, filepath.Clean(f.Path), f.Search(), h.Language, h.Save)
+		if event.Err != nil {
+			call.warnf("Unable to get the subtitle of %v: %v", f.Path, event.Err)
+		}
+		if h.OnEvent != nil {
+			h.OnEvent(event)
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
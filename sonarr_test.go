@@ -0,0 +1,91 @@
+package addic7ed_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+// sonarrHandler serves the API of a Sonarr instance with a monitored series in dir and an unmonitored one
+func sonarrHandler(t *testing.T, dir string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "key" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.URL.Path == "/api/v3/series":
+			w.Write([]byte(`[{"id": 1, "title": "Shameless (US)", "monitored": true}, {"id": 2, "title": "Ended", "monitored": false}]`))
+		case r.URL.Path == "/api/v3/episodefile" && r.URL.Query().Get("seriesId") == "1":
+			fmt.Fprintf(w, `[{"id": 10, "path": %q, "sceneName": "Shameless.US.S08E11.720p.HDTV.x264-BATV"},
+				{"id": 11, "path": %q, "sceneName": ""}, {"id": 12, "path": "/elsewhere/Shameless.US.S08E12.mkv"}]`,
+				filepath.Join(dir, "Season 8", "Shameless (US) - S08E11 - A Gallagher Pedicure.mkv"),
+				filepath.Join(dir, "Season 8", "Shameless.US.S08E10.720p.HDTV.x264-BATV.mkv"))
+		default:
+			t.Errorf("unexpected request to Sonarr %v", r.URL)
+			http.NotFound(w, r)
+		}
+	})
+}
+
+func TestSonarrImport(t *testing.T) {
+	const srt = "1\n00:00:01,000 --> 00:00:02,000\nHello\n"
+	dir := t.TempDir()
+	writeFiles(t, dir, "Season 8/Shameless (US) - S08E11 - A Gallagher Pedicure.mkv",
+		"Season 8/Shameless.US.S08E10.720p.HDTV.x264-BATV.mkv", "Season 8/Shameless.US.S08E10.720p.HDTV.x264-BATV.en.srt")
+	sonarr := sonarrHandler(t, dir)
+	addic7edHandler := episodeHandler(t, nil)
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Host == "sonarr.local":
+			sonarr.ServeHTTP(w, r)
+		case strings.HasPrefix(r.URL.Path, "/original/"), strings.HasPrefix(r.URL.Path, "/updated/"):
+			w.Write([]byte(srt))
+		default:
+			addic7edHandler.ServeHTTP(w, r)
+		}
+	})}}))
+
+	files, err := c.SonarrEpisodeFiles(context.Background(), addic7ed.Sonarr{URL: "http://sonarr.local/", APIKey: "key"})
+	assert.NoError(t, err)
+	if assert.Len(t, files, 3) {
+		assert.Equal(t, "Shameless (US)", files[0].Series)
+		assert.Equal(t, "Shameless.US.S08E11.720p.HDTV.x264-BATV", files[0].Search())
+		assert.False(t, files[0].HasSubtitle)
+		assert.Equal(t, files[1].Path, files[1].Search())
+		assert.True(t, files[1].HasSubtitle)
+	}
+
+	// Only the files without subtitle inside the handled directories are imported
+	webhook := c.NewSonarrWebhook("en")
+	webhook.Dirs = []string{dir}
+	var notified []string
+	webhook.OnEvent = func(event addic7ed.WatchEvent) {
+		notified = append(notified, event.Video)
+	}
+	events, err := webhook.Import(context.Background(), addic7ed.Sonarr{URL: "http://sonarr.local", APIKey: "key"})
+	assert.NoError(t, err)
+	if assert.Len(t, events, 1) {
+		assert.NoError(t, events[0].Err)
+		assert.Equal(t, "Shameless (US) - 08x11 - A Gallagher Pedicure", events[0].Name)
+		assert.Equal(t, filepath.Join(dir, "Season 8", "Shameless (US) - S08E11 - A Gallagher Pedicure.en.srt"), events[0].Path)
+		content, err := os.ReadFile(events[0].Path)
+		assert.NoError(t, err)
+		assert.Equal(t, srt, string(content))
+		assert.Equal(t, []string{events[0].Video}, notified)
+	}
+
+	_, err = webhook.Import(context.Background(), addic7ed.Sonarr{URL: "http://sonarr.local", APIKey: "wrong"})
+	var statusErr *addic7ed.StatusError
+	assert.True(t, errors.As(err, &statusErr), "unexpected error %v", err)
+	assert.Equal(t, http.StatusUnauthorized, statusErr.StatusCode)
+}
@@ -0,0 +1,109 @@
+package addic7ed
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cue is a subtitle cue of a SRT file
+type cue struct {
+	start time.Duration
+	end   time.Duration
+	lines []string
+}
+
+var srtCueTimingRegexp = regexp.MustCompile(`^(\d{1,2}):(\d{2}):(\d{2})[,.](\d{1,3})\s*-->\s*(\d{1,2}):(\d{2}):(\d{2})[,.](\d{1,3})`)
+
+// parseSRT parses the cues of a SRT file. Cues with broken timings are skipped
+func parseSRT(r io.Reader) ([]cue, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, utf8BOM)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(data)+1)
+	cues := []cue{}
+	var current *cue
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		// The text of cues is kept as is, with its bidirectional control characters, so that right-to-left text is never reordered
+		trimmed := trimSpaceAndBidi(line)
+		if m := srtCueTimingRegexp.FindStringSubmatch(trimmed); m != nil {
+			cues = append(cues, cue{start: srtTimestamp(m[1:5]), end: srtTimestamp(m[5:9])})
+			current = &cues[len(cues)-1]
+			continue
+		}
+		if trimmed == "" {
+			current = nil
+			continue
+		}
+		if current != nil {
+			current.lines = append(current.lines, line)
+		}
+	}
+	return cues, scanner.Err()
+}
+
+// srtTimestamp converts hours, minutes, seconds and milliseconds to a duration
+func srtTimestamp(parts []string) time.Duration {
+	h, _ := strconv.Atoi(parts[0])
+	m, _ := strconv.Atoi(parts[1])
+	s, _ := strconv.Atoi(parts[2])
+	// Milliseconds may be written with less than 3 digits
+	ms, _ := strconv.Atoi((parts[3] + "00")[:3])
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute + time.Duration(s)*time.Second + time.Duration(ms)*time.Millisecond
+}
+
+// formatSRTTimestamp formats a duration as a SRT timestamp, like "01:02:03,456"
+func formatSRTTimestamp(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	h := d / time.Hour
+	m := (d % time.Hour) / time.Minute
+	s := (d % time.Minute) / time.Second
+	ms := (d % time.Second) / time.Millisecond
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", h, m, s, ms)
+}
+
+// writeSRT writes cues as a SRT file, numbering cues from 1
+func writeSRT(w io.Writer, cues []cue) error {
+	bw := bufio.NewWriter(w)
+	for i, c := range cues {
+		fmt.Fprintf(bw, "%d\n%s --> %s\n", i+1, formatSRTTimestamp(c.start), formatSRTTimestamp(c.end))
+		for _, line := range c.lines {
+			fmt.Fprintln(bw, line)
+		}
+		fmt.Fprintln(bw)
+	}
+	return bw.Flush()
+}
+
+// ConcatSRT concatenates SRT subtitles into one SRT subtitle written to w.
+// offsets are the start times of each part in the concatenated video, used to retime the cues of each part.
+// It is used for files covering multiple episodes, like "Show.S01E01-E02.mkv".
+func ConcatSRT(w io.Writer, parts []io.Reader, offsets []time.Duration) error {
+	if len(parts) != len(offsets) {
+		return fmt.Errorf("got %v offsets for %v parts", len(offsets), len(parts))
+	}
+	all := []cue{}
+	for i, part := range parts {
+		cues, err := parseSRT(part)
+		if err != nil {
+			return newError(CodeParseFailure, err, "Unable to read part %v of the subtitle", i+1)
+		}
+		for _, c := range cues {
+			c.start += offsets[i]
+			c.end += offsets[i]
+			all = append(all, c)
+		}
+	}
+	return writeSRT(w, all)
+}
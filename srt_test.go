@@ -0,0 +1,30 @@
+package addic7ed_test
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+func TestConcatSRT(t *testing.T) {
+	part1 := "\xef\xbb\xbf1\r\n00:00:01,000 --> 00:00:02,500\r\nHello\r\n\r\n2\r\n00:00:03,000 --> 00:00:04,000\r\nWorld\r\n"
+	part2 := "1\n00:00:01,000 --> 00:00:02,000\n- Second\n- Episode\n"
+	var out bytes.Buffer
+	err := addic7ed.ConcatSRT(&out, []io.Reader{strings.NewReader(part1), strings.NewReader(part2)}, []time.Duration{0, 42*time.Minute + 500*time.Millisecond})
+	assert.NoError(t, err)
+	expected := "1\n00:00:01,000 --> 00:00:02,500\nHello\n\n" +
+		"2\n00:00:03,000 --> 00:00:04,000\nWorld\n\n" +
+		"3\n00:42:01,500 --> 00:42:02,500\n- Second\n- Episode\n\n"
+	assert.Equal(t, expected, out.String())
+}
+
+func TestConcatSRTWithMissingOffsets(t *testing.T) {
+	err := addic7ed.ConcatSRT(io.Discard, []io.Reader{strings.NewReader("")}, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,113 @@
+package addic7ed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var teamRegexp = regexp.MustCompile(`(?i)translated by\s+(?:team\s+)?([^·|\n]+)`)
+
+// Translation is a subtitle being translated on Addic7ed, for a version and a language
+type Translation struct {
+	// Language is the Addic7ed language of the translation
+	Language string
+	// Version is the version of the translated subtitle
+	Version string
+	// Completion is the percentage of the subtitle already translated
+	Completion float64
+	// Team is the name of the team translating the subtitle, if shown
+	Team string
+	// Link is the link to download the partial translation, empty when Addic7ed does not allow downloading it yet
+	Link string
+}
+
+func (t Translation) String() string {
+	s := fmt.Sprintf("%v is %v%% translated (%v)", t.Language, strconv.FormatFloat(t.Completion, 'f', -1, 64), t.Version)
+	if t.Team != "" {
+		s += " by " + t.Team
+	}
+	return s
+}
+
+// parseTranslations finds the translations in progress of the page of an episode.
+// Only the languages showing a percentage of completion are translations in progress, even if they have no download link.
+func (c *call) parseTranslations(doc *goquery.Document) []Translation {
+	var translations []Translation
+	doc.Find(".tabel95").Each(func(i int, s *goquery.Selection) {
+		if v, ok := s.Attr("align"); !ok || v != "center" {
+			return
+		}
+		version := CleanVersion(strings.TrimSpace(s.Find(".NewsTitle").Text()))
+		s.Find(".language").Each(func(j int, ss *goquery.Selection) {
+			row := ss.Parent()
+			if !completionRegexp.MatchString(row.Text()) {
+				return
+			}
+			completion := parseCompletion(row.Text())
+			if completion >= 100 {
+				return
+			}
+			translation := Translation{
+				Language:   strings.TrimSpace(ss.Text()),
+				Version:    version,
+				Completion: completion,
+			}
+			if details := row.Next(); details.Find(".language").Length() == 0 {
+				if m := teamRegexp.FindStringSubmatch(details.Text()); m != nil {
+					translation.Team = strings.TrimSpace(m[1])
+				}
+			}
+			if href, ok := row.Find(".buttonDownload").Attr("href"); ok {
+				translation.Link = c.url(strings.TrimSpace(href))
+			}
+			translations = append(translations, translation)
+		})
+	})
+	return translations
+}
+
+// WaitForCompletion waits for a translation in progress of an episode to be completed, and returns the completed subtitle.
+// The episode is searched again following the ladder of the show (see WithLadder and WithShowLadder), bypassing the cache,
+// until a completed subtitle of the language and version of the translation is found, or the context is done.
+// Errors other than ErrNoSubtitlesYet are returned right away, and ladders that are empty or have delays of 0 are refused.
+func (c *Client) WaitForCompletion(ctx context.Context, showStr string, translation Translation, opts ...CallOption) (Subtitle, error) {
+	if err := c.checkLadder(showStr); err != nil {
+		return Subtitle{}, err
+	}
+	completed := And(WithLanguage(translation.Language), WithVersion(translation.Version), WithCompleted())
+	for retry := 0; ; retry++ {
+		call := c.newCall(ctx, opts)
+		show, err := fetchSearch(showStr)(call)
+		if err != nil && !errors.Is(err, ErrNoSubtitlesYet) {
+			return Subtitle{}, err
+		}
+		if subtitles := show.Subtitles.Filter(completed); len(subtitles) > 0 {
+			return bestOfVersion(subtitles), nil
+		}
+		call.infof("Translation %v is not completed yet", translation)
+		timer := time.NewTimer(c.RetryDelay(showStr, retry))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return Subtitle{}, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// DownloadWhenCompleted waits for a translation in progress of an episode to be completed like WaitForCompletion,
+// and downloads the completed subtitle to a given path. Run it in a goroutine to subscribe to the translation.
+func (c *Client) DownloadWhenCompleted(ctx context.Context, showStr string, translation Translation, path string, opts ...CallOption) (Subtitle, error) {
+	subtitle, err := c.WaitForCompletion(ctx, showStr, translation, opts...)
+	if err != nil {
+		return Subtitle{}, err
+	}
+	return subtitle, subtitle.DownloadToContext(ctx, path)
+}
@@ -0,0 +1,111 @@
+package addic7ed_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+func TestParseEpisodePageTranslations(t *testing.T) {
+	f, err := os.Open("testdata/episode.html")
+	assert.NoError(t, err)
+	defer f.Close()
+
+	show, err := addic7ed.ParseEpisodePage(f)
+	assert.NoError(t, err)
+	assert.Equal(t, []addic7ed.Translation{
+		{Language: "English", Version: "WEB.x264-TBS", Completion: 67.19, Link: "https://www.addic7ed.com/original/131967/2"},
+		{Language: "Spanish", Version: "WEB.x264-TBS", Completion: 62.5, Team: "SubES"},
+	}, show.Translations)
+	assert.Equal(t, "Spanish is 62.5% translated (WEB.x264-TBS) by SubES", show.Translations[1].String())
+	assert.Empty(t, show.Subtitles.Filter(addic7ed.WithLanguage("Spanish")))
+}
+
+func TestSearchBestWarnsAboutTranslationsInProgress(t *testing.T) {
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{episodeHandler(t, nil)}}))
+	var warnings []addic7ed.Warning
+	_, _, err := c.SearchBest("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", "Spanish", addic7ed.WithWarnings(func(w addic7ed.Warning) {
+		warnings = append(warnings, w)
+	}))
+	assert.True(t, errors.Is(err, addic7ed.ErrNoSubtitlesForLanguage), "unexpected error %v", err)
+	if assert.Len(t, warnings, 1) {
+		assert.Equal(t, addic7ed.WarningTranslationInProgress, warnings[0].Code)
+		assert.Contains(t, warnings[0].Message, "62.5%")
+	}
+}
+
+func TestSearchBestWarnsAboutIncompleteSubtitles(t *testing.T) {
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{episodeHandler(t, nil)}}))
+	var warnings []addic7ed.Warning
+	_, subtitle, err := c.SearchBest("Shameless.US.S08E11.720p.WEB.x264-TBS", "English", addic7ed.WithWarnings(func(w addic7ed.Warning) {
+		warnings = append(warnings, w)
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "WEB.x264-TBS", subtitle.Version)
+	if assert.Len(t, warnings, 1) {
+		assert.Equal(t, addic7ed.WarningIncompleteSubtitle, warnings[0].Code)
+		assert.Contains(t, warnings[0].Message, "67.19%")
+	}
+
+	// Completed subtitles are picked without warning
+	warnings = nil
+	_, _, err = c.SearchBest("Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", "English", addic7ed.WithWarnings(func(w addic7ed.Warning) {
+		warnings = append(warnings, w)
+	}))
+	assert.NoError(t, err)
+	assert.Empty(t, warnings)
+}
+
+// completedAfter serves the episode fixture with the Spanish translation completed after the given number of searches
+func completedAfter(t *testing.T, searches int64) http.Handler {
+	page, err := os.ReadFile("testdata/episode.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+	completed := strings.Replace(string(page), "<td><b>62.5% Completed</b></td>\n        <td></td>",
+		`<td><b>Completed</b></td>\n        <td><a class="buttonDownload" href="/original/131967/3">Download</a></td>`, 1)
+	var served int64
+	handler := episodeHandler(t, map[string]string{"/original/131967/3": "1\n00:00:01,000 --> 00:00:02,000\nHola\n"})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/srch.php" && atomic.AddInt64(&served, 1) > searches {
+			w.Write([]byte(completed))
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func TestDownloadWhenCompleted(t *testing.T) {
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{completedAfter(t, 2)}}),
+		addic7ed.WithLadder(addic7ed.Ladder{time.Millisecond}))
+	translation := addic7ed.Translation{Language: "Spanish", Version: "WEB.x264-TBS", Completion: 62.5}
+
+	path := filepath.Join(t.TempDir(), "sub.es.srt")
+	subtitle, err := c.DownloadWhenCompleted(context.Background(), "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", translation, path)
+	assert.NoError(t, err)
+	assert.Equal(t, "Spanish", subtitle.Language)
+	assert.True(t, subtitle.IsCompleted())
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "Hola")
+}
+
+func TestWaitForCompletionIsCancelled(t *testing.T) {
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{episodeHandler(t, nil)}}),
+		addic7ed.WithLadder(addic7ed.Ladder{time.Hour}))
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	translation := addic7ed.Translation{Language: "Spanish", Version: "WEB.x264-TBS", Completion: 62.5}
+	_, err := c.WaitForCompletion(ctx, "Shameless.US.S08E11.720p.HDTV.x264-BATV[ettv]", translation)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded), "unexpected error %v", err)
+}
@@ -0,0 +1,42 @@
+package addic7ed
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// maxCapturedPage is the size of the beginning of an HTML page kept in an InvalidSubtitleError
+const maxCapturedPage = 4096
+
+// InvalidSubtitleError details a downloaded file that is not a subtitle. It is the underlying error of ErrInvalidSubtitle errors
+type InvalidSubtitleError struct {
+	// ContentType is the Content-Type of the file, as sent by Addic7ed
+	ContentType string
+	// HTML is the beginning of the page, when Addic7ed served an HTML page like an error page or a redirection. Empty otherwise
+	HTML string
+}
+
+func (e *InvalidSubtitleError) Error() string {
+	if e.HTML != "" {
+		return fmt.Sprintf("Addic7ed served an HTML page of type %q", e.ContentType)
+	}
+	return fmt.Sprintf("file of type %q is not of a known subtitle format", e.ContentType)
+}
+
+// checkSubtitle checks that a downloaded file is a subtitle of a known format, and not a page served by Addic7ed instead
+func checkSubtitle(contentType string, data []byte) error {
+	if format := DetectFormat(data); format != FormatUnknown {
+		return nil
+	}
+	invalid := &InvalidSubtitleError{ContentType: contentType}
+	if isHTML(contentType, data) {
+		invalid.HTML = string(data[:min(len(data), maxCapturedPage)])
+	}
+	return newError(CodeInvalidSubtitle, invalid, "downloaded file is not a subtitle")
+}
+
+// isHTML checks whether a downloaded file is an HTML page, from its Content-Type or its first character
+func isHTML(contentType string, data []byte) bool {
+	return strings.HasPrefix(strings.ToLower(contentType), "text/html") || bytes.HasPrefix(bytes.TrimSpace(bytes.TrimPrefix(data, utf8BOM)), []byte("<"))
+}
@@ -0,0 +1,147 @@
+package addic7ed
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Version is the parsed version of a subtitle.
+// On Addic7ed, versions are free texts like "720p.WEB-DL.DD5.1.H264-NTb", usually containing the name of the team who ripped the tv show
+type Version struct {
+	// Raw is the version as seen in the website
+	Raw string
+	// Group is the team who ripped the tv show, if found. Example: "NTb"
+	Group string
+	// Source is the source of the rip, if found. Example: "WEB-DL", "HDTV", "BluRay"
+	Source string
+	// Resolution is the resolution of the rip, if found. Example: "720p"
+	Resolution string
+	// Flags are the other known tags of the version. Example: "AMZN", "H264", "PROPER"
+	Flags []string
+}
+
+var resolutionRegexp = regexp.MustCompile(`(?i)^(\d{3,4}[pi]|4k|uhd)$`)
+
+// sources maps lowered source tags to their usual spelling
+var sources = map[string]string{
+	"hdtv":   "HDTV",
+	"pdtv":   "PDTV",
+	"sdtv":   "SDTV",
+	"web":    "WEB",
+	"webdl":  "WEB-DL",
+	"webrip": "WEBRip",
+	"bluray": "BluRay",
+	"bdrip":  "BDRip",
+	"brrip":  "BRRip",
+	"dvdrip": "DVDRip",
+	"hdrip":  "HDRip",
+}
+
+// flags are the lowered tags that are known not to be release groups
+var flags = map[string]bool{
+	"x264": true, "h264": true, "x265": true, "h265": true, "hevc": true, "avc": true, "xvid": true,
+	"aac": true, "ac3": true, "dd5": true, "ddp5": true, "dd2": true, "dts": true, "atmos": true,
+	"proper": true, "repack": true, "internal": true, "real": true, "rerip": true,
+	"amzn": true, "amz": true, "nf": true, "hulu": true, "dsnp": true, "hmax": true, "atvp": true, "itunes": true,
+	"10bit": true, "hdr": true, "dl": true, "rip": true,
+}
+
+// splitTags are the tags split in two words by their separator, like "WEB-DL" or "H.264", lowered
+var splitTags = map[[2]string]bool{
+	{"web", "dl"}: true, {"web", "rip"}: true, {"blu", "ray"}: true,
+	{"h", "264"}: true, {"h", "265"}: true, {"x", "264"}: true, {"x", "265"}: true,
+}
+
+// tags splits a version or a filename in tags, joining the words of tags split by their separator, like "WEB-DL" in "WEBDL".
+// It is the tokenization shared by the parsing of versions and the scoring of versions against filenames.
+func tags(s string) []string {
+	words := Words(s)
+	tags := make([]string, 0, len(words))
+	for i := 0; i < len(words); i++ {
+		if i+1 < len(words) && splitTags[[2]string{strings.ToLower(words[i]), strings.ToLower(words[i+1])}] {
+			tags = append(tags, words[i]+words[i+1])
+			i++
+			continue
+		}
+		tags = append(tags, words[i])
+	}
+	return tags
+}
+
+// canonicalTags returns the tags of a version or a filename in their canonical form, lowered
+func canonicalTags(s string) []string {
+	tags := tags(s)
+	for i, tag := range tags {
+		tags[i] = strings.ToLower(tag)
+	}
+	return tags
+}
+
+// ParseVersion parses a version as seen on the website
+func ParseVersion(raw string) Version {
+	v := Version{Raw: raw}
+	var others []string
+	for _, word := range tags(raw) {
+		lowered := strings.ToLower(word)
+		switch {
+		case sources[lowered] != "":
+			if v.Source == "" {
+				v.Source = sources[lowered]
+			}
+		case resolutionRegexp.MatchString(word):
+			if v.Resolution == "" {
+				v.Resolution = strings.ToLower(word)
+			}
+		case flags[lowered]:
+			v.Flags = append(v.Flags, strings.ToUpper(word))
+		case isNumber(word):
+			// Numbers are usually parts of other tags, like "5.1" in "DD5.1"
+		default:
+			others = append(others, word)
+		}
+	}
+	// The team is usually given last, like in "WEB-DL-NTb"
+	if len(others) > 0 {
+		v.Group = others[len(others)-1]
+		v.Flags = append(v.Flags, others[:len(others)-1]...)
+	}
+	return v
+}
+
+// Normalized returns the version in a form that does not depend on case and separators.
+// "WEB-DL 720p" and "web.dl.720P" have the same normalized form
+func (v Version) Normalized() string {
+	return strings.ToLower(strings.Join(Words(v.Raw), "."))
+}
+
+// Canonical returns the version in a form that does not depend on case, separators and order of tags.
+// "720p.WEB-DL.H.264-NTb", "NTb WEBDL 720P H264" have the same canonical form. Filenames can be canonicalized the same way, see CanonicalVersion
+func (v Version) Canonical() string {
+	return CanonicalVersion(v.Raw)
+}
+
+// CanonicalVersion returns the canonical form of a version or of a filename, see Version.Canonical
+func CanonicalVersion(s string) string {
+	tags := canonicalTags(s)
+	slices.Sort(tags)
+	return strings.Join(slices.Compact(tags), ".")
+}
+
+// Equal checks whether two versions are the same, ignoring case, separators and order of tags
+func (v Version) Equal(other Version) bool {
+	return v.Canonical() == other.Canonical()
+}
+
+func (v Version) String() string {
+	return v.Raw
+}
+
+func isNumber(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
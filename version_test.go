@@ -0,0 +1,63 @@
+package addic7ed_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+func TestParseVersion(t *testing.T) {
+	var versiontests = []struct {
+		in                 string
+		expectedGroup      string
+		expectedSource     string
+		expectedResolution string
+	}{
+		{"BATV", "BATV", "", ""},
+		{"480p.WEB-DL", "", "WEB-DL", "480p"},
+		{"WEBRip.x264-STRiFE", "STRiFE", "WEBRip", ""},
+		{"AMZN.WEBRip", "", "WEBRip", ""},
+		{"720p.WEB-DL.DD5.1.H264-NTb", "NTb", "WEB-DL", "720p"},
+		{"", "", "", ""},
+	}
+
+	for _, test := range versiontests {
+		v := addic7ed.ParseVersion(test.in)
+		assert.Equal(t, test.in, v.Raw)
+		assert.Equal(t, test.expectedGroup, v.Group, test.in)
+		assert.Equal(t, test.expectedSource, v.Source, test.in)
+		assert.Equal(t, test.expectedResolution, v.Resolution, test.in)
+	}
+}
+
+func TestVersionEqual(t *testing.T) {
+	assert.True(t, addic7ed.ParseVersion("WEB-DL 720p").Equal(addic7ed.ParseVersion("web.dl.720P")))
+	assert.False(t, addic7ed.ParseVersion("WEB-DL 720p").Equal(addic7ed.ParseVersion("WEB-DL 1080p")))
+}
+
+func TestCanonicalVersion(t *testing.T) {
+	assert.Equal(t, "720p.h264.ntb.webdl", addic7ed.CanonicalVersion("720p.WEB-DL.H.264-NTb"))
+	assert.Equal(t, addic7ed.CanonicalVersion("720p.WEB-DL.H.264-NTb"), addic7ed.ParseVersion("NTb WEBDL 720P H264").Canonical())
+	assert.True(t, addic7ed.ParseVersion("HDTV.x264-BATV").Equal(addic7ed.ParseVersion("BATV x.264 hdtv")))
+	assert.Equal(t, "H264", addic7ed.ParseVersion("720p.WEB-DL.H.264-NTb").Flags[0])
+	assert.Equal(t, "NTb", addic7ed.ParseVersion("720p.WEB-DL.H.264-NTb").Group)
+}
+
+func TestScoreIgnoresFormatting(t *testing.T) {
+	scorer := addic7ed.JaroWinklerScorer{}
+	file := "Show.S01E01.720p.WEB-DL.H.264-NTb"
+	assert.Equal(t, scorer.Score(file, "720p.WEBDL.H264-NTb"), scorer.Score(file, "720p.WEB-DL.H.264-NTb"))
+	assert.Greater(t, scorer.Score(file, "WEBDL.H264-NTb"), scorer.Score(file, "WEBRip.x265-STRiFE"))
+}
+
+func TestFilterGroup(t *testing.T) {
+	subs := addic7ed.Subtitles{
+		{Version: "720p.WEB-DL.DD5.1.H264-NTb", Language: "French", Link: "http://addic7ed.com/A-good-show"},
+		{Version: "WEBRip.x264-STRiFE", Language: "French", Link: "http://addic7ed.com/A-good-show"},
+		{Version: "ntb", Language: "English", Link: "http://addic7ed.com/A-good-show"},
+	}
+	assert.Len(t, subs.Filter(addic7ed.WithGroup("NTb")), 2)
+	assert.Len(t, subs.Filter(addic7ed.WithVersion("webrip-x264-strife")), 1)
+}
@@ -0,0 +1,62 @@
+package addic7ed
+
+import "fmt"
+
+// WarningCode identifies the kind of a warning
+type WarningCode string
+
+const (
+	// WarningAmbiguousShow is raised when a search matches multiple shows and one of them was picked
+	WarningAmbiguousShow WarningCode = "ambiguous_show"
+	// WarningNoMatchingVersion is raised when no version of the subtitles matches the search, so the best subtitle was picked arbitrarily
+	WarningNoMatchingVersion WarningCode = "no_matching_version"
+	// WarningMissingVersions is raised when some versions of an episode could not be fetched
+	WarningMissingVersions WarningCode = "missing_versions"
+	// WarningTranslationInProgress is raised when no subtitle of the searched language is available yet, but a translation is in progress
+	WarningTranslationInProgress WarningCode = "translation_in_progress"
+	// WarningPartialResult is raised when a best-effort call returns what it parsed before its end, see WithBestEffort
+	WarningPartialResult WarningCode = "partial_result"
+	// WarningUnparsedField is raised when some fields of a subtitle, like its language, could not be parsed, see Subtitle.Warnings
+	WarningUnparsedField WarningCode = "unparsed_field"
+	// WarningIncompleteSubtitle is raised when the best subtitle of a search is not completely translated yet, see Subtitle.Completion
+	WarningIncompleteSubtitle WarningCode = "incomplete_subtitle"
+	// WarningEncodingGuessed is raised when a downloaded subtitle is neither UTF-8 nor UTF-16, and was converted from the
+	// legacy encoding guessed from its language, see DownloadResult.Warnings
+	WarningEncodingGuessed WarningCode = "encoding_guessed"
+	// WarningEpisodeMismatch is raised instead of ErrEpisodeMismatch with WithEpisodeMismatchWarnings
+	WarningEpisodeMismatch WarningCode = "episode_mismatch"
+)
+
+// Warning is a non-fatal issue that happened during a call, that applications may want to show to their users
+type Warning struct {
+	// Code identifies the kind of warning
+	Code WarningCode
+	// Message is the english description of the warning
+	Message string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%v: %v", w.Code, w.Message)
+}
+
+// WithWarnings gives the warnings of a call to the handler as soon as they happen
+// Warnings of SearchAll are also given in Show.Warnings
+func WithWarnings(handler func(w Warning)) CallOption {
+	return func(c *call) {
+		c.warningHandler = handler
+	}
+}
+
+// warn raises a warning for the call, and returns it
+func (c *call) warn(code WarningCode, message string, params ...interface{}) Warning {
+	w := Warning{
+		Code:    code,
+		Message: fmt.Sprintf(message, params...),
+	}
+	c.warnf("Warning %v", w)
+	c.warnings = append(c.warnings, w)
+	if c.warningHandler != nil {
+		c.warningHandler(w)
+	}
+	return w
+}
@@ -0,0 +1,360 @@
+package addic7ed
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultWatchSettle is how long a new video must stay unchanged before its subtitle is searched, see Watcher
+const DefaultWatchSettle = 10 * time.Second
+
+// DefaultWatchPoll is how often the file systems of a Watcher are scanned for new videos, see Watcher.AddFS
+const DefaultWatchPoll = time.Minute
+
+// errWatchFSWithoutSave is returned when file systems are watched without Save, as subtitles can't be written to an fs.FS
+var errWatchFSWithoutSave = errors.New("watching a file system needs Save to write the subtitles")
+
+// WatchEvent is the result of the search and download of the subtitle of a new video, see Watcher
+type WatchEvent struct {
+	// Video is the path of the new video
+	Video string
+	// Name is the name of the episode on Addic7ed, empty if the episode was not found
+	Name string
+	// Subtitle is the best subtitle of the video
+	Subtitle Subtitle
+	// Path is the path of the saved subtitle
+	Path string
+	// Err is the error of the search or of the download, if any
+	Err error
+}
+
+// Watcher watches directories for new videos, and drops their best subtitle next to them.
+// Videos are only searched once they stayed unchanged for Settle, so that videos still being copied or downloaded are not searched.
+// Videos that already have a subtitle next to them, like "Show.S01E01.srt" or "Show.S01E01.en.srt" for "Show.S01E01.mkv", are skipped.
+// Local directories are watched with file system notifications, and file systems added with AddFS, like remote shares, are scanned
+// every Poll.
+type Watcher struct {
+	// Settle is how long a new video must stay unchanged before its subtitle is searched, DefaultWatchSettle when zero
+	Settle time.Duration
+	// Poll is how often the file systems added with AddFS are scanned for new videos, DefaultWatchPoll when zero
+	Poll time.Duration
+	// Save saves the best subtitle of a video, and returns the path of the saved file.
+	// When nil, subtitles are downloaded next to their video with DownloadAlongsideContext, with the language suffix.
+	Save func(ctx context.Context, video, name string, s Subtitle) (string, error)
+	// OnEvent, when set, is called with the result of each new video, from one goroutine at a time
+	OnEvent func(WatchEvent)
+
+	client *Client
+	// dirs are the languages of the watched directories, by directory
+	dirs map[string]string
+	// filesystems are the file systems scanned for new videos, see AddFS
+	filesystems []watchedFS
+	// eventMu serializes the calls to OnEvent
+	eventMu sync.Mutex
+}
+
+// NewWatcher creates a watcher of directories using the client, see Watcher
+func (c *Client) NewWatcher() *Watcher {
+	return &Watcher{client: c, dirs: map[string]string{}}
+}
+
+// Add watches a directory and its subdirectories, searching the subtitles of their videos in a language, by name or code.
+// A directory inside another watched directory gets its own language, like a "French" folder in a library of English shows.
+// Directories are added before running the watcher.
+func (w *Watcher) Add(dir, lang string) error {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(abs)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return &fs.PathError{Op: "watch", Path: dir, Err: fs.ErrInvalid}
+	}
+	w.dirs[abs] = lang
+	return nil
+}
+
+// watchedFS is a file system scanned for new videos, with the language of their subtitles
+type watchedFS struct {
+	fsys fs.FS
+	lang string
+}
+
+// AddFS watches a file system, searching the subtitles of its videos in a language, by name or code. It lets the watcher
+// run apart from the storage of the videos, through an fs.FS adapter of a remote file system like SFTP or SMB.
+// As notifications don't reach through such adapters, the file system is scanned every Poll, the videos found by the first
+// scan being the existing ones. Videos are given to Save with their slash-separated path in the file system, and Save must
+// be set to write their subtitles, as an fs.FS can't be written to.
+// File systems are added before running the watcher.
+func (w *Watcher) AddFS(fsys fs.FS, lang string) error {
+	info, err := fs.Stat(fsys, ".")
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return &fs.PathError{Op: "watch", Path: ".", Err: fs.ErrInvalid}
+	}
+	w.filesystems = append(w.filesystems, watchedFS{fsys: fsys, lang: lang})
+	return nil
+}
+
+// languageOf returns the language of a video, from the closest watched directory containing it
+func (w *Watcher) languageOf(video string) string {
+	lang, closest := "", ""
+	for dir, dirLang := range w.dirs {
+		if isInside(dir, video) && len(dir) > len(closest) {
+			lang, closest = dirLang, dir
+		}
+	}
+	return lang
+}
+
+// isInside checks whether a path is inside a directory, or is the directory itself
+func isInside(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// Run watches the directories until the context is done, and returns nil then.
+// It returns an error if the directories can't be watched.
+func (w *Watcher) Run(ctx context.Context) error {
+	settle := w.Settle
+	if settle <= 0 {
+		settle = DefaultWatchSettle
+	}
+	if len(w.filesystems) > 0 && w.Save == nil {
+		return errWatchFSWithoutSave
+	}
+	call := w.client.newCall(ctx, nil)
+	notifier, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer notifier.Close()
+	for dir := range w.dirs {
+		if err := watchTree(notifier, dir, nil); err != nil {
+			return err
+		}
+	}
+
+	// pending are the new videos waiting to settle, with the time of their last change
+	pending := map[string]time.Time{}
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for _, watched := range w.filesystems {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.poll(ctx, call, watched, settle, &wg)
+		}()
+	}
+	ticker := time.NewTicker(settle / 4)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-notifier.Errors:
+			call.warnf("Error watching directories: %v", err)
+		case event := <-notifier.Events:
+			switch {
+			case event.Has(fsnotify.Remove) || event.Has(fsnotify.Rename):
+				delete(pending, event.Name)
+			case event.Has(fsnotify.Create):
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					// Videos moved with their directory don't get their own events
+					err := watchTree(notifier, event.Name, func(video string) {
+						pending[video] = time.Now()
+					})
+					if err != nil {
+						call.warnf("Unable to watch directory %v: %v", event.Name, err)
+					}
+					continue
+				}
+				fallthrough
+			case event.Has(fsnotify.Write):
+				if IsVideo(event.Name) {
+					pending[event.Name] = time.Now()
+				}
+			}
+		case now := <-ticker.C:
+			for video, changed := range pending {
+				if now.Sub(changed) < settle {
+					continue
+				}
+				// Some copies don't send write events, like on network file systems, so the modification time is checked too
+				info, err := os.Stat(video)
+				if err != nil {
+					delete(pending, video)
+					continue
+				}
+				if modified := info.ModTime(); now.Sub(modified) < settle {
+					pending[video] = modified
+					continue
+				}
+				delete(pending, video)
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					w.process(ctx, call, video, w.languageOf(video), hasSubtitle(video))
+				}()
+			}
+		}
+	}
+}
+
+// watchTree watches a directory and its subdirectories, calling found with the videos already in them if set
+func watchTree(notifier *fsnotify.Watcher, dir string, found func(video string)) error {
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			return notifier.Add(path)
+		}
+		if found != nil && IsVideo(path) {
+			found(path)
+		}
+		return nil
+	})
+}
+
+// polledVideo is a video found by the scans of a file system, with its size and modification time at the last change
+type polledVideo struct {
+	size    int64
+	modTime time.Time
+	changed time.Time
+	// done is true once the video was processed, or when it was there from the first scan
+	done bool
+}
+
+// poll scans a file system every Poll until the context is done, and processes the new videos once unchanged for settle.
+// Failed scans are retried at the next one, as remote file systems come and go
+func (w *Watcher) poll(ctx context.Context, call *call, watched watchedFS, settle time.Duration, wg *sync.WaitGroup) {
+	every := w.Poll
+	if every <= 0 {
+		every = DefaultWatchPoll
+	}
+	videos := map[string]*polledVideo{}
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for first := true; ; first = false {
+		now := time.Now()
+		seen := map[string]bool{}
+		err := fs.WalkDir(watched.fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() || !IsVideo(name) {
+				return err
+			}
+			info, err := entry.Info()
+			if err != nil {
+				return nil
+			}
+			seen[name] = true
+			video, ok := videos[name]
+			switch {
+			case !ok:
+				videos[name] = &polledVideo{size: info.Size(), modTime: info.ModTime(), changed: now, done: first}
+			case video.size != info.Size() || !video.modTime.Equal(info.ModTime()):
+				video.size, video.modTime, video.changed = info.Size(), info.ModTime(), now
+			case !video.done && now.Sub(video.changed) >= settle && now.Sub(info.ModTime()) >= settle:
+				video.done = true
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					w.process(ctx, call, name, watched.lang, hasSubtitleIn(watched.fsys, name))
+				}()
+			}
+			return nil
+		})
+		if err != nil {
+			call.warnf("Error scanning file system: %v", err)
+		} else {
+			// Deleted videos are forgotten, so that they are processed again if they come back
+			for name := range videos {
+				if !seen[name] {
+					delete(videos, name)
+				}
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// process searches and saves the best subtitle of a new video in a language, unless it already has a subtitle
+func (w *Watcher) process(ctx context.Context, call *call, video, lang string, hasSubtitle bool) {
+	if hasSubtitle {
+		call.infof("Skipping %v, it already has a subtitle", video)
+		return
+	}
+	call.infof("Searching %v subtitle of new video %v", lang, video)
+	event := w.client.saveBest(ctx, video, video, lang, w.Save)
+	if event.Err != nil {
+		call.warnf("Unable to get the subtitle of %v: %v", video, event.Err)
+	}
+	if w.OnEvent != nil {
+		w.eventMu.Lock()
+		defer w.eventMu.Unlock()
+		w.OnEvent(event)
+	}
+}
+
+// saveBest searches the best subtitle of a video in a language and saves it with save, or next to the video if nil.
+// The search is usually the video itself, or its original release name.
+func (c *Client) saveBest(ctx context.Context, video, search, lang string, save func(ctx context.Context, video, name string, s Subtitle) (string, error)) WatchEvent {
+	event := WatchEvent{Video: video}
+	event.Name, event.Subtitle, event.Err = c.SearchBestContext(ctx, search, lang)
+	if event.Err != nil {
+		return event
+	}
+	if save != nil {
+		event.Path, event.Err = save(ctx, video, event.Name, event.Subtitle)
+	} else {
+		event.Path, event.Err = event.Subtitle.DownloadAlongsideContext(ctx, video, true)
+	}
+	return event
+}
+
+// hasSubtitle checks whether a video has a subtitle next to it, like "Show.S01E01.srt" or "Show.S01E01.en.srt" for "Show.S01E01.mkv"
+func hasSubtitle(video string) bool {
+	entries, err := os.ReadDir(filepath.Dir(video))
+	if err != nil {
+		return false
+	}
+	return subtitleAmong(entries, filepath.Base(video))
+}
+
+// hasSubtitleIn checks whether a video of a file system has a subtitle next to it, see hasSubtitle
+func hasSubtitleIn(fsys fs.FS, video string) bool {
+	entries, err := fs.ReadDir(fsys, path.Dir(video))
+	if err != nil {
+		return false
+	}
+	return subtitleAmong(entries, path.Base(video))
+}
+
+// subtitleAmong checks whether the entries of the directory of a video, given by its file name, have a subtitle of it
+func subtitleAmong(entries []fs.DirEntry, video string) bool {
+	base := strings.TrimSuffix(video, filepath.Ext(video)) + "."
+	for _, entry := range entries {
+		if name := entry.Name(); strings.HasPrefix(name, base) && IsSubtitle(name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package addic7ed_test
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+func TestWatcher(t *testing.T) {
+	const srt = "1\n00:00:01,000 --> 00:00:02,000\nHello\n"
+	handler := episodeHandler(t, nil)
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/original/") || strings.HasPrefix(r.URL.Path, "/updated/") {
+			w.Write([]byte(srt))
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})}}))
+
+	dir := t.TempDir()
+	french := filepath.Join(dir, "French")
+	assert.NoError(t, os.Mkdir(french, 0755))
+	// A video that already has a subtitle is skipped
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "Shameless.US.S08E11.720p.HDTV.x264-BATV.srt"), []byte(srt), 0644))
+
+	var mu sync.Mutex
+	var events []addic7ed.WatchEvent
+	w := c.NewWatcher()
+	w.Settle = 50 * time.Millisecond
+	w.OnEvent = func(event addic7ed.WatchEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	}
+	assert.NoError(t, w.Add(dir, "English"))
+	assert.NoError(t, w.Add(french, "fr"))
+	assert.Error(t, w.Add(filepath.Join(dir, "missing"), "English"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- w.Run(ctx)
+	}()
+	// Give the watcher the time to watch the directories
+	time.Sleep(50 * time.Millisecond)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "Shameless.US.S08E11.720p.HDTV.x264-BATV.mkv"), []byte("video"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(french, "Shameless.US.S08E11.720p.HDTV.x264-BATV.mkv"), []byte("video"), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a video"), 0644))
+
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(10 * time.Millisecond) {
+		mu.Lock()
+		n := len(events)
+		mu.Unlock()
+		if n > 0 {
+			break
+		}
+	}
+	// Wait for any unexpected event
+	time.Sleep(200 * time.Millisecond)
+	cancel()
+	assert.NoError(t, <-done)
+
+	if assert.Len(t, events, 1) {
+		assert.NoError(t, events[0].Err)
+		assert.Equal(t, "French", events[0].Subtitle.Language)
+		assert.Equal(t, filepath.Join(french, "Shameless.US.S08E11.720p.HDTV.x264-BATV.fr.srt"), events[0].Path)
+		content, err := os.ReadFile(events[0].Path)
+		assert.NoError(t, err)
+		assert.Equal(t, srt, string(content))
+	}
+}
+
+func TestWatcherOfFileSystem(t *testing.T) {
+	const srt = "1\n00:00:01,000 --> 00:00:02,000\nHello\n"
+	handler := episodeHandler(t, nil)
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/original/") || strings.HasPrefix(r.URL.Path, "/updated/") {
+			w.Write([]byte(srt))
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})}}))
+
+	dir := t.TempDir()
+	assert.NoError(t, os.Mkdir(filepath.Join(dir, "Shameless"), 0755))
+	// Videos found by the first scan are not new
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "Shameless", "Shameless.US.S08E10.720p.HDTV.x264-BATV.mkv"), []byte("video"), 0644))
+
+	w := c.NewWatcher()
+	assert.Error(t, w.AddFS(os.DirFS(filepath.Join(dir, "missing")), "English"))
+	assert.NoError(t, w.AddFS(os.DirFS(dir), "fr"))
+	assert.EqualError(t, w.Run(context.Background()), "watching a file system needs Save to write the subtitles")
+
+	var mu sync.Mutex
+	saved := map[string]string{}
+	w.Settle, w.Poll = 50*time.Millisecond, 10*time.Millisecond
+	w.Save = func(ctx context.Context, video, name string, s addic7ed.Subtitle) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		saved[video] = s.Language
+		return video + ".srt", nil
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- w.Run(ctx)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "Shameless", "Shameless.US.S08E11.720p.HDTV.x264-BATV.mkv"), []byte("video"), 0644))
+	// A video that already has a subtitle is skipped
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "Shameless.US.S08E12.720p.HDTV.x264-BATV.en.srt"), []byte(srt), 0644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "Shameless.US.S08E12.720p.HDTV.x264-BATV.mkv"), []byte("video"), 0644))
+
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(10 * time.Millisecond) {
+		mu.Lock()
+		n := len(saved)
+		mu.Unlock()
+		if n > 0 {
+			break
+		}
+	}
+	// Wait for any unexpected save
+	time.Sleep(200 * time.Millisecond)
+	cancel()
+	assert.NoError(t, <-done)
+	assert.Equal(t, map[string]string{"Shameless/Shameless.US.S08E11.720p.HDTV.x264-BATV.mkv": "French"}, saved)
+}
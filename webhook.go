@@ -0,0 +1,141 @@
+package addic7ed
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path/filepath"
+)
+
+// sonarrPayload is the part of the payload of Sonarr webhooks used to find the file of the episode
+type sonarrPayload struct {
+	EventType string `json:"eventType"`
+	Series    struct {
+		Path string `json:"path"`
+	} `json:"series"`
+	EpisodeFile struct {
+		RelativePath string `json:"relativePath"`
+		Path         string `json:"path"`
+		SceneName    string `json:"sceneName"`
+	} `json:"episodeFile"`
+}
+
+// SonarrWebhook is an HTTP handler of the webhooks of Sonarr, downloading the best subtitle of each imported episode next to its file.
+// Declare it in Sonarr as a "Webhook" connection triggered "On Import" and "On Upgrade", with the POST method.
+// Episodes are searched by their scene name when Sonarr knows it, as renamed files like "Show - S01E01 - Title.mkv" lose their release.
+// Other events, like the tests of the connection, are acknowledged with 204 No Content.
+//
+// Subtitles are downloaded before answering, with 200 OK and the result as JSON, 404 Not Found when the episode or its
+// subtitle isn't found, or 502 Bad Gateway on other failures.
+type SonarrWebhook struct {
+	// Language is the language of the subtitles, by name or code
+	Language string
+	// Username and Password, when set, are the credentials of the basic authentication configured in Sonarr
+	Username string
+	Password string
+	// Dirs, when set, are the only directories whose files are handled, like the root folders of Sonarr, so that callers
+	// can't drop subtitles elsewhere
+	Dirs []string
+	// Save saves the best subtitle of a file, and returns the path of the saved file.
+	// When nil, subtitles are downloaded next to their file with DownloadAlongsideContext, with the language suffix.
+	Save func(ctx context.Context, video, name string, s Subtitle) (string, error)
+	// OnEvent, when set, is called with the result of each episode
+	OnEvent func(WatchEvent)
+
+	client *Client
+}
+
+// NewSonarrWebhook creates a handler of Sonarr webhooks downloading subtitles in a language with the client, see SonarrWebhook
+func (c *Client) NewSonarrWebhook(lang string) *SonarrWebhook {
+	return &SonarrWebhook{Language: lang, client: c}
+}
+
+// webhookResult is the JSON answer to a webhook
+type webhookResult struct {
+	File     string    `json:"file"`
+	Episode  string    `json:"episode,omitempty"`
+	Subtitle string    `json:"subtitle,omitempty"`
+	Path     string    `json:"path,omitempty"`
+	Error    string    `json:"error,omitempty"`
+	Code     ErrorCode `json:"code,omitempty"`
+}
+
+func (h *SonarrWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "only POST is allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.Username != "" || h.Password != "" {
+		username, password, ok := r.BasicAuth()
+		if !ok || subtle.ConstantTimeCompare([]byte(username), []byte(h.Username)) != 1 || subtle.ConstantTimeCompare([]byte(password), []byte(h.Password)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Basic realm="addic7ed"`)
+			http.Error(w, "invalid credentials", http.StatusUnauthorized)
+			return
+		}
+	}
+	var payload sonarrPayload
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20)).Decode(&payload); err != nil {
+		http.Error(w, "invalid payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if payload.EventType != "Download" {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	file := payload.EpisodeFile.Path
+	if file == "" && payload.Series.Path != "" && payload.EpisodeFile.RelativePath != "" {
+		file = filepath.Join(payload.Series.Path, payload.EpisodeFile.RelativePath)
+	}
+	if file == "" || !filepath.IsAbs(file) {
+		http.Error(w, "invalid payload: no absolute path to the episode file", http.StatusBadRequest)
+		return
+	}
+	file = filepath.Clean(file)
+	if !h.allowed(file) {
+		http.Error(w, "file "+file+" is outside of the handled directories", http.StatusForbidden)
+		return
+	}
+
+	call := h.client.newCall(r.Context(), nil)
+	search := file
+	if payload.EpisodeFile.SceneName != "" {
+		search = payload.EpisodeFile.SceneName
+	}
+	call.infof("Searching %v subtitle of %v imported by Sonarr, as %v", h.Language, file, search)
+	event := h.client.saveBest(r.Context(), file, search, h.Language, h.Save)
+	if h.OnEvent != nil {
+		h.OnEvent(event)
+	}
+
+	result := webhookResult{File: file, Episode: event.Name, Path: event.Path}
+	status := http.StatusOK
+	if event.Err != nil {
+		call.warnf("Unable to get the subtitle of %v: %v", file, event.Err)
+		result.Error, result.Code = event.Err.Error(), ErrorCodeOf(event.Err)
+		status = http.StatusBadGateway
+		if errors.Is(event.Err, ErrShowNotFound) || errors.Is(event.Err, ErrNoSubtitlesYet) || errors.Is(event.Err, ErrNoSubtitlesForLanguage) {
+			status = http.StatusNotFound
+		}
+	} else {
+		result.Subtitle = event.Subtitle.String()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(result)
+}
+
+// allowed checks whether a file is inside the handled directories
+func (h *SonarrWebhook) allowed(file string) bool {
+	if len(h.Dirs) == 0 {
+		return true
+	}
+	for _, dir := range h.Dirs {
+		if isInside(filepath.Clean(dir), file) {
+			return true
+		}
+	}
+	return false
+}
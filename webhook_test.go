@@ -0,0 +1,80 @@
+package addic7ed_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/matcornic/addic7ed"
+)
+
+func TestSonarrWebhook(t *testing.T) {
+	const srt = "1\n00:00:01,000 --> 00:00:02,000\nHello\n"
+	handler := episodeHandler(t, nil)
+	c := addic7ed.New(addic7ed.WithHTTPClient(&http.Client{Transport: handlerTransport{http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.Contains(r.URL.Query().Get("search"), "Unknown"):
+			w.Write([]byte("<html><body>Nothing found</body></html>"))
+		case strings.HasPrefix(r.URL.Path, "/original/"), strings.HasPrefix(r.URL.Path, "/updated/"):
+			w.Write([]byte(srt))
+		default:
+			handler.ServeHTTP(w, r)
+		}
+	})}}))
+	dir := t.TempDir()
+	writeFiles(t, dir, "Season 8/Shameless (US) - S08E11 - A Gallagher Pedicure.mkv")
+	webhook := c.NewSonarrWebhook("en")
+	webhook.Username, webhook.Password = "sonarr", "secret"
+	webhook.Dirs = []string{dir}
+
+	post := func(payload string, auth bool) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/sonarr", strings.NewReader(payload))
+		if auth {
+			r.SetBasicAuth("sonarr", "secret")
+		}
+		w := httptest.NewRecorder()
+		webhook.ServeHTTP(w, r)
+		return w
+	}
+	download := func(relativePath, sceneName string) string {
+		return fmt.Sprintf(`{"eventType": "Download", "series": {"title": "Shameless (US)", "path": %q},
+			"episodes": [{"seasonNumber": 8, "episodeNumber": 11}],
+			"episodeFile": {"relativePath": %q, "sceneName": %q}, "isUpgrade": false}`, dir, relativePath, sceneName)
+	}
+
+	// Renamed files are searched by their scene name
+	w := post(download("Season 8/Shameless (US) - S08E11 - A Gallagher Pedicure.mkv", "Shameless.US.S08E11.720p.HDTV.x264-BATV"), true)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var result struct {
+		Episode string
+		Path    string
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, "Shameless (US) - 08x11 - A Gallagher Pedicure", result.Episode)
+	assert.Equal(t, filepath.Join(dir, "Season 8", "Shameless (US) - S08E11 - A Gallagher Pedicure.en.srt"), result.Path)
+	content, err := os.ReadFile(result.Path)
+	assert.NoError(t, err)
+	assert.Equal(t, srt, string(content))
+
+	w = post(download("Unknown.Show.S01E01.mkv", ""), true)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), `"code":"show_not_found"`)
+
+	assert.Equal(t, http.StatusNoContent, post(`{"eventType": "Test"}`, true).Code)
+	assert.Equal(t, http.StatusUnauthorized, post(`{"eventType": "Test"}`, false).Code)
+	assert.Equal(t, http.StatusBadRequest, post(`not json`, true).Code)
+	assert.Equal(t, http.StatusBadRequest, post(`{"eventType": "Download"}`, true).Code)
+	assert.Equal(t, http.StatusForbidden, post(`{"eventType": "Download", "episodeFile": {"path": "/etc/Show.S01E01.mkv"}}`, true).Code)
+
+	r := httptest.NewRequest(http.MethodGet, "/sonarr", nil)
+	w = httptest.NewRecorder()
+	webhook.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}